@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/1shoukr/linkvault/internal/config"
+)
+
+const configUsage = "usage: server config check"
+
+// runConfig implements the config subcommand: check prints the loaded settings with secrets
+// redacted, then every validation problem, and fails if there are any
+func runConfig(cfg *config.Config, loadErr error, args []string) error {
+	if len(args) != 1 || args[0] != "check" {
+		return fmt.Errorf("%s", configUsage)
+	}
+
+	if err := cfg.Fprint(os.Stdout); err != nil {
+		return err
+	}
+
+	var invalid *config.ValidationError
+	if errors.As(loadErr, &invalid) {
+		fmt.Printf("\n%d problem(s):\n", len(invalid.Problems))
+		for _, problem := range invalid.Problems {
+			fmt.Printf("  - %s\n", problem)
+		}
+		return errors.New("configuration is invalid")
+	}
+	if loadErr != nil {
+		return loadErr
+	}
+	fmt.Println("\nConfiguration OK")
+	return nil
+}
@@ -1,55 +1,123 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/1shoukr/linkvault/internal/app"
 	"github.com/1shoukr/linkvault/internal/config"
-	"github.com/1shoukr/linkvault/internal/middleware"
+	"github.com/1shoukr/linkvault/internal/logging"
+	"github.com/1shoukr/linkvault/internal/migrations"
 	"github.com/1shoukr/linkvault/internal/repository"
-	"github.com/1shoukr/linkvault/internal/routes"
-	"github.com/1shoukr/linkvault/pkg/utils"
+	"github.com/1shoukr/linkvault/internal/tracing"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 )
 
+// tracingFlushTimeout bounds exporting the spans still buffered at exit
+const tracingFlushTimeout = 5 * time.Second
+
 func main() {
+	// Log JSON from the start; the configured level and format apply once config is loaded
+	slog.SetDefault(logging.New(os.Stdout, "info", "json"))
+
 	// Load .env file
 	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using system environment variables")
+		slog.Info("No .env file found, using system environment variables")
+	}
+
+	// Load configuration; `config check` reports problems itself, everything else fails fast
+	cfg, cfgErr := config.Load()
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		if err := runConfig(cfg, cfgErr, os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	var invalid *config.ValidationError
+	if errors.As(cfgErr, &invalid) {
+		slog.Error("Invalid configuration; run `server config check` for details", "problems", invalid.Problems)
+		os.Exit(1)
 	}
+	slog.SetDefault(logging.New(os.Stdout, cfg.LogLevel, cfg.LogFormat))
 
-	// Load configuration
-	cfg := config.Load()
+	// Subcommands
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			fatal("Migration failed", err)
+		}
+		return
+	}
 
-	// Initialize JWT
-	utils.InitJWT(cfg.JWTSecret)
+	// Tracing is a no-op unless OTEL_TRACES_EXPORTER selects an exporter
+	tracerProvider, shutdownTracing, err := tracing.Setup(context.Background(), cfg.TracesExporter, cfg.ServiceName)
+	if err != nil {
+		fatal("Failed to initialize tracing", err)
+	}
 
 	// Initialize database connection
 	db, err := repository.InitDatabase(cfg)
 	if err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
+		fatal("Failed to initialize database", err)
+	}
+
+	// Connect to the read replica, if one is configured
+	replica, err := repository.InitReplica(cfg)
+	if err != nil {
+		fatal("Failed to initialize read replica", err)
 	}
-	defer func() {
-		sqlDB, _ := db.DB()
-		if sqlDB != nil {
-			sqlDB.Close()
+
+	// Apply pending migrations; the advisory lock makes this safe with several replicas booting
+	if cfg.MigrateOnStart {
+		migrator, err := migrations.New(db)
+		if err != nil {
+			fatal("Failed to load migrations", err)
+		}
+		slog.Info("Running database migrations")
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			fatal("Failed to run migrations", err)
 		}
-	}()
+		slog.Info("Database migrations applied", "applied", applied)
+	}
+
+	// Build the application
+	application, err := app.New(app.Deps{Config: cfg, DB: db, Replica: replica, TracerProvider: tracerProvider})
+	if err != nil {
+		fatal("Failed to initialize application", err)
+	}
 
 	// Set Gin mode based on environment
 	if cfg.Env == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	// Initialize Gin router
-	router := gin.Default()
-	// Setup CORS middleware
-	router.Use(middleware.CORS(cfg))
-	// Setup routes
-	routes.SetupRoutes(router, db)
-
-	log.Printf("Server starting on port %s", cfg.Port)
-	if err := router.Run(":" + cfg.Port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	// Serve until SIGINT/SIGTERM, then drain requests and stop workers before closing the DB
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	serveErr := application.Serve(ctx)
+	if err := application.Close(); err != nil {
+		slog.Error("Failed to close database connections", "error", err)
+	}
+	flushCtx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+	defer cancel()
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
 	}
+	if serveErr != nil {
+		fatal("Server stopped", serveErr)
+	}
+}
+
+// fatal logs err and exits with a failure status
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
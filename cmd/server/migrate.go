@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/1shoukr/linkvault/internal/config"
+	"github.com/1shoukr/linkvault/internal/migrations"
+	"github.com/1shoukr/linkvault/internal/repository"
+)
+
+const migrateUsage = "usage: server migrate [up | down [steps] | status]"
+
+// runMigrate implements the migrate subcommand: up applies pending migrations, down rolls back
+// the last one (or the given number), and status lists every migration and when it was applied
+func runMigrate(cfg *config.Config, args []string) error {
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+	steps := 1
+	if command == "down" && len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid step count %q\n%s", args[1], migrateUsage)
+		}
+		steps = n
+	}
+	if command != "up" && command != "down" && command != "status" {
+		return fmt.Errorf("unknown migrate command %q\n%s", command, migrateUsage)
+	}
+
+	db, err := repository.InitDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if sqlDB, _ := db.DB(); sqlDB != nil {
+			sqlDB.Close()
+		}
+	}()
+
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migration(s)\n", applied)
+	case "down":
+		rolledBack, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Rolled back %d migration(s)\n", rolledBack)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Printf("%04d  %-40s  %s\n", status.Version, status.Name, applied)
+		}
+	}
+	return nil
+}
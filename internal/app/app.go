@@ -0,0 +1,399 @@
+// Package app wires the API together: it owns the configuration, database, token issuer,
+// mailer, clock and background workers, and builds every service, controller and the router
+// from them. Tests can build an App with fakes in place of any dependency.
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/1shoukr/linkvault/internal/checker"
+	"github.com/1shoukr/linkvault/internal/clock"
+	"github.com/1shoukr/linkvault/internal/config"
+	"github.com/1shoukr/linkvault/internal/controllers"
+	"github.com/1shoukr/linkvault/internal/health"
+	"github.com/1shoukr/linkvault/internal/metrics"
+	"github.com/1shoukr/linkvault/internal/middleware"
+	"github.com/1shoukr/linkvault/internal/migrations"
+	"github.com/1shoukr/linkvault/internal/netguard"
+	"github.com/1shoukr/linkvault/internal/notify"
+	"github.com/1shoukr/linkvault/internal/repository"
+	"github.com/1shoukr/linkvault/internal/routes"
+	"github.com/1shoukr/linkvault/internal/services"
+	"github.com/1shoukr/linkvault/internal/tracing"
+	"github.com/1shoukr/linkvault/internal/workers"
+	"github.com/1shoukr/linkvault/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"gorm.io/gorm"
+)
+
+// Stores are the persistence dependencies of the services
+type Stores struct {
+	Users    repository.UserStore
+	Auth     repository.AuthStore
+	Links    repository.LinkStore
+	Checks   repository.CheckStore
+	Alerts   repository.AlertStore
+	Webhooks repository.WebhookStore
+	Uptime   repository.UptimeStore
+}
+
+// GormStores returns the Postgres-backed stores. Statistics and uptime reports read from
+// replica when it is not nil.
+func GormStores(db, replica *gorm.DB) Stores {
+	return Stores{
+		Users:    repository.NewUserRepository(db),
+		Auth:     repository.NewAuthRepository(db),
+		Links:    repository.NewLinkRepository(db, replica),
+		Checks:   repository.NewCheckRepository(db),
+		Alerts:   repository.NewAlertRepository(db),
+		Webhooks: repository.NewWebhookRepository(db),
+		Uptime:   repository.NewUptimeRepository(db, replica),
+	}
+}
+
+// withDefaults fills every nil store in s from defaults
+func (s Stores) withDefaults(defaults Stores) Stores {
+	if s.Users == nil {
+		s.Users = defaults.Users
+	}
+	if s.Auth == nil {
+		s.Auth = defaults.Auth
+	}
+	if s.Links == nil {
+		s.Links = defaults.Links
+	}
+	if s.Checks == nil {
+		s.Checks = defaults.Checks
+	}
+	if s.Alerts == nil {
+		s.Alerts = defaults.Alerts
+	}
+	if s.Webhooks == nil {
+		s.Webhooks = defaults.Webhooks
+	}
+	if s.Uptime == nil {
+		s.Uptime = defaults.Uptime
+	}
+	return s
+}
+
+// missing names the stores left nil in s
+func (s Stores) missing() []string {
+	var names []string
+	for _, store := range []struct {
+		name  string
+		unset bool
+	}{
+		{"Users", s.Users == nil},
+		{"Auth", s.Auth == nil},
+		{"Links", s.Links == nil},
+		{"Checks", s.Checks == nil},
+		{"Alerts", s.Alerts == nil},
+		{"Webhooks", s.Webhooks == nil},
+		{"Uptime", s.Uptime == nil},
+	} {
+		if store.unset {
+			names = append(names, store.name)
+		}
+	}
+	return names
+}
+
+// Deps are the external dependencies an App is built from. Config is required, and so is either
+// DB or every store: with a DB, any store left nil comes from GormStores(DB, Replica). The rest
+// default to their production implementations.
+type Deps struct {
+	Config *config.Config
+	DB     *gorm.DB
+	// Replica, when set, serves statistics and uptime reports in place of DB
+	Replica *gorm.DB
+	Stores  Stores
+	Clock   clock.Clock
+	Mailer  notify.Mailer
+	// Notifiers replace the alert channels (email, webhook, Slack, Discord) when set
+	Notifiers []notify.Notifier
+	// CheckTransport replaces the HTTP transport used by link health checks and robots.txt
+	// fetches when set
+	CheckTransport http.RoundTripper
+	// WebhookTransport replaces the HTTP transport used for outbound webhooks and alert webhooks
+	// when set. The default only connects to public addresses.
+	WebhookTransport http.RoundTripper
+	// TracerProvider receives the spans of requests, queries and outbound HTTP calls; nil
+	// disables tracing. Tests can pass an SDK provider with an in-memory span recorder.
+	TracerProvider trace.TracerProvider
+}
+
+// Services are the business logic built by an App
+type Services struct {
+	Users    *services.UserService
+	Auth     *services.AuthService
+	Links    *services.LinkService
+	Checks   *services.CheckService
+	Alerts   *services.AlertService
+	Webhooks *services.WebhookService
+	Uptime   *services.UptimeService
+	Health   *services.HealthService
+}
+
+// App is the assembled application
+type App struct {
+	Config   *config.Config
+	DB       *gorm.DB
+	Replica  *gorm.DB
+	Clock    clock.Clock
+	Tracer   trace.TracerProvider
+	Tokens   *utils.TokenIssuer
+	Mailer   notify.Mailer
+	Services Services
+	Clicks   *services.ClickRecorder
+	// Monitor collects the heartbeats of the background workers for readiness probes
+	Monitor *health.Monitor
+	// Metrics are exported on /metrics when METRICS_TOKEN is set
+	Metrics *metrics.Metrics
+
+	workers sync.WaitGroup
+}
+
+// New builds an App from deps
+func New(deps Deps) (*App, error) {
+	cfg := deps.Config
+	if cfg == nil {
+		return nil, errors.New("app: config is required")
+	}
+	if deps.DB != nil {
+		deps.Stores = deps.Stores.withDefaults(GormStores(deps.DB, deps.Replica))
+	} else if missing := deps.Stores.missing(); len(missing) > 0 {
+		return nil, fmt.Errorf("app: without a database every store is required; missing %s", strings.Join(missing, ", "))
+	}
+	if deps.Clock == nil {
+		deps.Clock = clock.Real{}
+	}
+	if deps.Mailer == nil {
+		deps.Mailer = notify.NewMailer(cfg.ResendAPIKey.Value(), cfg.AlertEmailFrom)
+	}
+	// Webhook URLs are user-supplied, so keep them off the server's own network
+	webhookTransport := deps.WebhookTransport
+	if webhookTransport == nil && !cfg.WebhookAllowPrivateNetworks {
+		webhookTransport = netguard.Transport()
+	}
+	// So are link destinations, which the health checker fetches
+	checkTransport := deps.CheckTransport
+	if checkTransport == nil && !cfg.CheckAllowPrivateNetworks {
+		checkTransport = netguard.Transport()
+	}
+	if deps.Notifiers == nil {
+		deps.Notifiers = []notify.Notifier{
+			notify.NewEmailNotifier(deps.Mailer),
+			notify.NewWebhookNotifier(webhookTransport),
+			notify.NewSlackNotifier(),
+			notify.NewDiscordNotifier(),
+		}
+	}
+
+	if deps.TracerProvider == nil {
+		deps.TracerProvider = noop.NewTracerProvider()
+	} else {
+		// The plugin may already be registered by an earlier App built on the same DB
+		for _, db := range []*gorm.DB{deps.DB, deps.Replica} {
+			if db == nil {
+				continue
+			}
+			if err := db.Use(tracing.NewGormPlugin(deps.TracerProvider)); err != nil && !errors.Is(err, gorm.ErrRegistered) {
+				return nil, err
+			}
+		}
+	}
+
+	tokens, err := utils.NewTokenIssuer(cfg.JWTSecret.Value(), utils.DefaultTokenTTL, deps.Clock.Now)
+	if err != nil {
+		return nil, err
+	}
+
+	stores := deps.Stores
+	webhookService := services.NewWebhookService(stores.Webhooks, cfg.WebhookTimeout, tracing.Transport(deps.TracerProvider, webhookTransport))
+	webhookService.AllowPrivateWebhooks = cfg.WebhookAllowPrivateNetworks
+	alertService := services.NewAlertService(stores.Alerts, stores.Users, stores.Links, webhookService, deps.Notifiers...)
+	alertService.AllowPrivateWebhooks = cfg.WebhookAllowPrivateNetworks
+	uptimeService := services.NewUptimeService(stores.Uptime, stores.Links)
+	checkService := services.NewCheckService(stores.Links, stores.Checks, checker.New(checker.Options{
+		Timeout:         cfg.CheckTimeout,
+		MaxHops:         cfg.CheckMaxHops,
+		UserAgents:      cfg.CheckUserAgents,
+		Transport:       tracing.Transport(deps.TracerProvider, checkTransport),
+		HostConcurrency: cfg.CheckHostConcurrency,
+		HostInterval:    cfg.CheckHostInterval,
+	}), alertService, uptimeService, services.CheckSchedule{
+		FreeInterval: cfg.CheckInterval,
+		ProInterval:  cfg.CheckProInterval,
+		MinInterval:  cfg.CheckMinInterval,
+		MaxInterval:  cfg.CheckMaxInterval,
+		FailureRetry: cfg.CheckFailureRetry,
+		DeadAfter:    cfg.CheckDeadAfter,
+	})
+
+	appMetrics := metrics.New()
+	checkService.Metrics = appMetrics
+	checkService.AllowPrivateNetworks = cfg.CheckAllowPrivateNetworks
+	webhookService.Metrics = appMetrics
+
+	monitor := health.NewMonitor()
+	healthService := services.NewHealthService(nil, nil, monitor)
+	if deps.DB != nil {
+		sqlDB, err := deps.DB.DB()
+		if err != nil {
+			return nil, err
+		}
+		appMetrics.RegisterDB(sqlDB, "primary")
+		migrator, err := migrations.New(deps.DB)
+		if err != nil {
+			return nil, err
+		}
+		healthService = services.NewHealthService(sqlDB, migrator, monitor)
+	}
+	if deps.Replica != nil {
+		replicaDB, err := deps.Replica.DB()
+		if err != nil {
+			return nil, err
+		}
+		appMetrics.RegisterDB(replicaDB, "replica")
+		healthService.Replica = replicaDB
+	}
+
+	linkService := services.NewLinkService(stores.Links, stores.Users, webhookService, tokens)
+	linkService.AllowPrivateDestinations = cfg.CheckAllowPrivateNetworks
+	clicks := services.NewClickRecorder(linkService)
+	appMetrics.RegisterClickQueue(clicks.QueueDepth)
+	return &App{
+		Config:  cfg,
+		DB:      deps.DB,
+		Replica: deps.Replica,
+		Clock:   deps.Clock,
+		Tracer:  deps.TracerProvider,
+		Tokens:  tokens,
+		Mailer:  deps.Mailer,
+		Services: Services{
+			Users:    services.NewUserService(stores.Users),
+			Auth:     services.NewAuthService(stores.Auth, tokens, deps.Clock),
+			Links:    linkService,
+			Checks:   checkService,
+			Alerts:   alertService,
+			Webhooks: webhookService,
+			Uptime:   uptimeService,
+			Health:   healthService,
+		},
+		Clicks:  clicks,
+		Monitor: monitor,
+		Metrics: appMetrics,
+	}, nil
+}
+
+// Router builds the Gin engine serving the API
+func (a *App) Router() *gin.Engine {
+	redirect := controllers.NewRedirectController(a.Services.Links, a.Clicks, a.Metrics)
+	redirect.TrustCountryHeaders = a.Config.TrustProxyCountryHeaders
+
+	router := gin.New()
+	router.Use(
+		otelgin.Middleware(a.Config.ServiceName,
+			otelgin.WithTracerProvider(a.Tracer),
+			otelgin.WithPropagators(tracing.Propagator),
+			otelgin.WithGinFilter(middleware.NotProbe),
+		),
+		middleware.RequestID(),
+		middleware.RequestLogger(),
+		middleware.Metrics(a.Metrics),
+		middleware.Recovery(),
+		middleware.CORS(a.Config),
+		middleware.Timeout(a.Config.RequestTimeout),
+	)
+	routes.SetupRoutes(router, routes.Handlers{
+		AuthService: a.Services.Auth,
+		User:        controllers.NewUserController(a.Services.Users),
+		Auth:        controllers.NewAuthController(a.Services.Auth),
+		Link:        controllers.NewLinkController(a.Services.Links),
+		Redirect:    redirect,
+		Check:       controllers.NewCheckController(a.Services.Checks),
+		Alert:       controllers.NewAlertController(a.Services.Alerts),
+		Webhook:     controllers.NewWebhookController(a.Services.Webhooks),
+		Uptime:      controllers.NewUptimeController(a.Services.Uptime),
+		Health:      controllers.NewHealthController(a.Services.Health),
+
+		Metrics:      a.Metrics.Handler(),
+		MetricsToken: a.Config.MetricsToken.Value(),
+	})
+	return router
+}
+
+// Worker is a background loop that runs until its context is cancelled
+type Worker interface {
+	Run(ctx context.Context)
+}
+
+// Workers returns the background workers configured for the App, each registered with the
+// App's Monitor so readiness reports a worker that stops making progress
+func (a *App) Workers() []Worker {
+	cfg := a.Config
+
+	lifecycle := workers.NewLinkLifecycleWorker(a.Services.Links, cfg.LinkLifecycleInterval, cfg.LinkArchiveAfter)
+	lifecycle.Heartbeat = a.Monitor.Register("link_lifecycle", cfg.LinkLifecycleInterval)
+	checks := workers.NewLinkCheckWorker(a.Services.Checks, cfg.CheckPollInterval, cfg.CheckBatchSize, cfg.CheckConcurrency)
+	checks.Heartbeat = a.Monitor.Register("link_checker", cfg.CheckPollInterval)
+	digests := workers.NewAlertDigestWorker(a.Services.Alerts, cfg.AlertDigestPollInterval)
+	digests.Heartbeat = a.Monitor.Register("alert_digest", cfg.AlertDigestPollInterval)
+	rollups := workers.NewUptimeRollupWorker(a.Services.Uptime, cfg.UptimeRollupInterval)
+	rollups.Heartbeat = a.Monitor.Register("uptime_rollup", cfg.UptimeRollupInterval)
+	deliveries := workers.NewWebhookDeliveryWorker(a.Services.Webhooks, cfg.WebhookPollInterval, cfg.WebhookBatchSize)
+	deliveries.Heartbeat = a.Monitor.Register("webhook_delivery", cfg.WebhookPollInterval)
+
+	return []Worker{a.Clicks, lifecycle, checks, digests, rollups, deliveries}
+}
+
+// StartWorkers runs every background worker until ctx is cancelled
+func (a *App) StartWorkers(ctx context.Context) {
+	for _, worker := range a.Workers() {
+		a.workers.Add(1)
+		go func(worker Worker) {
+			defer a.workers.Done()
+			worker.Run(ctx)
+		}(worker)
+	}
+}
+
+// WaitForWorkers blocks until every started worker has returned, or ctx is done
+func (a *App) WaitForWorkers(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		a.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close releases the database connections, if any
+func (a *App) Close() error {
+	var errs []error
+	for _, db := range []*gorm.DB{a.DB, a.Replica} {
+		if db == nil {
+			continue
+		}
+		sqlDB, err := db.DB()
+		if err == nil {
+			err = sqlDB.Close()
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,147 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/config"
+	"github.com/1shoukr/linkvault/internal/notify"
+	"github.com/1shoukr/linkvault/internal/repository/memory"
+	"github.com/gin-gonic/gin"
+)
+
+func testConfig() *config.Config {
+	return &config.Config{
+		Env:            "test",
+		ServiceName:    "linkvault-test",
+		JWTSecret:      "test-secret",
+		CORSOrigins:    []string{"http://localhost:3000"},
+		FrontendURL:    "http://localhost:3000",
+		RequestTimeout: 5 * time.Second,
+		WebhookTimeout: time.Second,
+	}
+}
+
+// memoryStores returns every store backed by one fresh in-memory database
+func memoryStores() Stores {
+	db := memory.NewDB()
+	return Stores{
+		Users:    memory.NewUserRepository(db),
+		Auth:     memory.NewAuthRepository(db),
+		Links:    memory.NewLinkRepository(db),
+		Checks:   memory.NewCheckRepository(db),
+		Alerts:   memory.NewAlertRepository(db),
+		Webhooks: memory.NewWebhookRepository(db),
+		Uptime:   memory.NewUptimeRepository(db),
+	}
+}
+
+func TestNewRequiresEveryStoreWithoutDB(t *testing.T) {
+	stores := memoryStores()
+	stores.Links = nil
+	stores.Uptime = nil
+
+	_, err := New(Deps{Config: testConfig(), Stores: stores})
+	if err == nil {
+		t.Fatal("New without a DB or a links store succeeded")
+	}
+	if !strings.Contains(err.Error(), "Links, Uptime") {
+		t.Fatalf("New error = %q, want it to name the missing stores", err)
+	}
+}
+
+func TestRouter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	application, err := New(Deps{
+		Config:    testConfig(),
+		Stores:    memoryStores(),
+		Notifiers: []notify.Notifier{},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	router := application.Router()
+
+	serve := func(method, path, body string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := serve(http.MethodGet, "/health", ""); rec.Code != http.StatusOK {
+		t.Fatalf("GET /health = %d, want 200", rec.Code)
+	}
+	if rec := serve(http.MethodGet, "/api/links", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("GET /api/links without a session = %d, want 401", rec.Code)
+	}
+
+	rec := serve(http.MethodPost, "/api/auth/register", `{"email":"router@example.com","password":"correct horse","name":"Router"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST /api/auth/register = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := serve(http.MethodPost, "/api/auth/login", `{"email":"router@example.com","password":"wrong password"}`); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("login with a wrong password = %d, want 401", rec.Code)
+	}
+	rec = serve(http.MethodPost, "/api/auth/login", `{"email":"router@example.com","password":"correct horse"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /api/auth/login = %d: %s", rec.Code, rec.Body)
+	}
+	var session *http.Cookie
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == "token" {
+			session = cookie
+		}
+	}
+	if session == nil {
+		t.Fatal("login did not set the token cookie")
+	}
+
+	rec = serve(http.MethodPost, "/api/links", `{"original_url":"https://example.com/product","title":"Product"}`, session)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST /api/links = %d: %s", rec.Code, rec.Body)
+	}
+	var created struct {
+		Data struct {
+			ID     string `json:"id"`
+			Status string `json:"status"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decoding the created link: %v", err)
+	}
+	if created.Data.ID == "" || created.Data.Status != "active" {
+		t.Fatalf("created link = %+v, want an active link with an ID", created.Data)
+	}
+
+	rec = serve(http.MethodGet, "/api/links", "", session)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), created.Data.ID) {
+		t.Fatalf("GET /api/links = %d %s, want the created link", rec.Code, rec.Body)
+	}
+
+	rec = serve(http.MethodGet, "/r/"+created.Data.ID, "")
+	if rec.Code != http.StatusFound || !strings.HasPrefix(rec.Header().Get("Location"), "https://example.com/product") {
+		t.Fatalf("GET /r/%s = %d to %q, want a redirect to the product", created.Data.ID, rec.Code, rec.Header().Get("Location"))
+	}
+	if rec := serve(http.MethodGet, "/r/not-a-link", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("GET /r/not-a-link = %d, want 404", rec.Code)
+	}
+
+	if rec := serve(http.MethodDelete, "/api/links/"+created.Data.ID, "", session); rec.Code >= 300 {
+		t.Fatalf("DELETE /api/links/%s = %d: %s", created.Data.ID, rec.Code, rec.Body)
+	}
+	if rec := serve(http.MethodGet, "/r/"+created.Data.ID, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("GET /r/%s after delete = %d, want 404", created.Data.ID, rec.Code)
+	}
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+// Server builds the HTTP server for the API with the configured timeouts
+func (a *App) Server() *http.Server {
+	cfg := a.Config
+	return &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           a.Router(),
+		ReadHeaderTimeout: cfg.HTTPReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTPReadTimeout,
+		WriteTimeout:      cfg.HTTPWriteTimeout,
+		IdleTimeout:       cfg.HTTPIdleTimeout,
+	}
+}
+
+// Serve runs the HTTP server and background workers until ctx is cancelled (e.g. on SIGTERM),
+// then shuts down within the configured deadline: in-flight requests are drained first so
+// their clicks are queued, then workers are cancelled and queued clicks flushed.
+func (a *App) Serve(ctx context.Context) error {
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	a.StartWorkers(workerCtx)
+
+	server := a.Server()
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("Server starting", "port", a.Config.Port)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	var err error
+	select {
+	case err = <-serveErr:
+		// The listener failed; still stop the workers cleanly below
+		err = fmt.Errorf("server failed: %w", err)
+	case <-ctx.Done():
+		slog.Info("Shutting down: draining requests")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.Config.ShutdownTimeout)
+	defer cancel()
+
+	if shutdownErr := server.Shutdown(shutdownCtx); shutdownErr != nil && !errors.Is(shutdownErr, http.ErrServerClosed) {
+		slog.Warn("HTTP server did not drain cleanly", "error", shutdownErr)
+	}
+
+	slog.Info("Shutting down: stopping background workers")
+	stopWorkers()
+	if waitErr := a.WaitForWorkers(shutdownCtx); waitErr != nil {
+		slog.Warn("Background workers did not stop before the shutdown deadline", "error", waitErr)
+	}
+
+	slog.Info("Shutdown complete")
+	return err
+}
@@ -0,0 +1,283 @@
+// Package checker performs link health checks, following redirects hop by hop so the
+// full chain and final destination can be recorded.
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/netguard"
+)
+
+const (
+	// DefaultMaxHops is the number of redirects followed before a chain is flagged as excessive
+	DefaultMaxHops = 10
+	// DefaultTimeout bounds a whole check, including every hop
+	DefaultTimeout = 15 * time.Second
+	// DefaultUserAgent identifies the checker when fetching robots.txt
+	DefaultUserAgent = "Mozilla/5.0 (compatible; LinkVaultBot/1.0; +https://linkvault.app/bot)"
+
+	// maxBodyBytes caps how much of a page is read for content rules; product pages are large
+	maxBodyBytes = 2 << 20
+)
+
+// Hop is a single request in a redirect chain
+type Hop struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+	LatencyMs  int    `json:"latency_ms"`
+}
+
+// Result is the outcome of checking a URL
+type Result struct {
+	URL          string
+	FinalURL     string
+	StatusCode   int // status of the final hop, 0 if no response was received
+	ResponseTime time.Duration
+	Hops         []Hop
+	Healthy      bool
+	SoftBroken   bool          // final destination drifted to a generic page
+	ContentRule  string        // name of the content rule that matched, if any
+	RateLimited  bool          // a host throttled us; the link's health is unknown, not failed
+	RetryAfter   time.Duration // when RateLimited, how long to wait before checking again
+	Error        string        // human-readable reason when unhealthy or rate limited
+}
+
+// Options configures a Checker
+type Options struct {
+	Timeout    time.Duration
+	MaxHops    int
+	UserAgents []string          // rotated per check; defaults to DefaultUserAgents
+	Transport  http.RoundTripper // defaults to netguard.Transport, or http.DefaultTransport if AllowPrivateNetworks
+	// AllowPrivateNetworks lets the default transport reach loopback and private addresses
+	AllowPrivateNetworks bool
+
+	// Per-host politeness, shared by every check made through the Checker
+	HostConcurrency int
+	HostInterval    time.Duration
+	MaxHostWait     time.Duration
+}
+
+// Checker checks URLs for health
+type Checker struct {
+	client     *http.Client
+	maxHops    int
+	userAgents []string
+	hosts      *hostLimiter
+}
+
+// New creates a checker, filling unset options with defaults
+func New(opts Options) *Checker {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxHops <= 0 {
+		opts.MaxHops = DefaultMaxHops
+	}
+	if len(opts.UserAgents) == 0 {
+		opts.UserAgents = DefaultUserAgents
+	}
+	if opts.HostConcurrency <= 0 {
+		opts.HostConcurrency = DefaultHostConcurrency
+	}
+	if opts.HostInterval <= 0 {
+		opts.HostInterval = DefaultHostInterval
+	}
+	if opts.MaxHostWait <= 0 {
+		opts.MaxHostWait = DefaultMaxHostWait
+	}
+	if opts.Transport == nil {
+		// Destinations are user-supplied, so keep checks off the server's own network
+		opts.Transport = netguard.Transport()
+		if opts.AllowPrivateNetworks {
+			opts.Transport = http.DefaultTransport
+		}
+	}
+
+	return &Checker{
+		client: &http.Client{
+			Timeout:   opts.Timeout,
+			Transport: opts.Transport,
+			// Redirects are followed manually so every hop can be recorded
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		maxHops:    opts.MaxHops,
+		userAgents: opts.UserAgents,
+		hosts:      newHostLimiter(opts.HostConcurrency, opts.HostInterval, opts.MaxHostWait, newRobotsCache(opts.Transport)),
+	}
+}
+
+// Check requests rawURL, following redirects up to the hop limit, and applies the built-in
+// merchant rules plus any custom content rules to the final page
+func (c *Checker) Check(ctx context.Context, rawURL string, rules ...ContentRule) *Result {
+	result := &Result{URL: rawURL, FinalURL: rawURL}
+	userAgent := pickUserAgent(c.userAgents)
+	start := time.Now()
+	var queued time.Duration // time spent waiting on host limits, excluded from ResponseTime
+	defer func() { result.ResponseTime = time.Since(start) - queued }()
+
+	visited := make(map[string]bool)
+	current := rawURL
+	var body string
+	for {
+		if visited[current] {
+			result.Error = fmt.Sprintf("redirect loop detected at %s", current)
+			return result
+		}
+		if len(result.Hops) > c.maxHops {
+			result.Error = fmt.Sprintf("too many redirects (more than %d)", c.maxHops)
+			return result
+		}
+		visited[current] = true
+
+		hop, next, page, waited, err := c.fetch(ctx, current, userAgent)
+		queued += waited
+		var limited *RateLimitedError
+		if errors.As(err, &limited) {
+			result.RateLimited = true
+			result.RetryAfter = limited.RetryAfter
+			result.Error = err.Error()
+			return result
+		}
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		result.Hops = append(result.Hops, hop)
+		result.StatusCode = hop.StatusCode
+		result.FinalURL = current
+
+		if next == "" {
+			body = page
+			break
+		}
+		current = next
+	}
+
+	switch {
+	case result.StatusCode < 200 || result.StatusCode >= 400:
+		result.Error = fmt.Sprintf("destination returned HTTP %d", result.StatusCode)
+	case isGenericDestination(rawURL, result.FinalURL):
+		result.SoftBroken = true
+		result.Error = fmt.Sprintf("redirected to a generic page (%s); the product may no longer exist", result.FinalURL)
+	default:
+		if rule := matchContent(result.FinalURL, body, rules); rule != nil {
+			result.ContentRule = rule.Name
+			result.Error = rule.Message
+			break
+		}
+		result.Healthy = true
+	}
+	return result
+}
+
+// fetch performs one request within the host's limits and returns the hop, the next URL for
+// redirects, the page body for HTML responses, and how long it queued for the host
+func (c *Checker) fetch(ctx context.Context, rawURL, userAgent string) (Hop, string, string, time.Duration, error) {
+	hop := Hop{URL: rawURL}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return hop, "", "", 0, fmt.Errorf("invalid URL %s: %w", rawURL, err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.8")
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+
+	host := strings.ToLower(req.URL.Hostname())
+	release, waited, err := c.hosts.acquire(ctx, req.URL.Scheme, host)
+	if err != nil {
+		return hop, "", "", waited, err
+	}
+	defer release()
+
+	public := new(bool)
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) { *public = isPublicConn(info.Conn) },
+	}))
+
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return hop, "", "", waited, fmt.Errorf("request to %s timed out", rawURL)
+		}
+		return hop, "", "", waited, fmt.Errorf("request to %s failed: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	hop.StatusCode = resp.StatusCode
+	if resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusServiceUnavailable && resp.Header.Get("Retry-After") != "") {
+		delay := retryAfter(resp, start)
+		c.hosts.backoff(host, delay)
+		return hop, "", "", waited, &RateLimitedError{Host: host, RetryAfter: delay, Reason: fmt.Sprintf("returned HTTP %d", resp.StatusCode)}
+	}
+
+	location := resp.Header.Get("Location")
+	if isRedirect(resp.StatusCode) && location != "" {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, maxBodyBytes))
+		hop.LatencyMs = int(time.Since(start).Milliseconds())
+		next, err := resolveLocation(rawURL, location)
+		if err != nil {
+			return hop, "", "", waited, fmt.Errorf("invalid redirect from %s: %w", rawURL, err)
+		}
+		return hop, next, "", waited, nil
+	}
+
+	// Content rules are user-defined, so matching them against a page served from the
+	// server's own network would leak what's on it; only public responses are read
+	var page string
+	if *public && strings.Contains(resp.Header.Get("Content-Type"), "html") {
+		page = readPage(resp.Body)
+	} else {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, maxBodyBytes))
+	}
+	hop.LatencyMs = int(time.Since(start).Milliseconds())
+	return hop, "", page, waited, nil
+}
+
+// readPage reads at most maxBodyBytes of a page body
+func readPage(body io.Reader) string {
+	data, _ := io.ReadAll(io.LimitReader(body, maxBodyBytes))
+	return string(data)
+}
+
+// isPublicConn reports whether conn's peer is a public address, as netguard.Transport requires
+func isPublicConn(conn net.Conn) bool {
+	addrPort, err := netip.ParseAddrPort(conn.RemoteAddr().String())
+	return err == nil && netguard.IsPublic(addrPort.Addr())
+}
+
+func isRedirect(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// resolveLocation resolves a possibly relative Location header against the current URL
+func resolveLocation(current, location string) (string, error) {
+	base, err := url.Parse(current)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(location)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(ref).String(), nil
+}
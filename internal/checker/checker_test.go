@@ -0,0 +1,65 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCheckRefusesLoopbackByDefault(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte("<html><title>internal admin</title></html>"))
+	}))
+	defer server.Close()
+
+	result := New(Options{}).Check(t.Context(), server.URL+"/admin")
+	if result.Healthy || !strings.Contains(result.Error, "not a public address") {
+		t.Fatalf("Check(%s) = healthy %v, error %q; want it refused as non-public", server.URL, result.Healthy, result.Error)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Fatalf("loopback server received %d requests (including robots.txt), want none", n)
+	}
+
+	result = New(Options{AllowPrivateNetworks: true}).Check(t.Context(), server.URL+"/admin")
+	if !result.Healthy {
+		t.Fatalf("Check with AllowPrivateNetworks = %q, want healthy", result.Error)
+	}
+}
+
+func TestContentRulesSkipNonPublicPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><title>internal admin</title></html>"))
+	}))
+	defer server.Close()
+
+	rule, err := NewTextRule("probe", "internal admin", false, "")
+	if err != nil {
+		t.Fatalf("NewTextRule: %v", err)
+	}
+	result := New(Options{AllowPrivateNetworks: true}).Check(t.Context(), server.URL, rule)
+	if !result.Healthy || result.ContentRule != "" {
+		t.Fatalf("Check = healthy %v, rule %q; want the loopback page's content left unread", result.Healthy, result.ContentRule)
+	}
+}
+
+func TestNewTextRuleCapsPatternLength(t *testing.T) {
+	for _, isRegex := range []bool{false, true} {
+		if _, err := NewTextRule("max", strings.Repeat("a", MaxPatternLength), isRegex, ""); err != nil {
+			t.Fatalf("NewTextRule(regex %v) with %d characters: %v", isRegex, MaxPatternLength, err)
+		}
+		if _, err := NewTextRule("long", strings.Repeat("a", MaxPatternLength+1), isRegex, ""); err == nil {
+			t.Fatalf("NewTextRule(regex %v) with %d characters succeeded", isRegex, MaxPatternLength+1)
+		}
+	}
+}
+
+func TestReadPageCapsBody(t *testing.T) {
+	if page := readPage(strings.NewReader(strings.Repeat("x", maxBodyBytes+1024))); len(page) != maxBodyBytes {
+		t.Fatalf("readPage read %d bytes, want %d", len(page), maxBodyBytes)
+	}
+}
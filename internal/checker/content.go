@@ -0,0 +1,132 @@
+package checker
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// MaxPatternLength caps the length of a user-defined rule's phrase or regular expression
+const MaxPatternLength = 1000
+
+// ContentRule marks a page unhealthy when its HTML matches. Rules are scoped to hosts when
+// Hosts is set; a rule matches if any of its phrases (case-insensitive) or its pattern is found.
+type ContentRule struct {
+	Name    string
+	Hosts   []string // domains the rule applies to, including subdomains; empty means any host
+	Phrases []string
+	Pattern *regexp.Regexp
+	Message string // explanation recorded on the check
+}
+
+// matches reports whether the rule applies to host and matches the page body
+func (r *ContentRule) matches(host, body, lowerBody string) bool {
+	if len(r.Hosts) > 0 {
+		scoped := false
+		for _, domain := range r.Hosts {
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				scoped = true
+				break
+			}
+		}
+		if !scoped {
+			return false
+		}
+	}
+	for _, phrase := range r.Phrases {
+		if strings.Contains(lowerBody, strings.ToLower(phrase)) {
+			return true
+		}
+	}
+	return r.Pattern != nil && r.Pattern.MatchString(body)
+}
+
+// amazonStorefronts lists the Amazon retail domains the Amazon detectors apply to
+var amazonStorefronts = []string{
+	"amazon.com", "amazon.ca", "amazon.com.mx", "amazon.com.br", "amazon.co.uk", "amazon.de", "amazon.fr",
+	"amazon.it", "amazon.es", "amazon.nl", "amazon.se", "amazon.pl", "amazon.com.be", "amazon.com.tr",
+	"amazon.ae", "amazon.sa", "amazon.eg", "amazon.in", "amazon.co.jp", "amazon.sg", "amazon.com.au",
+}
+
+// MerchantRules are the built-in detectors for soft-404 and out-of-stock product pages
+var MerchantRules = []ContentRule{
+	{
+		Name:    "amazon-unavailable",
+		Hosts:   amazonStorefronts,
+		Phrases: []string{"Currently unavailable.", "We don't know when or if this item will be back in stock"},
+		Message: "Amazon lists this product as currently unavailable",
+	},
+	{
+		Name:    "amazon-dog-page",
+		Hosts:   amazonStorefronts,
+		Phrases: []string{"Sorry! We couldn't find that page", "The Web address you entered is not a functioning page on our site"},
+		Message: "Amazon shows its page-not-found page for this product",
+	},
+	{
+		// Shopify storefronts run on custom domains, so this matches the theme markup
+		// instead of the host: a disabled add-to-cart button reading "Sold out"
+		Name:    "shopify-sold-out",
+		Pattern: regexp.MustCompile(`(?is)<button[^>]*name="add"[^>]*\bdisabled\b[^>]*>\s*(<span[^>]*>\s*)?Sold out`),
+		Message: "The Shopify store shows this product as sold out",
+	},
+	{
+		Name:    "meta-out-of-stock",
+		Pattern: regexp.MustCompile(`(?i)<meta[^>]+property="(og|product):availability"[^>]+content="(oos|out of stock)"`),
+		Message: "The product page metadata marks this item as out of stock",
+	},
+	{
+		Name:    "schema-out-of-stock",
+		Pattern: regexp.MustCompile(`(?i)(itemprop="availability"[^>]*(href|content)="https?://schema\.org/(OutOfStock|Discontinued|SoldOut)")|("availability"\s*:\s*"https?://schema\.org/(OutOfStock|Discontinued|SoldOut)")`),
+		Message: "The product page marks this item as out of stock",
+	},
+	{
+		Name:    "soft-404-title",
+		Pattern: regexp.MustCompile(`(?i)<title[^>]*>[^<]*(page not found|404 not found|error 404|product not found|no longer available)[^<]*</title>`),
+		Message: "The page title says it was not found even though it returned a success status",
+	},
+}
+
+// NewTextRule builds a user-defined rule that matches a case-insensitive phrase or, when
+// isRegex is set, a regular expression
+func NewTextRule(name, pattern string, isRegex bool, message string) (ContentRule, error) {
+	rule := ContentRule{Name: name, Message: message}
+	if len(pattern) > MaxPatternLength {
+		return rule, fmt.Errorf("pattern must be at most %d characters", MaxPatternLength)
+	}
+	if isRegex {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return rule, fmt.Errorf("invalid pattern: %w", err)
+		}
+		rule.Pattern = re
+	} else {
+		rule.Phrases = []string{pattern}
+	}
+	if rule.Message == "" {
+		rule.Message = fmt.Sprintf("page content matched rule %q", name)
+	}
+	return rule, nil
+}
+
+// matchContent returns the first rule matching the final page, built-in rules first
+func matchContent(finalURL, body string, custom []ContentRule) *ContentRule {
+	if body == "" {
+		return nil
+	}
+	u, err := url.Parse(finalURL)
+	if err != nil {
+		return nil
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	lowerBody := strings.ToLower(body)
+
+	for _, rules := range [][]ContentRule{MerchantRules, custom} {
+		for i := range rules {
+			if rules[i].matches(host, body, lowerBody) {
+				return &rules[i]
+			}
+		}
+	}
+	return nil
+}
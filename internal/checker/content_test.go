@@ -0,0 +1,66 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchContent(t *testing.T) {
+	custom := mustTextRule(t, "discontinued-banner", "this colour is no longer available")
+
+	tests := []struct {
+		name    string
+		fixture string
+		url     string
+		custom  []ContentRule
+		want    string // rule name, or "" for a healthy page
+	}{
+		{name: "amazon unavailable", fixture: "amazon_unavailable.html", url: "https://www.amazon.com/dp/B000000001", want: "amazon-unavailable"},
+		{name: "amazon storefront subdomain", fixture: "amazon_unavailable.html", url: "https://smile.amazon.de/dp/B000000001", want: "amazon-unavailable"},
+		{name: "amazon dog page", fixture: "amazon_dog_page.html", url: "https://www.amazon.co.uk/dp/B000000002", want: "amazon-dog-page"},
+		{name: "amazon in stock", fixture: "amazon_in_stock.html", url: "https://www.amazon.com/dp/B000000003"},
+		{name: "amazon phrases off amazon", fixture: "amazon_unavailable.html", url: "https://notamazon.com/p/1"},
+		{name: "shopify sold out", fixture: "shopify_sold_out.html", url: "https://shop.example.com/products/tote", want: "shopify-sold-out"},
+		{name: "shopify in stock", fixture: "shopify_in_stock.html", url: "https://shop.example.com/products/tote"},
+		{name: "open graph out of stock", fixture: "og_out_of_stock.html", url: "https://outdoors.example.com/trail-runner-2", want: "meta-out-of-stock"},
+		{name: "json-ld out of stock", fixture: "schema_jsonld_out_of_stock.html", url: "https://home.example.com/desk-lamp", want: "schema-out-of-stock"},
+		{name: "microdata discontinued", fixture: "schema_microdata_discontinued.html", url: "https://electronics.example.com/m2", want: "schema-out-of-stock"},
+		{name: "soft 404 title", fixture: "soft_404.html", url: "https://store.example.com/p/123", want: "soft-404-title"},
+		{name: "healthy product", fixture: "product_in_stock.html", url: "https://home.example.com/desk-lamp"},
+		{name: "custom rule", fixture: "product_in_stock.html", url: "https://home.example.com/desk-lamp", custom: []ContentRule{custom}, want: "discontinued-banner"},
+		{name: "built-in rules first", fixture: "soft_404.html", url: "https://store.example.com/p/123", custom: []ContentRule{custom, mustTextRule(t, "oops", "Oops!")}, want: "soft-404-title"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := os.ReadFile(filepath.Join("testdata", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := ""
+			if rule := matchContent(tt.url, string(body), tt.custom); rule != nil {
+				got = rule.Name
+			}
+			if got != tt.want {
+				t.Errorf("matchContent(%s, %s) = %q, want %q", tt.url, tt.fixture, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchContentEmptyBody(t *testing.T) {
+	if rule := matchContent("https://www.amazon.com/dp/B000000001", "", nil); rule != nil {
+		t.Errorf("matchContent matched %q on an empty body", rule.Name)
+	}
+}
+
+func mustTextRule(t *testing.T, name, pattern string) ContentRule {
+	t.Helper()
+	rule, err := NewTextRule(name, pattern, false, "")
+	if err != nil {
+		t.Fatalf("NewTextRule: %v", err)
+	}
+	return rule
+}
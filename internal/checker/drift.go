@@ -0,0 +1,71 @@
+package checker
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/1shoukr/linkvault/pkg/merchant"
+)
+
+// genericPathPatterns match landing pages merchants fall back to when a product is gone
+var genericPathPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^/?$`),
+	regexp.MustCompile(`^/(index\.html?|home|default\.aspx)/?$`),
+	regexp.MustCompile(`(^|/)(404|not-?found|page-?not-?found|error|unavailable)(/|\.html?)?$`),
+	regexp.MustCompile(`^/(search|s)/?$`),
+	regexp.MustCompile(`^/(collections/all|products)/?$`),
+	regexp.MustCompile(`^/(gp/browse\.html|b)/?$`),
+}
+
+// isGenericDestination reports whether a chain that started at a specific page ended on a
+// generic one (home page, search, 404 or catch-all listing), which means the product is gone
+// even though the final response was successful
+func isGenericDestination(original, final string) bool {
+	if original == final {
+		return false
+	}
+	finalURL, err := url.Parse(final)
+	if err != nil || !isGenericPath(finalURL.Path) {
+		return false
+	}
+
+	intended, known := intendedDestination(original)
+	if !known {
+		return false
+	}
+	intendedURL, err := url.Parse(intended)
+	if err != nil {
+		return false
+	}
+	// A link that pointed at a generic page to begin with hasn't drifted
+	return !isGenericPath(intendedURL.Path)
+}
+
+// intendedDestination returns the page a link was meant to reach. Network wrappers carry it
+// as a deep-link parameter; wrappers without one may legitimately land on a home page, so
+// their intent is unknown.
+func intendedDestination(original string) (string, bool) {
+	result, ok := merchant.Detect(original)
+	if !ok || result.ShortLink {
+		return original, true
+	}
+	if result.Destination != "" {
+		return result.Destination, true
+	}
+	switch result.Network {
+	case merchant.NetworkAmazon, merchant.NetworkShopify:
+		return original, true
+	}
+	return "", false
+}
+
+func isGenericPath(path string) bool {
+	path = strings.ToLower(path)
+	for _, pattern := range genericPathPatterns {
+		if pattern.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultHostConcurrency is how many requests may be in flight to one host at once
+	DefaultHostConcurrency = 2
+	// DefaultHostInterval is the minimum gap between request starts to one host
+	DefaultHostInterval = time.Second
+	// DefaultMaxHostWait is how long a check will queue for a busy host before it is deferred
+	DefaultMaxHostWait = 30 * time.Second
+
+	// defaultRetryAfter is the backoff applied to a 429 that doesn't say how long to wait
+	defaultRetryAfter = time.Minute
+	// maxRetryAfter caps how long a host can ask us to stay away
+	maxRetryAfter = time.Hour
+	// hostIdleTTL is how long an idle host's state is kept
+	hostIdleTTL = time.Hour
+	// maxTrackedHosts triggers pruning of idle host state
+	maxTrackedHosts = 10000
+)
+
+// RateLimitedError reports that a host asked us to slow down, or that we deferred a request
+// to keep within its limits
+type RateLimitedError struct {
+	Host       string
+	RetryAfter time.Duration
+	Reason     string
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("%s %s; retrying in %s", e.Host, e.Reason, e.RetryAfter.Round(time.Second))
+}
+
+// hostState tracks politeness for one host
+type hostState struct {
+	slots        chan struct{} // in-flight request slots
+	nextStart    time.Time     // earliest time the next request may start
+	blockedUntil time.Time     // set from Retry-After; requests are refused until then
+	crawlDelay   time.Duration // from robots.txt
+	robotsAt     time.Time     // when robots.txt was last fetched
+	lastUsed     time.Time
+}
+
+// hostLimiter enforces per-host concurrency, pacing and backoff across all checks
+type hostLimiter struct {
+	mu          sync.Mutex
+	hosts       map[string]*hostState
+	concurrency int
+	interval    time.Duration
+	maxWait     time.Duration
+	robots      *robotsCache
+}
+
+func newHostLimiter(concurrency int, interval, maxWait time.Duration, robots *robotsCache) *hostLimiter {
+	return &hostLimiter{
+		hosts:       make(map[string]*hostState),
+		concurrency: concurrency,
+		interval:    interval,
+		maxWait:     maxWait,
+		robots:      robots,
+	}
+}
+
+// acquire waits for a request slot for the URL's host and returns a release function plus
+// how long it waited. It fails with a RateLimitedError if the host is backing us off or the
+// wait would exceed maxWait.
+func (l *hostLimiter) acquire(ctx context.Context, scheme, host string) (func(), time.Duration, error) {
+	now := time.Now()
+	state, refreshRobots := l.state(host, now)
+	if refreshRobots {
+		delay := l.robots.crawlDelay(ctx, scheme, host)
+		l.mu.Lock()
+		state.crawlDelay = delay
+		l.mu.Unlock()
+	}
+
+	l.mu.Lock()
+	if state.blockedUntil.After(now) {
+		retry := state.blockedUntil.Sub(now)
+		l.mu.Unlock()
+		return nil, 0, &RateLimitedError{Host: host, RetryAfter: retry, Reason: "is rate limiting checks"}
+	}
+	start := state.nextStart
+	if start.Before(now) {
+		start = now
+	}
+	if wait := start.Sub(now); wait > l.maxWait {
+		l.mu.Unlock()
+		return nil, 0, &RateLimitedError{Host: host, RetryAfter: wait, Reason: "has too many checks queued"}
+	}
+	state.nextStart = start.Add(max(l.interval, state.crawlDelay))
+	l.mu.Unlock()
+
+	select {
+	case state.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	}
+	if wait := time.Until(start); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			<-state.slots
+			return nil, 0, ctx.Err()
+		}
+	}
+	return func() { <-state.slots }, time.Since(now), nil
+}
+
+// backoff blocks a host until retryAfter has passed
+func (l *hostLimiter) backoff(host string, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if state, ok := l.hosts[host]; ok {
+		until := time.Now().Add(retryAfter)
+		if until.After(state.blockedUntil) {
+			state.blockedUntil = until
+		}
+	}
+}
+
+// state returns the host's state, creating it if needed, and whether the caller should
+// refresh its robots.txt
+func (l *hostLimiter) state(host string, now time.Time) (*hostState, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state, ok := l.hosts[host]
+	if !ok {
+		if len(l.hosts) >= maxTrackedHosts {
+			l.prune(now)
+		}
+		state = &hostState{slots: make(chan struct{}, l.concurrency)}
+		l.hosts[host] = state
+	}
+	state.lastUsed = now
+
+	refresh := l.robots != nil && now.Sub(state.robotsAt) > robotsTTL
+	if refresh {
+		state.robotsAt = now
+	}
+	return state, refresh
+}
+
+// prune drops idle hosts that aren't backing us off. Callers must hold l.mu.
+func (l *hostLimiter) prune(now time.Time) {
+	for host, state := range l.hosts {
+		if len(state.slots) == 0 && now.Sub(state.lastUsed) > hostIdleTTL && now.After(state.blockedUntil) {
+			delete(l.hosts, host)
+		}
+	}
+}
+
+// retryAfter reads a response's Retry-After header, given in seconds or as an HTTP date,
+// falling back to defaultRetryAfter and capping at maxRetryAfter
+func retryAfter(resp *http.Response, now time.Time) time.Duration {
+	value := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	delay := defaultRetryAfter
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		delay = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(value); err == nil {
+		delay = at.Sub(now)
+	}
+	return min(max(delay, time.Second), maxRetryAfter)
+}
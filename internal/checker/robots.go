@@ -0,0 +1,103 @@
+package checker
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// robotsTTL is how long a host's robots.txt Crawl-delay is trusted before refetching
+	robotsTTL = 24 * time.Hour
+	// maxCrawlDelay caps the Crawl-delay honored, so one host can't stall its links' checks
+	maxCrawlDelay = 30 * time.Second
+	// robotsAgent is the product token matched against robots.txt User-agent lines
+	robotsAgent = "linkvaultbot"
+)
+
+// robotsCache fetches robots.txt to learn a host's Crawl-delay. Disallow rules aren't applied:
+// a check is a single request for a link its owner published, not a crawl.
+type robotsCache struct {
+	client *http.Client
+}
+
+func newRobotsCache(transport http.RoundTripper) *robotsCache {
+	return &robotsCache{client: &http.Client{Timeout: 5 * time.Second, Transport: transport}}
+}
+
+// crawlDelay returns the host's Crawl-delay for our agent (or *), or 0 if it has none or
+// robots.txt can't be read
+func (r *robotsCache) crawlDelay(ctx context.Context, scheme, host string) time.Duration {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+host+"/robots.txt", nil)
+	if err != nil {
+		return 0
+	}
+	req.Header.Set("User-Agent", DefaultUserAgent)
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return 0
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0
+	}
+	return parseCrawlDelay(io.LimitReader(resp.Body, 512<<10))
+}
+
+// parseCrawlDelay extracts the Crawl-delay that applies to robotsAgent, preferring a group
+// naming it over the * group
+func parseCrawlDelay(robots io.Reader) time.Duration {
+	var (
+		agents      []string
+		inRules     bool
+		wildcard    time.Duration
+		specific    time.Duration
+		hasSpecific bool
+	)
+	scanner := bufio.NewScanner(robots)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// Consecutive User-agent lines share one group; a rule line ends the list
+			if inRules {
+				agents, inRules = nil, false
+			}
+			agents = append(agents, strings.ToLower(value))
+		case "crawl-delay":
+			inRules = true
+			seconds, err := strconv.ParseFloat(value, 64)
+			if err != nil || seconds < 0 {
+				continue
+			}
+			delay := time.Duration(seconds * float64(time.Second))
+			for _, agent := range agents {
+				switch {
+				case strings.Contains(agent, robotsAgent):
+					specific, hasSpecific = delay, true
+				case agent == "*":
+					wildcard = delay
+				}
+			}
+		default:
+			inRules = true
+		}
+	}
+
+	delay := wildcard
+	if hasSpecific {
+		delay = specific
+	}
+	return min(delay, maxCrawlDelay)
+}
@@ -1,66 +1,210 @@
+// Package config loads the server configuration from the environment. Load parses every
+// variable into a typed field, reads secrets from files when NAME_FILE is set, and validates
+// each feature's settings, reporting every problem at once so a deploy fails fast instead of
+// surfacing a missing secret at runtime.
 package config
 
 import (
-	"os"
+	"time"
 )
 
+// Config holds every server setting, grouped by feature. Build it with Load, or validate one
+// built in code with Validate; secrets are Secret values so printing a Config never leaks them.
 type Config struct {
 	// Server
 	Port string
-	Env  string
+	Env  string // development, test, staging or production
+
+	// Logging
+	LogLevel  string // debug, info, warn or error
+	LogFormat string // json, or text for local development
+
+	// Metrics
+	MetricsToken Secret // bearer token required by /metrics; empty disables the endpoint
+
+	// Tracing; the OTLP exporter reads its endpoint and headers from OTEL_EXPORTER_OTLP_*
+	TracesExporter string // "otlp", or "none" for no-op tracing
+	ServiceName    string
+
+	// HTTP server timeouts and graceful shutdown
+	HTTPReadHeaderTimeout time.Duration
+	HTTPReadTimeout       time.Duration
+	HTTPWriteTimeout      time.Duration
+	HTTPIdleTimeout       time.Duration
+	ShutdownTimeout       time.Duration // deadline for draining requests and stopping workers
+	RequestTimeout        time.Duration // deadline for a request's handler and its queries; 0 disables
 
 	// Database
-	DatabaseURL string
+	DatabaseURL          Secret
+	MigrateOnStart       bool          // apply pending migrations when the server boots
+	DBSlowQueryThreshold time.Duration // queries slower than this are logged as warnings; 0 disables
+
+	// Database connection pool, applied to the primary and the replica alike
+	DBMaxOpenConns     int
+	DBMaxIdleConns     int
+	DBConnMaxLifetime  time.Duration // recycle connections this old; 0 keeps them forever
+	DBConnMaxIdleTime  time.Duration // close connections idle this long; 0 keeps them
+	DBStatementTimeout time.Duration // Postgres statement_timeout for every session; 0 disables
+
+	// Read replica serving statistics and uptime reports; empty sends every query to the primary
+	DatabaseReplicaURL Secret
 
 	// JWT
-	JWTSecret string
+	JWTSecret Secret
 
 	// OAuth
 	GoogleClientID     string
-	GoogleClientSecret string
+	GoogleClientSecret Secret
 	GoogleCallbackURL  string
 
 	// Email
-	ResendAPIKey string
+	ResendAPIKey   Secret
+	AlertEmailFrom string
 
-	// Stripe
-	StripeSecretKey    string
-	StripeWebhookSecret string
-	StripeProPriceID   string
+	// Stripe; setting any of these, or BillingEnabled, requires all three
+	BillingEnabled      bool
+	StripeSecretKey     Secret
+	StripeWebhookSecret Secret
+	StripeProPriceID    string
 
 	// CORS
-	CORSOrigin  string
+	CORSOrigins []string // origins allowed to make credentialed requests; the first is the default
 	FrontendURL string
 
+	// Geo routing; visitors can set country headers themselves, so they are only read behind
+	// a CDN or proxy that overwrites them. Without it visitors have no country.
+	TrustProxyCountryHeaders bool
+
 	// Cron
-	CronSecret string
+	CronSecret Secret
+
+	// Link lifecycle worker
+	LinkLifecycleInterval time.Duration
+	LinkArchiveAfter      time.Duration // archive links this long after they expire; 0 disables
+
+	// Link health checker
+	CheckInterval     time.Duration // how often a healthy free-plan link is re-checked
+	CheckPollInterval time.Duration // how often the worker looks for due links
+	CheckBatchSize    int
+	CheckConcurrency  int
+	CheckTimeout      time.Duration
+	CheckMaxHops      int
+
+	// Adaptive check scheduling
+	CheckProInterval  time.Duration // how often a healthy Pro link is re-checked
+	CheckMinInterval  time.Duration
+	CheckMaxInterval  time.Duration
+	CheckFailureRetry time.Duration // first recheck after a failed check
+	CheckDeadAfter    time.Duration // back off links that have been failing this long
+
+	// Per-host checker politeness
+	CheckHostConcurrency int           // concurrent requests to any one host
+	CheckHostInterval    time.Duration // minimum gap between request starts to one host
+	CheckUserAgents      []string      // rotated per check; empty uses the checker's defaults
+
+	// Alert digest worker
+	AlertDigestPollInterval time.Duration
+
+	// Uptime reporting
+	UptimeRollupInterval time.Duration
+
+	// Outbound webhooks
+	WebhookPollInterval time.Duration
+	WebhookBatchSize    int
+	WebhookTimeout      time.Duration
+	// WebhookAllowPrivateNetworks lets webhooks reach loopback and private addresses, for
+	// self-hosted installs posting to internal services
+	WebhookAllowPrivateNetworks bool
+	// CheckAllowPrivateNetworks lets link destinations and health checks reach loopback and
+	// private addresses, for self-hosted installs checking internal services
+	CheckAllowPrivateNetworks bool
 }
 
-func Load() *Config {
-	return &Config{
-		Port:               getEnv("PORT", "8080"),
-		Env:                getEnv("ENV", "development"),
-		DatabaseURL:        getEnv("DATABASE_URL", ""),
-		JWTSecret:          getEnv("JWT_SECRET", ""),
-		GoogleClientID:     getEnv("GOOGLE_CLIENT_ID", ""),
-		GoogleClientSecret: getEnv("GOOGLE_CLIENT_SECRET", ""),
-		GoogleCallbackURL:  getEnv("GOOGLE_CALLBACK_URL", ""),
-		ResendAPIKey:       getEnv("RESEND_API_KEY", ""),
-		StripeSecretKey:    getEnv("STRIPE_SECRET_KEY", ""),
-		StripeWebhookSecret: getEnv("STRIPE_WEBHOOK_SECRET", ""),
-		StripeProPriceID:   getEnv("STRIPE_PRO_PRICE_ID", ""),
-		CORSOrigin:         getEnv("CORS_ORIGIN", "http://localhost:3000"),
-		FrontendURL:        getEnv("FRONTEND_URL", "http://localhost:3000"),
-		CronSecret:         getEnv("CRON_SECRET", ""),
+// Load reads the configuration from the environment and validates it. On failure it returns
+// the configuration alongside a *ValidationError listing every problem, so callers such as
+// `config check` can still show what was loaded.
+func Load() (*Config, error) {
+	env := &environment{}
+	cfg := &Config{
+		Port:                 env.string("PORT", "8080"),
+		Env:                  env.string("ENV", "development"),
+		LogLevel:             env.string("LOG_LEVEL", "info"),
+		LogFormat:            env.string("LOG_FORMAT", "json"),
+		MetricsToken:         env.secret("METRICS_TOKEN"),
+		TracesExporter:       env.string("OTEL_TRACES_EXPORTER", "none"),
+		ServiceName:          env.string("OTEL_SERVICE_NAME", "linkvault-api"),
+		DatabaseURL:          env.secret("DATABASE_URL"),
+		MigrateOnStart:       env.bool("MIGRATE_ON_START", true),
+		DBSlowQueryThreshold: env.duration("DB_SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+		DBMaxOpenConns:       env.int("DB_MAX_OPEN_CONNS", 100),
+		DBMaxIdleConns:       env.int("DB_MAX_IDLE_CONNS", 10),
+		DBConnMaxLifetime:    env.duration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+		DBConnMaxIdleTime:    env.duration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+		DBStatementTimeout:   env.duration("DB_STATEMENT_TIMEOUT", 30*time.Second),
+		DatabaseReplicaURL:   env.secret("DATABASE_REPLICA_URL"),
+		JWTSecret:            env.secret("JWT_SECRET"),
+		GoogleClientID:       env.string("GOOGLE_CLIENT_ID", ""),
+		GoogleClientSecret:   env.secret("GOOGLE_CLIENT_SECRET"),
+		GoogleCallbackURL:    env.string("GOOGLE_CALLBACK_URL", ""),
+		ResendAPIKey:         env.secret("RESEND_API_KEY"),
+		BillingEnabled:       env.bool("BILLING_ENABLED", false),
+		StripeSecretKey:      env.secret("STRIPE_SECRET_KEY"),
+		StripeWebhookSecret:  env.secret("STRIPE_WEBHOOK_SECRET"),
+		StripeProPriceID:     env.string("STRIPE_PRO_PRICE_ID", ""),
+		CORSOrigins:          origins(env.list("CORS_ORIGIN", "http://localhost:3000")),
+		FrontendURL:          env.string("FRONTEND_URL", "http://localhost:3000"),
+		CronSecret:           env.secret("CRON_SECRET"),
+		AlertEmailFrom:       env.string("ALERT_EMAIL_FROM", "LinkVault <alerts@linkvault.app>"),
+
+		LinkLifecycleInterval: env.duration("LINK_LIFECYCLE_INTERVAL", time.Minute),
+		LinkArchiveAfter:      env.duration("LINK_ARCHIVE_AFTER", 30*24*time.Hour),
+
+		CheckInterval:     env.duration("CHECK_INTERVAL", 6*time.Hour),
+		CheckPollInterval: env.duration("CHECK_POLL_INTERVAL", time.Minute),
+		CheckBatchSize:    env.int("CHECK_BATCH_SIZE", 100),
+		CheckConcurrency:  env.int("CHECK_CONCURRENCY", 5),
+		CheckTimeout:      env.duration("CHECK_TIMEOUT", 15*time.Second),
+		CheckMaxHops:      env.int("CHECK_MAX_HOPS", 10),
+
+		CheckProInterval:  env.duration("CHECK_PRO_INTERVAL", time.Hour),
+		CheckMinInterval:  env.duration("CHECK_MIN_INTERVAL", 5*time.Minute),
+		CheckMaxInterval:  env.duration("CHECK_MAX_INTERVAL", 7*24*time.Hour),
+		CheckFailureRetry: env.duration("CHECK_FAILURE_RETRY", 5*time.Minute),
+		CheckDeadAfter:    env.duration("CHECK_DEAD_AFTER", 72*time.Hour),
+
+		CheckHostConcurrency: env.int("CHECK_HOST_CONCURRENCY", 2),
+		CheckHostInterval:    env.duration("CHECK_HOST_INTERVAL", time.Second),
+		CheckUserAgents:      env.list("CHECK_USER_AGENTS", ""),
+
+		AlertDigestPollInterval: env.duration("ALERT_DIGEST_POLL_INTERVAL", time.Minute),
+
+		UptimeRollupInterval: env.duration("UPTIME_ROLLUP_INTERVAL", time.Hour),
+
+		WebhookPollInterval: env.duration("WEBHOOK_POLL_INTERVAL", 10*time.Second),
+		WebhookBatchSize:    env.int("WEBHOOK_BATCH_SIZE", 100),
+		WebhookTimeout:      env.duration("WEBHOOK_TIMEOUT", 10*time.Second),
+
+		WebhookAllowPrivateNetworks: env.bool("WEBHOOK_ALLOW_PRIVATE_NETWORKS", false),
+		CheckAllowPrivateNetworks:   env.bool("CHECK_ALLOW_PRIVATE_NETWORKS", false),
+
+		TrustProxyCountryHeaders: env.bool("TRUST_PROXY_COUNTRY_HEADERS", false),
+
+		HTTPReadHeaderTimeout: env.duration("HTTP_READ_HEADER_TIMEOUT", 5*time.Second),
+		HTTPReadTimeout:       env.duration("HTTP_READ_TIMEOUT", 15*time.Second),
+		HTTPWriteTimeout:      env.duration("HTTP_WRITE_TIMEOUT", 30*time.Second),
+		HTTPIdleTimeout:       env.duration("HTTP_IDLE_TIMEOUT", 2*time.Minute),
+		ShutdownTimeout:       env.duration("SHUTDOWN_TIMEOUT", 25*time.Second),
+		RequestTimeout:        env.duration("REQUEST_TIMEOUT", 20*time.Second),
 	}
-}
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+	problems := append(env.problems, cfg.problems()...)
+	if len(problems) > 0 {
+		return cfg, &ValidationError{Problems: problems}
 	}
-	return defaultValue
+	return cfg, nil
 }
 
-
-
+// IsProduction reports whether the server runs in production
+func (c *Config) IsProduction() bool {
+	return c.Env == "production"
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// load runs Load with only vars set, on top of DATABASE_URL and JWT_SECRET which every
+// configuration needs
+func load(t *testing.T, vars map[string]string) (*Config, error) {
+	t.Helper()
+	for _, entry := range os.Environ() {
+		key, _, _ := strings.Cut(entry, "=")
+		t.Setenv(key, "")
+	}
+	t.Setenv("DATABASE_URL", "postgres://linkvault@localhost/linkvault")
+	t.Setenv("JWT_SECRET", "test-secret")
+	for key, value := range vars {
+		t.Setenv(key, value)
+	}
+	return Load()
+}
+
+func TestLoad(t *testing.T) {
+	cfg, err := load(t, map[string]string{
+		"PORT":                  "9090",
+		"CHECK_TIMEOUT":         "45s",
+		"CHECK_BATCH_SIZE":      "25",
+		"MIGRATE_ON_START":      "false",
+		"CORS_ORIGIN":           "https://app.example.com/, https://admin.example.com",
+		"CHECK_USER_AGENTS":     "agent-a,,agent-b",
+		"STRIPE_SECRET_KEY":     "sk_test_123",
+		"STRIPE_WEBHOOK_SECRET": "whsec_123",
+		"STRIPE_PRO_PRICE_ID":   "price_123",
+	})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if cfg.Port != "9090" || cfg.CheckTimeout != 45*time.Second || cfg.CheckBatchSize != 25 || cfg.MigrateOnStart {
+		t.Fatalf("Load parsed PORT %q, CHECK_TIMEOUT %s, CHECK_BATCH_SIZE %d, MIGRATE_ON_START %v",
+			cfg.Port, cfg.CheckTimeout, cfg.CheckBatchSize, cfg.MigrateOnStart)
+	}
+	if want := []string{"https://app.example.com", "https://admin.example.com"}; !slices.Equal(cfg.CORSOrigins, want) {
+		t.Fatalf("CORSOrigins = %q, want %q", cfg.CORSOrigins, want)
+	}
+	if want := []string{"agent-a", "agent-b"}; !slices.Equal(cfg.CheckUserAgents, want) {
+		t.Fatalf("CheckUserAgents = %q, want %q", cfg.CheckUserAgents, want)
+	}
+	if cfg.Env != "development" || cfg.CheckInterval != 6*time.Hour || cfg.BillingEnabled {
+		t.Fatalf("defaults: ENV %q, CHECK_INTERVAL %s, BILLING_ENABLED %v", cfg.Env, cfg.CheckInterval, cfg.BillingEnabled)
+	}
+	if cfg.StripeSecretKey.Value() != "sk_test_123" {
+		t.Fatalf("StripeSecretKey = %q, want the variable's value", cfg.StripeSecretKey.Value())
+	}
+}
+
+func TestLoadValidationErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		vars map[string]string
+		want string
+	}{
+		{"malformed duration", map[string]string{"CHECK_TIMEOUT": "15"}, `CHECK_TIMEOUT: "15" is not a duration`},
+		{"malformed integer", map[string]string{"CHECK_BATCH_SIZE": "lots"}, `CHECK_BATCH_SIZE: "lots" is not an integer`},
+		{"malformed boolean", map[string]string{"BILLING_ENABLED": "sure"}, `BILLING_ENABLED: "sure" is not a boolean`},
+		{"missing database", map[string]string{"DATABASE_URL": ""}, "DATABASE_URL: required"},
+		{"unknown environment", map[string]string{"ENV": "prod"}, `ENV: "prod" is not one of`},
+		{"short production secret", map[string]string{"ENV": "production", "RESEND_API_KEY": "re_123"}, "JWT_SECRET: must be at least 32 characters"},
+		{"production without email", map[string]string{"ENV": "production", "JWT_SECRET": strings.Repeat("s", 32)}, "RESEND_API_KEY: required in production"},
+		{"origin with a path", map[string]string{"CORS_ORIGIN": "https://app.example.com/app"}, `CORS_ORIGIN: "https://app.example.com/app" is not an origin`},
+		{"origin without a scheme", map[string]string{"CORS_ORIGIN": "app.example.com"}, `CORS_ORIGIN: "app.example.com" is not an origin`},
+		{"request outlives the write timeout", map[string]string{"REQUEST_TIMEOUT": "30s"}, "REQUEST_TIMEOUT: 30s must be shorter than HTTP_WRITE_TIMEOUT"},
+		{"half-configured Stripe", map[string]string{"STRIPE_SECRET_KEY": "sk_test_123"}, "STRIPE_WEBHOOK_SECRET: required when billing is configured"},
+		{"billing without Stripe", map[string]string{"BILLING_ENABLED": "true"}, "STRIPE_SECRET_KEY: required when billing is configured"},
+		{"half-configured Google sign-in", map[string]string{"GOOGLE_CLIENT_ID": "client"}, "GOOGLE_CLIENT_SECRET: required when Google sign-in is configured"},
+		{"inverted check intervals", map[string]string{"CHECK_MIN_INTERVAL": "2h", "CHECK_MAX_INTERVAL": "1h"}, "CHECK_MIN_INTERVAL: 2h0m0s is longer than CHECK_MAX_INTERVAL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := load(t, tt.vars)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Load error = %v, want a *ValidationError", err)
+			}
+			if cfg == nil {
+				t.Fatal("Load returned no configuration alongside its validation error")
+			}
+			if !slices.ContainsFunc(validationErr.Problems, func(p string) bool { return strings.Contains(p, tt.want) }) {
+				t.Fatalf("Load problems = %q, want one containing %q", validationErr.Problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadSecretsFromFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwt_secret")
+	if err := os.WriteFile(path, []byte("from-a-file\n"), 0o600); err != nil {
+		t.Fatalf("writing the secret file: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		vars       map[string]string
+		wantSecret string
+		wantErr    string
+	}{
+		{"read and trimmed", map[string]string{"JWT_SECRET": "", "JWT_SECRET_FILE": path}, "from-a-file", ""},
+		{"missing file", map[string]string{"JWT_SECRET": "", "JWT_SECRET_FILE": path + ".missing"}, "", "JWT_SECRET_FILE: "},
+		{"both set", map[string]string{"JWT_SECRET_FILE": path}, "test-secret", "JWT_SECRET: set either JWT_SECRET or JWT_SECRET_FILE, not both"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := load(t, tt.vars)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Load: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Load error = %v, want one containing %q", err, tt.wantErr)
+			}
+			if cfg.JWTSecret.Value() != tt.wantSecret {
+				t.Fatalf("JWTSecret = %q, want %q", cfg.JWTSecret.Value(), tt.wantSecret)
+			}
+		})
+	}
+}
+
+func TestFprintRedactsSecrets(t *testing.T) {
+	cfg, err := load(t, map[string]string{
+		"JWT_SECRET":            "jwt-value-not-for-logs",
+		"METRICS_TOKEN":         "metrics-value-not-for-logs",
+		"STRIPE_SECRET_KEY":     "sk_value_not_for_logs",
+		"STRIPE_WEBHOOK_SECRET": "whsec_value_not_for_logs",
+		"STRIPE_PRO_PRICE_ID":   "price_123",
+	})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := cfg.Fprint(&buf); err != nil {
+		t.Fatalf("Fprint: %v", err)
+	}
+	out := buf.String()
+	for _, secret := range []string{"jwt-value", "metrics-value", "sk_value", "whsec_value", "postgres://"} {
+		if strings.Contains(out, secret) {
+			t.Fatalf("Fprint leaked %q:\n%s", secret, out)
+		}
+	}
+	for _, line := range []string{"JWTSecret", "[redacted]", "StripeProPriceID", "price_123", "CORSOrigins"} {
+		if !strings.Contains(out, line) {
+			t.Fatalf("Fprint output has no %q:\n%s", line, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if name, value, _ := strings.Cut(line, " "); name == "CronSecret" && strings.TrimSpace(value) != "" {
+			t.Fatalf("Fprint printed unset CronSecret as %q, want it empty", strings.TrimSpace(value))
+		}
+	}
+}
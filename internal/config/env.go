@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// environment reads typed variables, collecting a problem for every malformed value instead of
+// silently falling back to the default
+type environment struct {
+	problems []string
+}
+
+func (e *environment) problem(format string, args ...any) {
+	e.problems = append(e.problems, fmt.Sprintf(format, args...))
+}
+
+func (e *environment) string(key, defaultValue string) string {
+	if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func (e *environment) int(key string, defaultValue int) int {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		e.problem("%s: %q is not an integer", key, value)
+		return defaultValue
+	}
+	return n
+}
+
+func (e *environment) bool(key string, defaultValue bool) bool {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		e.problem("%s: %q is not a boolean", key, value)
+		return defaultValue
+	}
+	return b
+}
+
+func (e *environment) duration(key string, defaultValue time.Duration) time.Duration {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		e.problem("%s: %q is not a duration (e.g. 30s, 5m, 6h)", key, value)
+		return defaultValue
+	}
+	return d
+}
+
+// list splits a comma-separated variable, dropping empty entries
+func (e *environment) list(key, defaultValue string) []string {
+	var values []string
+	for _, value := range strings.Split(e.string(key, defaultValue), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// origins drops the trailing slash from each origin, since browsers send Origin without one
+func origins(values []string) []string {
+	for i, value := range values {
+		values[i] = strings.TrimSuffix(value, "/")
+	}
+	return values
+}
+
+// secret reads key, or the file named by key_FILE (e.g. a mounted Docker or Kubernetes secret),
+// trimming the trailing newline such files usually end with
+func (e *environment) secret(key string) Secret {
+	value := os.Getenv(key)
+	path := strings.TrimSpace(os.Getenv(key + "_FILE"))
+	if path == "" {
+		return Secret(strings.TrimSpace(value))
+	}
+	if value != "" {
+		e.problem("%s: set either %s or %s_FILE, not both", key, key, key)
+		return Secret(strings.TrimSpace(value))
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		e.problem("%s_FILE: %v", key, err)
+		return ""
+	}
+	return Secret(strings.TrimSpace(string(contents)))
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+// Fprint writes every setting as "Name  value", one per line. Secrets are redacted.
+func (c *Config) Fprint(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	value := reflect.ValueOf(*c)
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		var formatted string
+		switch v := field.Interface().(type) {
+		case []string:
+			formatted = strings.Join(v, ", ")
+		default:
+			// Secret implements fmt.Stringer, so set secrets print as [redacted]
+			formatted = fmt.Sprint(v)
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", value.Type().Field(i).Name, formatted)
+	}
+	return tw.Flush()
+}
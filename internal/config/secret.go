@@ -0,0 +1,46 @@
+package config
+
+import (
+	"encoding/json"
+	"log/slog"
+)
+
+// redacted replaces a set secret wherever it would be printed
+const redacted = "[redacted]"
+
+// Secret is a configuration value that must never be printed or logged. Formatting, JSON and
+// slog all render it as "[redacted]" (or "" when unset); Value returns the secret itself.
+type Secret string
+
+// Value returns the secret
+func (s Secret) Value() string {
+	return string(s)
+}
+
+// IsSet reports whether the secret has a value
+func (s Secret) IsSet() bool {
+	return s != ""
+}
+
+// String redacts the secret for fmt
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// GoString redacts the secret for %#v
+func (s Secret) GoString() string {
+	return `"` + s.String() + `"`
+}
+
+// MarshalJSON redacts the secret for encoding/json
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// LogValue redacts the secret for log/slog
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
@@ -0,0 +1,214 @@
+package config
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// minProductionSecretLength is the shortest JWT secret accepted in production (256 bits)
+const minProductionSecretLength = 32
+
+// ValidationError lists every problem found in the configuration
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid configuration (%d problems): %s", len(e.Problems), strings.Join(e.Problems, "; "))
+}
+
+// Validate checks the settings of every feature, returning a *ValidationError listing all
+// problems found. Load already validates; this is for configurations built in code.
+func (c *Config) Validate() error {
+	if problems := c.problems(); len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func (c *Config) problems() []string {
+	v := &validator{}
+	c.validateServer(v)
+	c.validateObservability(v)
+	c.validateDatabase(v)
+	c.validateAuth(v)
+	c.validateEmail(v)
+	c.validateStripe(v)
+	c.validateWorkers(v)
+	c.validateChecker(v)
+	return v.problems
+}
+
+func (c *Config) validateServer(v *validator) {
+	v.oneOf("ENV", c.Env, "development", "test", "staging", "production")
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		v.add("PORT: %q is not a TCP port", c.Port)
+	}
+	v.positive("HTTP_READ_HEADER_TIMEOUT", c.HTTPReadHeaderTimeout)
+	v.positive("HTTP_READ_TIMEOUT", c.HTTPReadTimeout)
+	v.positive("HTTP_WRITE_TIMEOUT", c.HTTPWriteTimeout)
+	v.positive("HTTP_IDLE_TIMEOUT", c.HTTPIdleTimeout)
+	v.positive("SHUTDOWN_TIMEOUT", c.ShutdownTimeout)
+	v.nonNegative("REQUEST_TIMEOUT", c.RequestTimeout)
+	if c.RequestTimeout >= c.HTTPWriteTimeout {
+		// The server would close the connection before a timed-out handler could respond
+		v.add("REQUEST_TIMEOUT: %s must be shorter than HTTP_WRITE_TIMEOUT (%s)", c.RequestTimeout, c.HTTPWriteTimeout)
+	}
+
+	if len(c.CORSOrigins) == 0 {
+		v.add("CORS_ORIGIN: at least one origin is required")
+	}
+	for _, origin := range c.CORSOrigins {
+		// The origin is compared verbatim with the browser's Origin header, which has no path
+		if u, err := url.Parse(origin); err != nil || !isHTTPURL(u) || u.Path != "" || u.RawQuery != "" {
+			v.add("CORS_ORIGIN: %q is not an origin like https://app.example.com", origin)
+		}
+	}
+	v.httpURL("FRONTEND_URL", c.FrontendURL)
+}
+
+func (c *Config) validateObservability(v *validator) {
+	v.oneOf("LOG_LEVEL", strings.ToLower(c.LogLevel), "debug", "info", "warn", "warning", "error")
+	v.oneOf("LOG_FORMAT", strings.ToLower(c.LogFormat), "json", "text")
+	v.oneOf("OTEL_TRACES_EXPORTER", strings.ToLower(c.TracesExporter), "none", "otlp")
+	if c.ServiceName == "" {
+		v.add("OTEL_SERVICE_NAME: must not be empty")
+	}
+}
+
+func (c *Config) validateDatabase(v *validator) {
+	if !c.DatabaseURL.IsSet() {
+		v.add("DATABASE_URL: required")
+	}
+	v.nonNegative("DB_SLOW_QUERY_THRESHOLD", c.DBSlowQueryThreshold)
+	v.atLeastOne("DB_MAX_OPEN_CONNS", c.DBMaxOpenConns)
+	if c.DBMaxIdleConns < 0 || c.DBMaxIdleConns > c.DBMaxOpenConns {
+		v.add("DB_MAX_IDLE_CONNS: must be between 0 and DB_MAX_OPEN_CONNS (%d), got %d", c.DBMaxOpenConns, c.DBMaxIdleConns)
+	}
+	v.nonNegative("DB_CONN_MAX_LIFETIME", c.DBConnMaxLifetime)
+	v.nonNegative("DB_CONN_MAX_IDLE_TIME", c.DBConnMaxIdleTime)
+	v.nonNegative("DB_STATEMENT_TIMEOUT", c.DBStatementTimeout)
+}
+
+func (c *Config) validateAuth(v *validator) {
+	switch {
+	case !c.JWTSecret.IsSet():
+		v.add("JWT_SECRET: required")
+	case c.IsProduction() && len(c.JWTSecret.Value()) < minProductionSecretLength:
+		v.add("JWT_SECRET: must be at least %d characters in production", minProductionSecretLength)
+	}
+
+	// Google sign-in is enabled by its client ID and then needs the rest of its settings
+	if c.GoogleClientID != "" || c.GoogleClientSecret.IsSet() || c.GoogleCallbackURL != "" {
+		v.requiredWith("GOOGLE_CLIENT_ID", c.GoogleClientID != "", "Google sign-in")
+		v.requiredWith("GOOGLE_CLIENT_SECRET", c.GoogleClientSecret.IsSet(), "Google sign-in")
+		v.requiredWith("GOOGLE_CALLBACK_URL", c.GoogleCallbackURL != "", "Google sign-in")
+		if c.GoogleCallbackURL != "" {
+			v.httpURL("GOOGLE_CALLBACK_URL", c.GoogleCallbackURL)
+		}
+	}
+}
+
+func (c *Config) validateEmail(v *validator) {
+	// Without a provider emails, password reset links included, are written to the log
+	if c.IsProduction() && !c.ResendAPIKey.IsSet() {
+		v.add("RESEND_API_KEY: required in production")
+	}
+	if _, err := mail.ParseAddress(c.AlertEmailFrom); err != nil {
+		v.add("ALERT_EMAIL_FROM: %q is not an email address", c.AlertEmailFrom)
+	}
+}
+
+func (c *Config) validateStripe(v *validator) {
+	if !c.BillingEnabled && !c.StripeSecretKey.IsSet() && !c.StripeWebhookSecret.IsSet() && c.StripeProPriceID == "" {
+		return
+	}
+	v.requiredWith("STRIPE_SECRET_KEY", c.StripeSecretKey.IsSet(), "billing")
+	v.requiredWith("STRIPE_WEBHOOK_SECRET", c.StripeWebhookSecret.IsSet(), "billing")
+	v.requiredWith("STRIPE_PRO_PRICE_ID", c.StripeProPriceID != "", "billing")
+}
+
+func (c *Config) validateWorkers(v *validator) {
+	v.positive("LINK_LIFECYCLE_INTERVAL", c.LinkLifecycleInterval)
+	v.nonNegative("LINK_ARCHIVE_AFTER", c.LinkArchiveAfter)
+	v.positive("ALERT_DIGEST_POLL_INTERVAL", c.AlertDigestPollInterval)
+	v.positive("UPTIME_ROLLUP_INTERVAL", c.UptimeRollupInterval)
+	v.positive("WEBHOOK_POLL_INTERVAL", c.WebhookPollInterval)
+	v.atLeastOne("WEBHOOK_BATCH_SIZE", c.WebhookBatchSize)
+	v.positive("WEBHOOK_TIMEOUT", c.WebhookTimeout)
+}
+
+func (c *Config) validateChecker(v *validator) {
+	v.positive("CHECK_INTERVAL", c.CheckInterval)
+	v.positive("CHECK_POLL_INTERVAL", c.CheckPollInterval)
+	v.atLeastOne("CHECK_BATCH_SIZE", c.CheckBatchSize)
+	v.atLeastOne("CHECK_CONCURRENCY", c.CheckConcurrency)
+	v.positive("CHECK_TIMEOUT", c.CheckTimeout)
+	v.atLeastOne("CHECK_MAX_HOPS", c.CheckMaxHops)
+	v.positive("CHECK_PRO_INTERVAL", c.CheckProInterval)
+	v.positive("CHECK_MIN_INTERVAL", c.CheckMinInterval)
+	v.positive("CHECK_MAX_INTERVAL", c.CheckMaxInterval)
+	if c.CheckMinInterval > c.CheckMaxInterval {
+		v.add("CHECK_MIN_INTERVAL: %s is longer than CHECK_MAX_INTERVAL (%s)", c.CheckMinInterval, c.CheckMaxInterval)
+	}
+	v.positive("CHECK_FAILURE_RETRY", c.CheckFailureRetry)
+	v.positive("CHECK_DEAD_AFTER", c.CheckDeadAfter)
+	v.atLeastOne("CHECK_HOST_CONCURRENCY", c.CheckHostConcurrency)
+	v.nonNegative("CHECK_HOST_INTERVAL", c.CheckHostInterval)
+}
+
+// validator collects problems, each prefixed with the variable it concerns
+type validator struct {
+	problems []string
+}
+
+func (v *validator) add(format string, args ...any) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.add("%s: %q is not one of %s", key, value, strings.Join(allowed, ", "))
+}
+
+func (v *validator) positive(key string, d time.Duration) {
+	if d <= 0 {
+		v.add("%s: must be positive, got %s", key, d)
+	}
+}
+
+func (v *validator) nonNegative(key string, d time.Duration) {
+	if d < 0 {
+		v.add("%s: must not be negative, got %s", key, d)
+	}
+}
+
+func (v *validator) atLeastOne(key string, n int) {
+	if n < 1 {
+		v.add("%s: must be at least 1, got %d", key, n)
+	}
+}
+
+func (v *validator) requiredWith(key string, set bool, feature string) {
+	if !set {
+		v.add("%s: required when %s is configured", key, feature)
+	}
+}
+
+func (v *validator) httpURL(key, raw string) {
+	if u, err := url.Parse(raw); err != nil || !isHTTPURL(u) {
+		v.add("%s: %q is not an absolute http(s) URL", key, raw)
+	}
+}
+
+func isHTTPURL(u *url.URL) bool {
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/1shoukr/linkvault/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AlertController handles notification preference requests
+type AlertController struct {
+	alertService *services.AlertService
+}
+
+// NewAlertController creates a new alert controller
+func NewAlertController(alertService *services.AlertService) *AlertController {
+	return &AlertController{
+		alertService: alertService,
+	}
+}
+
+// GetPreferences handles GET /api/me/notifications
+func (ac *AlertController) GetPreferences(c *gin.Context) {
+	prefs, err := ac.alertService.GetPreferences(c.Request.Context(), c.MustGet("userID").(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve notification preferences",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": prefs,
+	})
+}
+
+// UpdatePreferences handles PUT /api/me/notifications
+func (ac *AlertController) UpdatePreferences(c *gin.Context) {
+	var input services.NotificationPreferenceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	prefs, err := ac.alertService.UpdatePreferences(c.Request.Context(), c.MustGet("userID").(uuid.UUID), input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": prefs,
+	})
+}
+
+// SendTestNotification handles POST /api/me/notifications/test
+func (ac *AlertController) SendTestNotification(c *gin.Context) {
+	var request struct {
+		Channel string `json:"channel" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := ac.alertService.SendTestNotification(c.Request.Context(), c.MustGet("userID").(uuid.UUID), request.Channel)
+	switch {
+	case errors.Is(err, services.ErrUnknownChannel), errors.Is(err, services.ErrChannelNotConfigured):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Test message sent",
+	})
+}
@@ -31,7 +31,7 @@ func (ac *AuthController) Register(c *gin.Context) {
 		return
 	}
 
-	user, token, err := ac.authService.Register(registerRequest.Email, registerRequest.Password, registerRequest.Name)
+	user, token, err := ac.authService.Register(c.Request.Context(), registerRequest.Email, registerRequest.Password, registerRequest.Name)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -71,7 +71,7 @@ func (ac *AuthController) Login(c *gin.Context) {
 		return
 	}
 
-	user, token, err := ac.authService.Login(loginRequest.Email, loginRequest.Password)
+	user, token, err := ac.authService.Login(c.Request.Context(), loginRequest.Email, loginRequest.Password)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
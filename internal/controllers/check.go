@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/1shoukr/linkvault/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CheckController handles HTTP requests for link health checks
+type CheckController struct {
+	checkService *services.CheckService
+}
+
+// NewCheckController creates a new check controller
+func NewCheckController(checkService *services.CheckService) *CheckController {
+	return &CheckController{
+		checkService: checkService,
+	}
+}
+
+// CheckLink handles POST /api/links/:id/check
+func (cc *CheckController) CheckLink(c *gin.Context) {
+	linkID, ok := parseLinkID(c)
+	if !ok {
+		return
+	}
+
+	history, err := cc.checkService.CheckUserLink(c.Request.Context(), c.MustGet("userID").(uuid.UUID), linkID)
+	if err != nil {
+		respondLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": history})
+}
+
+// GetCheckHistory handles GET /api/links/:id/checks
+func (cc *CheckController) GetCheckHistory(c *gin.Context) {
+	linkID, ok := parseLinkID(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	history, err := cc.checkService.GetCheckHistory(c.Request.Context(), c.MustGet("userID").(uuid.UUID), linkID, limit)
+	if err != nil {
+		respondLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": history})
+}
+
+// GetContentRules handles GET /api/content-rules
+func (cc *CheckController) GetContentRules(c *gin.Context) {
+	rules, err := cc.checkService.GetContentRules(c.Request.Context(), c.MustGet("userID").(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve content rules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": rules})
+}
+
+// CreateContentRule handles POST /api/content-rules
+func (cc *CheckController) CreateContentRule(c *gin.Context) {
+	var input services.ContentRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := cc.checkService.CreateContentRule(c.Request.Context(), c.MustGet("userID").(uuid.UUID), input)
+	if err != nil {
+		respondLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": rule})
+}
+
+// DeleteContentRule handles DELETE /api/content-rules/:id
+func (cc *CheckController) DeleteContentRule(c *gin.Context) {
+	ruleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content rule ID format"})
+		return
+	}
+
+	err = cc.checkService.DeleteContentRule(c.Request.Context(), c.MustGet("userID").(uuid.UUID), ruleID)
+	if errors.Is(err, services.ErrContentRuleNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Content rule not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Content rule deleted successfully"})
+}
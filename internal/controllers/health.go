@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/buildinfo"
+	"github.com/1shoukr/linkvault/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// serviceName identifies the API in health and info responses
+const serviceName = "linkvault-api"
+
+// HealthController handles liveness, readiness and build info requests
+type HealthController struct {
+	healthService *services.HealthService
+}
+
+// NewHealthController creates a new health controller
+func NewHealthController(healthService *services.HealthService) *HealthController {
+	return &HealthController{
+		healthService: healthService,
+	}
+}
+
+// Live handles GET /health. It only reports that the process is serving requests, so a slow
+// database never gets a healthy instance restarted.
+func (hc *HealthController) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "ok",
+		"service":   serviceName,
+		"timestamp": time.Now().Unix(),
+	})
+}
+
+// Ready handles GET /health/ready. It answers 503 while the database is unreachable or
+// migrations are pending, and 200 with status "degraded" when a background worker is stale.
+func (hc *HealthController) Ready(c *gin.Context) {
+	readiness := hc.healthService.Readiness(c.Request.Context())
+	status := http.StatusOK
+	if !readiness.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, readiness)
+}
+
+// Version handles GET /version
+func (hc *HealthController) Version(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"service":        serviceName,
+			"build":          buildinfo.Get(),
+			"uptime_seconds": int64(buildinfo.Uptime().Seconds()),
+		},
+	})
+}
+
+// Info handles GET /api
+func (hc *HealthController) Info(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"service": serviceName,
+		"version": buildinfo.Version,
+		"status":  "ok",
+	})
+}
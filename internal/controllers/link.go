@@ -0,0 +1,321 @@
+package controllers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/logging"
+	"github.com/1shoukr/linkvault/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// LinkController handles HTTP requests for link operations
+type LinkController struct {
+	linkService *services.LinkService
+}
+
+// NewLinkController creates a new link controller
+func NewLinkController(linkService *services.LinkService) *LinkController {
+	return &LinkController{
+		linkService: linkService,
+	}
+}
+
+// GetLinks handles GET /api/links
+func (lc *LinkController) GetLinks(c *gin.Context) {
+	links, err := lc.linkService.GetLinks(c.Request.Context(), c.MustGet("userID").(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve links"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": links})
+}
+
+// GetLink handles GET /api/links/:id
+func (lc *LinkController) GetLink(c *gin.Context) {
+	linkID, ok := parseLinkID(c)
+	if !ok {
+		return
+	}
+
+	link, err := lc.linkService.GetLink(c.Request.Context(), c.MustGet("userID").(uuid.UUID), linkID)
+	if err != nil {
+		respondLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": link})
+}
+
+// CreateLink handles POST /api/links
+func (lc *LinkController) CreateLink(c *gin.Context) {
+	var input services.LinkInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	link, err := lc.linkService.CreateLink(c.Request.Context(), c.MustGet("userID").(uuid.UUID), input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data":     link,
+		"warnings": lc.linkService.AffiliateWarnings(c.Request.Context(), link),
+	})
+}
+
+// UpdateLink handles PATCH /api/links/:id
+func (lc *LinkController) UpdateLink(c *gin.Context) {
+	linkID, ok := parseLinkID(c)
+	if !ok {
+		return
+	}
+
+	var input services.LinkInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	link, err := lc.linkService.UpdateLink(c.Request.Context(), c.MustGet("userID").(uuid.UUID), linkID, input)
+	if err != nil {
+		respondLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":     link,
+		"warnings": lc.linkService.AffiliateWarnings(c.Request.Context(), link),
+	})
+}
+
+// DetectMerchant handles POST /api/links/detect
+func (lc *LinkController) DetectMerchant(c *gin.Context) {
+	var request struct {
+		URL string `json:"url" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	inspection, err := lc.linkService.InspectURL(c.Request.Context(), c.MustGet("userID").(uuid.UUID), request.URL)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": inspection})
+}
+
+// DeleteLink handles DELETE /api/links/:id
+func (lc *LinkController) DeleteLink(c *gin.Context) {
+	linkID, ok := parseLinkID(c)
+	if !ok {
+		return
+	}
+
+	if err := lc.linkService.DeleteLink(c.Request.Context(), c.MustGet("userID").(uuid.UUID), linkID); err != nil {
+		respondLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Link deleted successfully"})
+}
+
+// SetGeoRules handles PUT /api/links/:id/geo-rules
+func (lc *LinkController) SetGeoRules(c *gin.Context) {
+	linkID, ok := parseLinkID(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Rules []services.GeoRuleInput `json:"rules" binding:"dive"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	link, err := lc.linkService.SetGeoRules(c.Request.Context(), c.MustGet("userID").(uuid.UUID), linkID, request.Rules)
+	if err != nil {
+		respondLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": link.GeoRules})
+}
+
+// SetVariants handles PUT /api/links/:id/variants
+func (lc *LinkController) SetVariants(c *gin.Context) {
+	linkID, ok := parseLinkID(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Variants []services.VariantInput `json:"variants" binding:"dive"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	link, err := lc.linkService.SetVariants(c.Request.Context(), c.MustGet("userID").(uuid.UUID), linkID, request.Variants)
+	if err != nil {
+		respondLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": link.Variants})
+}
+
+// SetDeviceRules handles PUT /api/links/:id/device-rules
+func (lc *LinkController) SetDeviceRules(c *gin.Context) {
+	linkID, ok := parseLinkID(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Rules []services.DeviceRuleInput `json:"rules" binding:"dive"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	link, err := lc.linkService.SetDeviceRules(c.Request.Context(), c.MustGet("userID").(uuid.UUID), linkID, request.Rules)
+	if err != nil {
+		respondLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": link.DeviceRules})
+}
+
+// CreateAccessToken handles POST /api/links/:id/access-tokens
+func (lc *LinkController) CreateAccessToken(c *gin.Context) {
+	linkID, ok := parseLinkID(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		ExpiresInSeconds int `json:"expires_in_seconds" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ttl := time.Duration(request.ExpiresInSeconds) * time.Second
+	token, expiresAt, err := lc.linkService.IssueAccessToken(c.Request.Context(), c.MustGet("userID").(uuid.UUID), linkID, ttl)
+	if err != nil {
+		respondLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"token":      token,
+		"path":       linkPath(linkID) + "?token=" + token,
+		"expires_at": expiresAt,
+	})
+}
+
+// SetChannels handles PUT /api/links/:id/channels
+func (lc *LinkController) SetChannels(c *gin.Context) {
+	linkID, ok := parseLinkID(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Channels []services.ChannelInput `json:"channels" binding:"dive"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	link, err := lc.linkService.SetChannels(c.Request.Context(), c.MustGet("userID").(uuid.UUID), linkID, request.Channels)
+	if err != nil {
+		respondLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": link.Channels})
+}
+
+// PreviewUTM handles GET /api/links/:id/utm-preview?channel=
+func (lc *LinkController) PreviewUTM(c *gin.Context) {
+	linkID, ok := parseLinkID(c)
+	if !ok {
+		return
+	}
+
+	channel := c.Query("channel")
+	tagged, err := lc.linkService.BuildTaggedURL(c.Request.Context(), c.MustGet("userID").(uuid.UUID), linkID, channel)
+	if err != nil {
+		respondLinkError(c, err)
+		return
+	}
+
+	path := linkPath(linkID)
+	if channel != "" {
+		path += "/" + channel
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"path":        path,
+		"destination": tagged,
+	})
+}
+
+// GetVariantStats handles GET /api/links/:id/variants/stats
+func (lc *LinkController) GetVariantStats(c *gin.Context) {
+	linkID, ok := parseLinkID(c)
+	if !ok {
+		return
+	}
+
+	stats, err := lc.linkService.GetVariantStats(c.Request.Context(), c.MustGet("userID").(uuid.UUID), linkID)
+	if err != nil {
+		respondLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": stats})
+}
+
+// parseLinkID parses the :id URL parameter, writing a 400 response on failure
+func parseLinkID(c *gin.Context) (uuid.UUID, bool) {
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid link ID format"})
+		return uuid.Nil, false
+	}
+	annotateLink(c, linkID)
+	return linkID, true
+}
+
+// annotateLink attaches the link ID to the request's log records
+func annotateLink(c *gin.Context, linkID uuid.UUID) {
+	logging.Annotate(c.Request.Context(), slog.String("link_id", linkID.String()))
+}
+
+// respondLinkError maps link service errors to HTTP responses
+func respondLinkError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrLinkNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,270 @@
+package controllers
+
+import (
+	"errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/metrics"
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/1shoukr/linkvault/internal/services"
+	"github.com/1shoukr/linkvault/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// variantCookieMaxAge keeps A/B assignments sticky for 30 days
+	variantCookieMaxAge = int(30 * 24 * time.Hour / time.Second)
+	// accessGrantTTL is how long a visitor stays unlocked after entering a link's password
+	accessGrantTTL = 12 * time.Hour
+)
+
+// accessPage is the interstitial shown for password-protected and private links
+var accessPage = template.Must(template.New("access").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Protected link</title>
+</head>
+<body>
+{{if .Private}}
+<p>This link is private. Ask the owner for an access link.</p>
+{{else}}
+<form method="POST" action="{{.Action}}">
+<label for="password">This link is password protected</label>
+<input id="password" name="password" type="password" autocomplete="current-password" required autofocus>
+<button type="submit">Continue</button>
+</form>
+{{end}}
+{{if .Error}}<p role="alert">{{.Error}}</p>{{end}}
+</body>
+</html>
+`))
+
+// deepLinkPage tries to open the app and falls back to the web destination if nothing handles the scheme
+var deepLinkPage = template.Must(template.New("deeplink").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Opening app…</title>
+</head>
+<body>
+<p>Opening the app… <a href="{{.Fallback}}">Continue in your browser</a></p>
+<script>
+var fallback = {{.Fallback}};
+var timer = setTimeout(function () { window.location.replace(fallback); }, 1500);
+document.addEventListener("visibilitychange", function () { if (document.hidden) clearTimeout(timer); });
+window.location.href = {{.DeepLink}};
+</script>
+</body>
+</html>
+`))
+
+// RedirectController handles public link redirects
+type RedirectController struct {
+	linkService *services.LinkService
+	clicks      *services.ClickRecorder
+	metrics     *metrics.Metrics
+
+	// TrustCountryHeaders reads the visitor's country from CDN headers such as CF-IPCountry
+	TrustCountryHeaders bool
+}
+
+// NewRedirectController creates a new redirect controller. m may be nil.
+func NewRedirectController(linkService *services.LinkService, clicks *services.ClickRecorder, m *metrics.Metrics) *RedirectController {
+	return &RedirectController{
+		linkService: linkService,
+		clicks:      clicks,
+		metrics:     m,
+	}
+}
+
+// Redirect handles GET /r/:id and GET /r/:id/:channel
+func (rc *RedirectController) Redirect(c *gin.Context) {
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		rc.metrics.Redirect(metrics.RedirectUnavailable)
+		c.String(http.StatusNotFound, "Link not found")
+		return
+	}
+	annotateLink(c, linkID)
+
+	cookieName := variantCookieName(linkID)
+	visitor := services.Visitor{
+		Country:  utils.ResolveCountry(c.Request, rc.TrustCountryHeaders),
+		Platform: utils.DevicePlatform(c.GetHeader("User-Agent")),
+		Channel:  c.Param("channel"),
+	}
+	visitor.StickyVariantID, _ = c.Cookie(cookieName)
+
+	link, target, err := rc.linkService.ResolveRedirect(c.Request.Context(), linkID, visitor)
+	if err != nil {
+		rc.metrics.Redirect(metrics.RedirectUnavailable)
+		respondUnavailable(c, link, err)
+		return
+	}
+	if link.RequiresAccess() && !rc.hasLinkAccess(c, link) {
+		rc.metrics.Redirect(metrics.RedirectLocked)
+		renderAccessPage(c, link, http.StatusUnauthorized, "")
+		return
+	}
+
+	if err := rc.linkService.ClaimClick(c.Request.Context(), link); err != nil {
+		rc.metrics.Redirect(metrics.RedirectUnavailable)
+		if !errors.Is(err, services.ErrLinkExpired) {
+			slog.ErrorContext(c.Request.Context(), "Failed to count click against cap", "error", err)
+			c.String(http.StatusServiceUnavailable, "Link temporarily unavailable")
+			return
+		}
+		respondUnavailable(c, link, err)
+		return
+	}
+
+	click := newClick(c, link.ID, visitor.Country)
+	if target.Variant != nil {
+		click.VariantID = &target.Variant.ID
+		c.SetCookie(cookieName, target.Variant.ID.String(), variantCookieMaxAge, linkPath(link.ID), "", false, true)
+	}
+	rc.clicks.Record(c.Request.Context(), link, click)
+
+	switch {
+	case target.DeepLinkURL == "":
+		rc.metrics.Redirect(metrics.RedirectServed)
+		c.Redirect(http.StatusFound, target.URL)
+	case strings.HasPrefix(target.DeepLinkURL, "https://"):
+		// Universal/app links open the app when installed and load as a web page otherwise
+		rc.metrics.Redirect(metrics.RedirectServed)
+		c.Redirect(http.StatusFound, target.DeepLinkURL)
+	default:
+		rc.metrics.Redirect(metrics.RedirectDeepLink)
+		c.Header("Cache-Control", "no-store")
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.Status(http.StatusOK)
+		err := deepLinkPage.Execute(c.Writer, gin.H{
+			"DeepLink": template.URL(target.DeepLinkURL), // validated app scheme
+			"Fallback": target.URL,
+		})
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "Failed to render deep link page", "error", err)
+		}
+	}
+}
+
+// Unlock handles POST /r/:id and POST /r/:id/:channel, checking a protected link's password
+func (rc *RedirectController) Unlock(c *gin.Context) {
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.String(http.StatusNotFound, "Link not found")
+		return
+	}
+	annotateLink(c, linkID)
+
+	link, err := rc.linkService.GetPublicLink(c.Request.Context(), linkID)
+	if err != nil {
+		respondUnavailable(c, link, err)
+		return
+	}
+	if !link.RequiresAccess() {
+		c.Redirect(http.StatusSeeOther, c.Request.URL.Path)
+		return
+	}
+	if link.PasswordHash == nil {
+		renderAccessPage(c, link, http.StatusForbidden, "")
+		return
+	}
+
+	err = rc.linkService.VerifyLinkPassword(link, c.PostForm("password"), c.ClientIP())
+	switch {
+	case errors.Is(err, services.ErrTooManyAttempts):
+		renderAccessPage(c, link, http.StatusTooManyRequests, err.Error())
+		return
+	case err != nil:
+		renderAccessPage(c, link, http.StatusUnauthorized, services.ErrInvalidLinkPassword.Error())
+		return
+	}
+
+	token, err := rc.linkService.GrantAccess(link.ID, accessGrantTTL)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to unlock link")
+		return
+	}
+	c.SetCookie(accessCookieName(link.ID), token, int(accessGrantTTL.Seconds()), linkPath(link.ID), "", false, true)
+	c.Redirect(http.StatusSeeOther, c.Request.URL.Path)
+}
+
+// respondUnavailable writes the response for a link that can't be served
+func respondUnavailable(c *gin.Context, link *models.Link, err error) {
+	if errors.Is(err, services.ErrLinkExpired) {
+		if link.FallbackURL != nil {
+			c.Redirect(http.StatusFound, *link.FallbackURL)
+			return
+		}
+		c.String(http.StatusGone, "This link has expired")
+		return
+	}
+	c.String(http.StatusNotFound, "Link not found")
+}
+
+// hasLinkAccess checks for a valid access token in the query string or the unlock cookie
+func (rc *RedirectController) hasLinkAccess(c *gin.Context, link *models.Link) bool {
+	if rc.linkService.HasAccess(c.Query("token"), link.ID) {
+		return true
+	}
+	token, err := c.Cookie(accessCookieName(link.ID))
+	return err == nil && rc.linkService.HasAccess(token, link.ID)
+}
+
+// renderAccessPage renders the password/private interstitial for a link
+func renderAccessPage(c *gin.Context, link *models.Link, status int, message string) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	err := accessPage.Execute(c.Writer, gin.H{
+		"Private": link.PasswordHash == nil,
+		"Action":  c.Request.URL.Path,
+		"Error":   message,
+	})
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to render access page", "error", err)
+	}
+}
+
+// linkPath returns the public redirect path of a link
+func linkPath(linkID uuid.UUID) string {
+	return "/r/" + linkID.String()
+}
+
+// accessCookieName returns the cookie holding a visitor's unlock grant for a link
+func accessCookieName(linkID uuid.UUID) string {
+	return "lv_access_" + linkID.String()
+}
+
+// variantCookieName returns the cookie that pins a visitor to one A/B variant of a link
+func variantCookieName(linkID uuid.UUID) string {
+	return "lv_variant_" + linkID.String()
+}
+
+// newClick captures the request metadata for a click before the response is written
+func newClick(c *gin.Context, linkID uuid.UUID, country string) *models.Click {
+	click := &models.Click{LinkID: linkID}
+	if referrer := c.GetHeader("Referer"); referrer != "" {
+		click.Referrer = &referrer
+	}
+	if userAgent := c.GetHeader("User-Agent"); userAgent != "" {
+		click.UserAgent = &userAgent
+	}
+	if ip := c.ClientIP(); ip != "" {
+		click.IPAddress = &ip
+	}
+	if country != "" {
+		click.Country = &country
+	}
+	return click
+}
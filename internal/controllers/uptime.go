@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UptimeController handles uptime and SLA report requests
+type UptimeController struct {
+	uptimeService *services.UptimeService
+}
+
+// NewUptimeController creates a new uptime controller
+func NewUptimeController(uptimeService *services.UptimeService) *UptimeController {
+	return &UptimeController{
+		uptimeService: uptimeService,
+	}
+}
+
+// GetLinkUptime handles GET /api/links/:id/uptime?from=&to=
+func (uc *UptimeController) GetLinkUptime(c *gin.Context) {
+	linkID, ok := parseLinkID(c)
+	if !ok {
+		return
+	}
+	from, to, ok := parseReportWindow(c)
+	if !ok {
+		return
+	}
+
+	report, err := uc.uptimeService.GetLinkReport(c.Request.Context(), c.MustGet("userID").(uuid.UUID), linkID, from, to)
+	if err != nil {
+		respondLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// GetUserUptime handles GET /api/me/uptime?from=&to=
+func (uc *UptimeController) GetUserUptime(c *gin.Context) {
+	from, to, ok := parseReportWindow(c)
+	if !ok {
+		return
+	}
+
+	report, err := uc.uptimeService.GetUserReport(c.Request.Context(), c.MustGet("userID").(uuid.UUID), from, to)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// parseReportWindow parses the optional from/to query parameters as RFC 3339 timestamps or
+// YYYY-MM-DD dates, writing a 400 response on failure
+func parseReportWindow(c *gin.Context) (*time.Time, *time.Time, bool) {
+	var bounds [2]*time.Time
+	for i, name := range []string{"from", "to"} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t, err = time.Parse(time.DateOnly, value)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid %s: use RFC 3339 or YYYY-MM-DD", name)})
+			return nil, nil, false
+		}
+		bounds[i] = &t
+	}
+	return bounds[0], bounds[1], true
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"net/http"
 
+	"github.com/1shoukr/linkvault/internal/models"
 	"github.com/1shoukr/linkvault/internal/services"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -33,7 +34,7 @@ func (uc *UserController) GetUser(c *gin.Context) {
 	}
 
 	// Get user from service
-	user, err := uc.userService.GetUserByID(userID)
+	user, err := uc.userService.GetUserByID(c.Request.Context(), userID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": "User not found",
@@ -48,7 +49,7 @@ func (uc *UserController) GetUser(c *gin.Context) {
 
 // GetUsers handles GET /api/users
 func (uc *UserController) GetUsers(c *gin.Context) {
-	users, err := uc.userService.GetAllUsers()
+	users, err := uc.userService.GetAllUsers(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to retrieve users",
@@ -60,3 +61,66 @@ func (uc *UserController) GetUsers(c *gin.Context) {
 		"data": users,
 	})
 }
+
+// UpdateUTMDefaults handles PUT /api/me/utm-defaults
+func (uc *UserController) UpdateUTMDefaults(c *gin.Context) {
+	var utm models.UTMTemplate
+	if err := c.ShouldBindJSON(&utm); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	user, err := uc.userService.UpdateUTMDefaults(c.Request.Context(), c.MustGet("userID").(uuid.UUID), utm)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": user.DefaultUTM,
+	})
+}
+
+// GetAffiliateTags handles GET /api/me/affiliate-tags
+func (uc *UserController) GetAffiliateTags(c *gin.Context) {
+	tags, err := uc.userService.GetAffiliateTags(c.Request.Context(), c.MustGet("userID").(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve affiliate tags",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": tags,
+	})
+}
+
+// SetAffiliateTags handles PUT /api/me/affiliate-tags
+func (uc *UserController) SetAffiliateTags(c *gin.Context) {
+	var request struct {
+		Tags []services.AffiliateTagInput `json:"tags" binding:"dive"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	tags, err := uc.userService.SetAffiliateTags(c.Request.Context(), c.MustGet("userID").(uuid.UUID), request.Tags)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": tags,
+	})
+}
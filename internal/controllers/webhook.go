@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/1shoukr/linkvault/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WebhookController handles HTTP requests for outbound webhook endpoints
+type WebhookController struct {
+	webhookService *services.WebhookService
+}
+
+// NewWebhookController creates a new webhook controller
+func NewWebhookController(webhookService *services.WebhookService) *WebhookController {
+	return &WebhookController{
+		webhookService: webhookService,
+	}
+}
+
+// GetEndpoints handles GET /api/webhooks
+func (wc *WebhookController) GetEndpoints(c *gin.Context) {
+	endpoints, err := wc.webhookService.GetEndpoints(c.Request.Context(), c.MustGet("userID").(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve webhooks"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": endpoints})
+}
+
+// CreateEndpoint handles POST /api/webhooks. The signing secret is only ever returned here.
+func (wc *WebhookController) CreateEndpoint(c *gin.Context) {
+	var input services.WebhookEndpointInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	endpoint, err := wc.webhookService.CreateEndpoint(c.Request.Context(), c.MustGet("userID").(uuid.UUID), input)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data":   endpoint,
+		"secret": endpoint.Secret,
+	})
+}
+
+// UpdateEndpoint handles PATCH /api/webhooks/:id
+func (wc *WebhookController) UpdateEndpoint(c *gin.Context) {
+	endpointID, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	var input services.WebhookEndpointInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	endpoint, err := wc.webhookService.UpdateEndpoint(c.Request.Context(), c.MustGet("userID").(uuid.UUID), endpointID, input)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": endpoint})
+}
+
+// DeleteEndpoint handles DELETE /api/webhooks/:id
+func (wc *WebhookController) DeleteEndpoint(c *gin.Context) {
+	endpointID, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	if err := wc.webhookService.DeleteEndpoint(c.Request.Context(), c.MustGet("userID").(uuid.UUID), endpointID); err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+// GetDeliveries handles GET /api/webhooks/:id/deliveries
+func (wc *WebhookController) GetDeliveries(c *gin.Context) {
+	endpointID, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	deliveries, err := wc.webhookService.GetDeliveries(c.Request.Context(), c.MustGet("userID").(uuid.UUID), endpointID)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": deliveries})
+}
+
+// Redeliver handles POST /api/webhooks/:id/deliveries/:deliveryId/redeliver
+func (wc *WebhookController) Redeliver(c *gin.Context) {
+	endpointID, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	deliveryID, err := uuid.Parse(c.Param("deliveryId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery ID format"})
+		return
+	}
+
+	delivery, err := wc.webhookService.Redeliver(c.Request.Context(), c.MustGet("userID").(uuid.UUID), endpointID, deliveryID)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"data": delivery})
+}
+
+// parseWebhookID parses the :id URL parameter, writing a 400 response on failure
+func parseWebhookID(c *gin.Context) (uuid.UUID, bool) {
+	endpointID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID format"})
+		return uuid.Nil, false
+	}
+	return endpointID, true
+}
+
+// respondWebhookError maps webhook service errors to HTTP responses
+func respondWebhookError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrWebhookNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+	case errors.Is(err, services.ErrWebhookDeliveryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook delivery not found"})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
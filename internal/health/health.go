@@ -0,0 +1,101 @@
+// Package health tracks the liveness of background workers so readiness probes can report
+// workers that have stopped making progress.
+package health
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// staleIntervals is how many missed intervals mark a worker stale
+	staleIntervals = 3
+	// minStaleAfter keeps fast-polling workers from flapping stale during a slow pass
+	minStaleAfter = 5 * time.Minute
+)
+
+// Worker statuses
+const (
+	WorkerStarting = "starting" // registered, no pass completed yet
+	WorkerOK       = "ok"
+	WorkerStale    = "stale"
+)
+
+// WorkerStatus reports a worker's most recent heartbeat
+type WorkerStatus struct {
+	Name       string     `json:"name"`
+	Status     string     `json:"status"`
+	Interval   string     `json:"interval"`
+	LastBeatAt *time.Time `json:"last_beat_at"`
+}
+
+// Monitor collects heartbeats from background workers
+type Monitor struct {
+	mu      sync.Mutex
+	started time.Time
+	workers map[string]*workerState
+}
+
+type workerState struct {
+	interval time.Duration
+	lastBeat time.Time
+}
+
+// NewMonitor creates an empty monitor
+func NewMonitor() *Monitor {
+	return &Monitor{started: time.Now(), workers: make(map[string]*workerState)}
+}
+
+// Register declares a worker that should beat at least once per interval
+func (m *Monitor) Register(name string, interval time.Duration) *Heartbeat {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.workers[name] = &workerState{interval: interval}
+	return &Heartbeat{monitor: m, name: name}
+}
+
+// Workers reports every registered worker, sorted by name
+func (m *Monitor) Workers(now time.Time) []WorkerStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statuses := make([]WorkerStatus, 0, len(m.workers))
+	for name, state := range m.workers {
+		staleAfter := max(staleIntervals*state.interval, minStaleAfter)
+		status := WorkerStatus{Name: name, Interval: state.interval.String()}
+		switch {
+		case !state.lastBeat.IsZero():
+			lastBeat := state.lastBeat
+			status.LastBeatAt = &lastBeat
+			status.Status = WorkerOK
+			if now.Sub(lastBeat) > staleAfter {
+				status.Status = WorkerStale
+			}
+		case now.Sub(m.started) > staleAfter:
+			status.Status = WorkerStale
+		default:
+			status.Status = WorkerStarting
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// Heartbeat is a worker's handle for reporting progress. A nil Heartbeat ignores beats, so
+// workers built without a monitor need no special casing.
+type Heartbeat struct {
+	monitor *Monitor
+	name    string
+}
+
+// Beat records that the worker completed a pass
+func (h *Heartbeat) Beat() {
+	if h == nil {
+		return
+	}
+	h.monitor.mu.Lock()
+	defer h.monitor.mu.Unlock()
+	h.monitor.workers[h.name].lastBeat = time.Now()
+}
@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+type fieldsKey struct{}
+
+type requestIDKey struct{}
+
+// fields are the attributes attached to a context. They are shared by every context derived
+// from the one they were attached to, so a handler can Annotate a request after the middleware
+// that logs it has already captured the context.
+type fields struct {
+	mu    sync.Mutex
+	attrs []slog.Attr
+}
+
+func (f *fields) snapshot() []slog.Attr {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]slog.Attr(nil), f.attrs...)
+}
+
+func fieldsFrom(ctx context.Context) *fields {
+	if ctx == nil {
+		return nil
+	}
+	f, _ := ctx.Value(fieldsKey{}).(*fields)
+	return f
+}
+
+// With returns a context whose log records carry attrs in addition to those already attached to
+// ctx. Annotating the returned context does not affect ctx.
+func With(ctx context.Context, attrs ...slog.Attr) context.Context {
+	f := &fields{}
+	if parent := fieldsFrom(ctx); parent != nil {
+		f.attrs = parent.snapshot()
+	}
+	f.attrs = append(f.attrs, attrs...)
+	return context.WithValue(ctx, fieldsKey{}, f)
+}
+
+// Annotate adds attrs to the fields already attached to ctx, making them visible to every log
+// record of the request or pass, including ones written by callers up the stack. It is a no-op
+// for a context without fields.
+func Annotate(ctx context.Context, attrs ...slog.Attr) {
+	f := fieldsFrom(ctx)
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attrs = append(f.attrs, attrs...)
+}
+
+// WithRequestID returns a context carrying the request ID, both for RequestID and as the
+// request_id field of its log records
+func WithRequestID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	return With(ctx, slog.String("request_id", id))
+}
+
+// RequestID returns the request ID carried by ctx, or ""
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
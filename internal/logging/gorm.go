@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// GormLogger adapts slog to GORM's logger. Failed queries are logged as errors and queries
+// slower than the threshold as warnings, each with the fields of the statement's context, so a
+// slow query run for a request carries its request ID. Every other query is logged at debug.
+type GormLogger struct {
+	logger        *slog.Logger
+	level         logger.LogLevel
+	slowThreshold time.Duration
+}
+
+// NewGormLogger creates a GORM logger writing to l. A zero slowThreshold disables slow query
+// warnings.
+func NewGormLogger(l *slog.Logger, level logger.LogLevel, slowThreshold time.Duration) *GormLogger {
+	return &GormLogger{logger: l, level: level, slowThreshold: slowThreshold}
+}
+
+// LogMode returns a copy of the logger at level
+func (g *GormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	copied := *g
+	copied.level = level
+	return &copied
+}
+
+func (g *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if g.level >= logger.Info {
+		g.logger.InfoContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+func (g *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if g.level >= logger.Warn {
+		g.logger.WarnContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+func (g *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if g.level >= logger.Error {
+		g.logger.ErrorContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+// Trace logs a finished query
+func (g *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if g.level <= logger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+
+	switch {
+	// Lookups that find nothing are answered with ErrRecordNotFound by design, not failures
+	case err != nil && g.level >= logger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		g.logger.ErrorContext(ctx, "Database query failed", queryAttrs(sql, rows, elapsed, slog.Any("error", err))...)
+	case g.slowThreshold > 0 && elapsed > g.slowThreshold && g.level >= logger.Warn:
+		sql, rows := fc()
+		g.logger.WarnContext(ctx, "Slow database query", queryAttrs(sql, rows, elapsed, slog.Int64("threshold_ms", g.slowThreshold.Milliseconds()))...)
+	case g.level >= logger.Info && g.logger.Enabled(ctx, slog.LevelDebug):
+		sql, rows := fc()
+		g.logger.DebugContext(ctx, "Database query", queryAttrs(sql, rows, elapsed)...)
+	}
+}
+
+func queryAttrs(sql string, rows int64, elapsed time.Duration, extra ...slog.Attr) []any {
+	attrs := []any{
+		slog.String("sql", sql),
+		slog.Int64("rows", rows),
+		slog.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+	}
+	for _, attr := range extra {
+		attrs = append(attrs, attr)
+	}
+	return attrs
+}
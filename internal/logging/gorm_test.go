@@ -0,0 +1,75 @@
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/logging"
+	"github.com/1shoukr/linkvault/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// dryRunDB opens a gorm DB that builds and logs statements without connecting to Postgres
+func dryRunDB(t *testing.T, l logger.Interface) *gorm.DB {
+	t.Helper()
+	connConfig, err := pgx.ParseConfig("postgres://linkvault@127.0.0.1:1/linkvault")
+	if err != nil {
+		t.Fatalf("parsing DSN: %v", err)
+	}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: stdlib.OpenDB(*connConfig)}), &gorm.Config{
+		Logger:                 l,
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+	})
+	if err != nil {
+		t.Fatalf("opening dry-run DB: %v", err)
+	}
+	return db
+}
+
+func TestGormLoggerCarriesRequestID(t *testing.T) {
+	tests := []struct {
+		name          string
+		slowThreshold time.Duration
+		wantLevel     string
+		wantMsg       string
+	}{
+		{name: "every query at debug", wantLevel: "DEBUG", wantMsg: "Database query"},
+		{name: "slow queries", slowThreshold: time.Nanosecond, wantLevel: "WARN", wantMsg: "Slow database query"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			gormLogger := logging.NewGormLogger(logging.New(&buf, "debug", "json"), logger.Info, tt.slowThreshold)
+			users := repository.NewUserRepository(dryRunDB(t, gormLogger))
+
+			ctx := logging.WithRequestID(t.Context(), "req-42")
+			if _, err := users.GetByID(ctx, uuid.New()); err != nil {
+				t.Fatalf("GetByID: %v", err)
+			}
+
+			var record map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("decoding log record %q: %v", buf.String(), err)
+			}
+			if record["level"] != tt.wantLevel || record["msg"] != tt.wantMsg {
+				t.Fatalf("log record = %s %q, want %s %q", record["level"], record["msg"], tt.wantLevel, tt.wantMsg)
+			}
+			if record["request_id"] != "req-42" {
+				t.Fatalf("log record request_id = %v, want req-42: %s", record["request_id"], buf.String())
+			}
+			if sql, _ := record["sql"].(string); sql == "" {
+				t.Fatalf("log record has no SQL: %s", buf.String())
+			}
+		})
+	}
+}
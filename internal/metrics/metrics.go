@@ -0,0 +1,144 @@
+// Package metrics defines the Prometheus metrics exported on /metrics. A nil *Metrics records
+// nothing, so services and middleware built without metrics need no special casing.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "linkvault"
+
+// Redirect outcomes
+const (
+	RedirectServed      = "redirected"
+	RedirectDeepLink    = "deep_link"       // served the app-opening interstitial
+	RedirectLocked      = "access_required" // password or private interstitial
+	RedirectUnavailable = "unavailable"     // missing, inactive, expired or click-capped
+)
+
+// Check outcomes
+const (
+	CheckHealthy     = "healthy"
+	CheckUnhealthy   = "unhealthy"   // the destination answered, but not acceptably
+	CheckUnreachable = "unreachable" // no response: DNS, connection or timeout failure
+	CheckRateLimited = "rate_limited"
+)
+
+// Webhook delivery outcomes
+const (
+	WebhookDelivered = "delivered"
+	WebhookRetrying  = "retrying"
+	WebhookFailed    = "failed" // out of attempts or endpoint disabled
+)
+
+// Metrics holds the application's collectors and the registry they are exported from
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.HistogramVec
+	redirects    *prometheus.CounterVec
+	checks       *prometheus.CounterVec
+	webhooks     *prometheus.CounterVec
+}
+
+// New creates the application metrics, along with the Go runtime and process collectors
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of HTTP requests by method, route and status code.",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"method", "route", "status"}),
+		redirects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "redirects_total",
+			Help:      "Public redirect requests by outcome.",
+		}, []string{"outcome"}),
+		checks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "link_checks_total",
+			Help:      "Link health checks by outcome and destination host.",
+		}, []string{"outcome", "host"}),
+		webhooks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "webhook_deliveries_total",
+			Help:      "Outbound webhook delivery attempts by outcome.",
+		}, []string{"outcome"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.redirects,
+		m.checks,
+		m.webhooks,
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDB exports the connection pool statistics of db
+func (m *Metrics) RegisterDB(db *sql.DB, name string) {
+	if m == nil {
+		return
+	}
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// RegisterClickQueue exports the number of clicks waiting to be written, as reported by depth
+func (m *Metrics) RegisterClickQueue(depth func() int) {
+	if m == nil {
+		return
+	}
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "click_queue_depth",
+		Help:      "Clicks queued for writing.",
+	}, func() float64 { return float64(depth()) }))
+}
+
+// ObserveRequest records a served HTTP request. route is the matched route pattern, never the
+// raw path, to keep the label's cardinality bounded.
+func (m *Metrics) ObserveRequest(method, route string, status int, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Observe(elapsed.Seconds())
+}
+
+// Redirect records a public redirect request
+func (m *Metrics) Redirect(outcome string) {
+	if m == nil {
+		return
+	}
+	m.redirects.WithLabelValues(outcome).Inc()
+}
+
+// Check records a completed link health check
+func (m *Metrics) Check(outcome, host string) {
+	if m == nil {
+		return
+	}
+	m.checks.WithLabelValues(outcome, host).Inc()
+}
+
+// WebhookDelivery records a webhook delivery attempt
+func (m *Metrics) WebhookDelivery(outcome string) {
+	if m == nil {
+		return
+	}
+	m.webhooks.WithLabelValues(outcome).Inc()
+}
@@ -1,12 +1,15 @@
 package middleware
 
 import (
+	"errors"
+	"log/slog"
 	"net/http"
 	"strings"
 
+	"github.com/1shoukr/linkvault/internal/logging"
 	"github.com/1shoukr/linkvault/internal/services"
-	"github.com/1shoukr/linkvault/pkg/utils"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // AuthMiddleware validates JWT tokens and sets user context
@@ -33,23 +36,21 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 			token = parts[1]
 		}
 
-		// Validate token
-		claims, err := utils.ValidateToken(token)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		// Validate token and load its user
+		user, err := authService.Authenticate(c.Request.Context(), token)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
 			c.Abort()
 			return
 		}
-
-		// Get user from database
-		user, err := authService.GetUserByID(claims.UserID)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			c.Abort()
 			return
 		}
 
-		// Set user in context
+		// Set user in context and on the request's log records
+		logging.Annotate(c.Request.Context(), slog.String("user_id", user.ID.String()))
 		c.Set("user", user)
 		c.Set("userID", user.ID)
 		c.Set("userEmail", user.Email)
@@ -76,14 +77,11 @@ func OptionalAuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 
 		// If token exists, validate it
 		if token != "" {
-			claims, err := utils.ValidateToken(token)
-			if err == nil {
-				user, err := authService.GetUserByID(claims.UserID)
-				if err == nil {
-					c.Set("user", user)
-					c.Set("userID", user.ID)
-					c.Set("userEmail", user.Email)
-				}
+			if user, err := authService.Authenticate(c.Request.Context(), token); err == nil {
+				logging.Annotate(c.Request.Context(), slog.String("user_id", user.ID.String()))
+				c.Set("user", user)
+				c.Set("userID", user.ID)
+				c.Set("userEmail", user.Email)
 			}
 		}
 
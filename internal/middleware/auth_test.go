@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/clock"
+	"github.com/1shoukr/linkvault/internal/repository/memory"
+	"github.com/1shoukr/linkvault/internal/services"
+	"github.com/1shoukr/linkvault/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestAuthMiddlewareRejectsLinkAccessTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tokens, err := utils.NewTokenIssuer("test-secret", utils.DefaultTokenTTL, nil)
+	if err != nil {
+		t.Fatalf("NewTokenIssuer: %v", err)
+	}
+	authService := services.NewAuthService(memory.NewAuthRepository(memory.NewDB()), tokens, clock.Real{})
+	user, session, err := authService.Register(t.Context(), "middleware@example.com", "correct horse", "Middleware")
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	router := gin.New()
+	router.GET("/me", AuthMiddleware(authService), func(c *gin.Context) { c.Status(http.StatusOK) })
+	serve := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := serve(session); rec.Code != http.StatusOK {
+		t.Fatalf("GET /me with a session token = %d: %s", rec.Code, rec.Body)
+	}
+
+	// A link access token carries no user, so one signed with the session key would only
+	// fail at the user lookup; it must be refused as a token
+	for _, linkID := range []uuid.UUID{uuid.New(), user.ID} {
+		linkToken, err := tokens.GenerateLinkAccessToken(linkID, time.Hour)
+		if err != nil {
+			t.Fatalf("GenerateLinkAccessToken: %v", err)
+		}
+		rec := serve(linkToken)
+		var body struct {
+			Error string `json:"error"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &body)
+		if rec.Code != http.StatusUnauthorized || body.Error != "Invalid or expired token" {
+			t.Fatalf("GET /me with a link access token = %d %q, want 401 for an invalid token", rec.Code, body.Error)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"slices"
 
 	"github.com/1shoukr/linkvault/internal/config"
 	"github.com/gin-gonic/gin"
@@ -11,15 +12,19 @@ import (
 func CORS(cfg *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		origin := c.GetHeader("Origin")
-		
-		// Use configured CORS origin or allow the request origin
-		allowedOrigin := cfg.CORSOrigin
-		if origin != "" && cfg.Env == "development" {
-			// In development, allow any origin
+
+		// Echo the request origin when it is allowed, otherwise answer with the default origin.
+		// In development, allow any origin.
+		allowedOrigin := ""
+		if len(cfg.CORSOrigins) > 0 {
+			allowedOrigin = cfg.CORSOrigins[0]
+		}
+		if origin != "" && (cfg.Env == "development" || slices.Contains(cfg.CORSOrigins, origin)) {
 			allowedOrigin = origin
 		}
-		
+
 		c.Writer.Header().Set("Access-Control-Allow-Origin", allowedOrigin)
+		c.Writer.Header().Add("Vary", "Origin")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
@@ -32,6 +37,3 @@ func CORS(cfg *config.Config) gin.HandlerFunc {
 		c.Next()
 	}
 }
-
-
-
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/logging"
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout gives every request a context deadline of d, so database queries and outbound calls
+// made on its behalf are cancelled once the client can no longer be answered in time. A d of
+// zero or less disables the deadline.
+func Timeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if d <= 0 {
+			c.Next()
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			logging.Annotate(ctx, slog.Bool("timed_out", true))
+		}
+	}
+}
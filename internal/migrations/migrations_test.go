@@ -0,0 +1,236 @@
+package migrations_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/migrations"
+	"github.com/1shoukr/linkvault/internal/repository"
+	"github.com/1shoukr/linkvault/internal/repository/repotest"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// The models as they were in the release before versioned migrations, which created its
+// schema with AutoMigrate on every boot
+
+type baselineUser struct {
+	ID                 uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Email              string    `gorm:"type:varchar(255);uniqueIndex:idx_users_email;not null"`
+	PasswordHash       *string   `gorm:"type:varchar(255)"`
+	Name               *string   `gorm:"type:varchar(255)"`
+	AvatarURL          *string   `gorm:"type:text"`
+	EmailVerified      bool      `gorm:"default:false"`
+	Plan               string    `gorm:"type:varchar(20);default:'free';index:idx_users_plan"`
+	StripeCustomerID   *string   `gorm:"type:varchar(255);index:idx_users_stripe_customer_id"`
+	SubscriptionStatus *string   `gorm:"type:varchar(20)"`
+	SubscriptionID     *string   `gorm:"type:varchar(255)"`
+	TrialEndsAt        *time.Time
+	CurrentPeriodEnd   *time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	LastLoginAt        *time.Time
+
+	OAuthAccounts []baselineOAuthAccount `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Links         []baselineLink         `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Subscriptions []baselineSubscription `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (baselineUser) TableName() string { return "users" }
+
+type baselineOAuthAccount struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID         uuid.UUID `gorm:"type:uuid;not null;index:idx_oauth_accounts_user_id;constraint:OnDelete:CASCADE"`
+	Provider       string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_oauth_accounts_provider"`
+	ProviderUserID string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_oauth_accounts_provider"`
+	AccessToken    *string   `gorm:"type:text"`
+	RefreshToken   *string   `gorm:"type:text"`
+	ExpiresAt      *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	User           baselineUser `gorm:"foreignKey:UserID"`
+}
+
+func (baselineOAuthAccount) TableName() string { return "oauth_accounts" }
+
+type baselineMagicLinkToken struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Email     string    `gorm:"type:varchar(255);not null;index:idx_magic_link_tokens_email_expires"`
+	Token     string    `gorm:"type:varchar(255);uniqueIndex;not null"`
+	ExpiresAt time.Time `gorm:"not null;index:idx_magic_link_tokens_email_expires"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (baselineMagicLinkToken) TableName() string { return "magic_link_tokens" }
+
+type baselineLink struct {
+	ID               uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID           uuid.UUID `gorm:"type:uuid;not null;index:idx_links_user_id;index:idx_links_user_status;constraint:OnDelete:CASCADE"`
+	OriginalURL      string    `gorm:"type:text;not null"`
+	Title            *string   `gorm:"type:varchar(500)"`
+	Description      *string   `gorm:"type:text"`
+	Category         *string   `gorm:"type:varchar(100)"`
+	Platform         *string   `gorm:"type:varchar(100)"`
+	Tags             []string  `gorm:"type:text[]"`
+	Status           string    `gorm:"type:varchar(20);default:'active';index:idx_links_status;index:idx_links_user_status"`
+	IsHealthy        bool      `gorm:"default:true;index:idx_links_is_healthy"`
+	LastStatusCode   *int
+	LastResponseTime *int
+	LastCheckedAt    *time.Time `gorm:"index:idx_links_last_checked"`
+	LastWorkingAt    *time.Time
+	ClickCount       int `gorm:"default:0"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	ArchivedAt       *time.Time
+
+	User         baselineUser           `gorm:"foreignKey:UserID"`
+	Clicks       []baselineClick        `gorm:"foreignKey:LinkID;constraint:OnDelete:CASCADE"`
+	CheckHistory []baselineCheckHistory `gorm:"foreignKey:LinkID;constraint:OnDelete:CASCADE"`
+}
+
+func (baselineLink) TableName() string { return "links" }
+
+type baselineClick struct {
+	ID        uuid.UUID    `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	LinkID    uuid.UUID    `gorm:"type:uuid;not null;index:idx_clicks_link_id;index:idx_clicks_link_date"`
+	ClickedAt time.Time    `gorm:"default:now();index:idx_clicks_clicked_at;index:idx_clicks_link_date"`
+	Referrer  *string      `gorm:"type:text"`
+	UserAgent *string      `gorm:"type:text"`
+	IPAddress *string      `gorm:"type:varchar(45)"`
+	Country   *string      `gorm:"type:varchar(2)"`
+	Link      baselineLink `gorm:"foreignKey:LinkID"`
+}
+
+func (baselineClick) TableName() string { return "clicks" }
+
+type baselineCheckHistory struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	LinkID       uuid.UUID `gorm:"type:uuid;not null;index:idx_link_check_history_link_id;index:idx_link_check_history_link_date"`
+	CheckedAt    time.Time `gorm:"default:now();index:idx_link_check_history_checked_at;index:idx_link_check_history_link_date"`
+	StatusCode   int       `gorm:"not null"`
+	ResponseTime *int
+	IsHealthy    bool         `gorm:"not null"`
+	ErrorMessage *string      `gorm:"type:text"`
+	Link         baselineLink `gorm:"foreignKey:LinkID"`
+}
+
+func (baselineCheckHistory) TableName() string { return "link_check_history" }
+
+type baselineSubscription struct {
+	ID                   uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID               uuid.UUID `gorm:"type:uuid;not null;index;constraint:OnDelete:CASCADE"`
+	StripeSubscriptionID string    `gorm:"type:varchar(255);uniqueIndex;not null"`
+	StripeCustomerID     string    `gorm:"type:varchar(255);not null"`
+	Status               string    `gorm:"type:varchar(20);not null;index"`
+	Plan                 string    `gorm:"type:varchar(20);not null"`
+	CurrentPeriodStart   time.Time `gorm:"not null"`
+	CurrentPeriodEnd     time.Time `gorm:"not null"`
+	CancelAtPeriodEnd    bool      `gorm:"default:false"`
+	CanceledAt           *time.Time
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	User                 baselineUser `gorm:"foreignKey:UserID"`
+}
+
+func (baselineSubscription) TableName() string { return "subscriptions" }
+
+// openSchema connects to TEST_DATABASE_URL with a fresh, empty schema first on the search path,
+// so the test doesn't disturb the database the repository suites share
+func openSchema(t *testing.T, schema string) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	connConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		t.Fatalf("parsing TEST_DATABASE_URL: %v", err)
+	}
+	admin, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("connecting to the test database: %v", err)
+	}
+	if err := admin.Exec("DROP SCHEMA IF EXISTS " + schema + " CASCADE").Error; err != nil {
+		t.Fatalf("dropping schema %s: %v", schema, err)
+	}
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("creating schema %s: %v", schema, err)
+	}
+
+	connConfig.RuntimeParams["search_path"] = schema
+	sqlDB := stdlib.OpenDB(*connConfig)
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger:         logger.Discard,
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatalf("opening schema %s: %v", schema, err)
+	}
+	t.Cleanup(func() {
+		sqlDB.Close()
+		admin.Exec("DROP SCHEMA IF EXISTS " + schema + " CASCADE")
+		if adminDB, err := admin.DB(); err == nil {
+			adminDB.Close()
+		}
+	})
+	return db
+}
+
+func TestUpFromBaselineSchema(t *testing.T) {
+	db := openSchema(t, "linkvault_baseline_upgrade")
+	err := db.AutoMigrate(&baselineUser{}, &baselineOAuthAccount{}, &baselineMagicLinkToken{},
+		&baselineLink{}, &baselineClick{}, &baselineCheckHistory{}, &baselineSubscription{})
+	if err != nil {
+		t.Fatalf("creating the baseline schema: %v", err)
+	}
+	user := baselineUser{Email: "baseline@example.com"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("creating a baseline user: %v", err)
+	}
+	link := baselineLink{UserID: user.ID, OriginalURL: "https://example.com/product"}
+	if err := db.Omit("User").Create(&link).Error; err != nil {
+		t.Fatalf("creating a baseline link: %v", err)
+	}
+
+	migrator, err := migrations.New(db)
+	if err != nil {
+		t.Fatalf("loading migrations: %v", err)
+	}
+	if _, err := migrator.Up(t.Context()); err != nil {
+		t.Fatalf("Up on the baseline schema: %v", err)
+	}
+	if pending, err := migrator.Pending(t.Context()); err != nil || pending != 0 {
+		t.Fatalf("Pending after Up = %d, %v; want 0", pending, err)
+	}
+
+	// Rows written by the baseline release read back through the current models
+	upgraded, err := repository.NewLinkRepository(db, nil).GetByID(t.Context(), link.ID)
+	if err != nil {
+		t.Fatalf("reading the baseline link: %v", err)
+	}
+	if upgraded.OriginalURL != link.OriginalURL || upgraded.IsPrivate || upgraded.ConsecutiveFailures != 0 {
+		t.Fatalf("baseline link after Up = %+v, want its URL and the new columns' defaults", upgraded)
+	}
+
+	newStores := func(t *testing.T) repotest.Stores { return repotest.GormStores(t, db) }
+	contracts := []struct {
+		name string
+		run  func(*testing.T, repotest.StoresFactory)
+	}{
+		{"users", repotest.RunUserStoreContract},
+		{"links", repotest.RunLinkStoreContract},
+		{"checks", repotest.RunCheckStoreContract},
+		{"alerts", repotest.RunAlertStoreContract},
+		{"webhooks", repotest.RunWebhookStoreContract},
+		{"uptime", repotest.RunUptimeStoreContract},
+	}
+	for _, contract := range contracts {
+		t.Run(contract.name, func(t *testing.T) { contract.run(t, newStores) })
+	}
+}
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "subscriptions";
+DROP TABLE IF EXISTS "link_check_history";
+DROP TABLE IF EXISTS "clicks";
+DROP TABLE IF EXISTS "links";
+DROP TABLE IF EXISTS "magic_link_tokens";
+DROP TABLE IF EXISTS "oauth_accounts";
+DROP TABLE IF EXISTS "users";
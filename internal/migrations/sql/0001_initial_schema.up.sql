@@ -0,0 +1,133 @@
+-- Baseline schema, exactly as AutoMigrate created it in the release before versioned
+-- migrations. IF NOT EXISTS lets a database created by that release adopt it unchanged; every
+-- later change is its own additive migration.
+
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "email" varchar(255) NOT NULL,
+    "password_hash" varchar(255),
+    "name" varchar(255),
+    "avatar_url" text,
+    "email_verified" boolean DEFAULT false,
+    "plan" varchar(20) DEFAULT 'free',
+    "stripe_customer_id" varchar(255),
+    "subscription_status" varchar(20),
+    "subscription_id" varchar(255),
+    "trial_ends_at" timestamptz,
+    "current_period_end" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "last_login_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_users_stripe_customer_id" ON "users" ("stripe_customer_id");
+CREATE INDEX IF NOT EXISTS "idx_users_plan" ON "users" ("plan");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email");
+
+CREATE TABLE IF NOT EXISTS "oauth_accounts" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "provider" varchar(50) NOT NULL,
+    "provider_user_id" varchar(255) NOT NULL,
+    "access_token" text,
+    "refresh_token" text,
+    "expires_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_o_auth_accounts" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_oauth_accounts_provider" ON "oauth_accounts" ("provider","provider_user_id");
+CREATE INDEX IF NOT EXISTS "idx_oauth_accounts_user_id" ON "oauth_accounts" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "magic_link_tokens" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "email" varchar(255) NOT NULL,
+    "token" varchar(255) NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "used_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_magic_link_tokens_token" ON "magic_link_tokens" ("token");
+CREATE INDEX IF NOT EXISTS "idx_magic_link_tokens_email_expires" ON "magic_link_tokens" ("email","expires_at");
+
+CREATE TABLE IF NOT EXISTS "links" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "original_url" text NOT NULL,
+    "title" varchar(500),
+    "description" text,
+    "category" varchar(100),
+    "platform" varchar(100),
+    "tags" text[],
+    "status" varchar(20) DEFAULT 'active',
+    "is_healthy" boolean DEFAULT true,
+    "last_status_code" bigint,
+    "last_response_time" bigint,
+    "last_checked_at" timestamptz,
+    "last_working_at" timestamptz,
+    "click_count" bigint DEFAULT 0,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "archived_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_links" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_links_user_id" ON "links" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_links_last_checked" ON "links" ("last_checked_at");
+CREATE INDEX IF NOT EXISTS "idx_links_is_healthy" ON "links" ("is_healthy");
+CREATE INDEX IF NOT EXISTS "idx_links_status" ON "links" ("status");
+CREATE INDEX IF NOT EXISTS "idx_links_user_status" ON "links" ("user_id","status");
+
+CREATE TABLE IF NOT EXISTS "clicks" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "link_id" uuid NOT NULL,
+    "clicked_at" timestamptz DEFAULT now(),
+    "referrer" text,
+    "user_agent" text,
+    "ip_address" varchar(45),
+    "country" varchar(2),
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_links_clicks" FOREIGN KEY ("link_id") REFERENCES "links"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_clicks_clicked_at" ON "clicks" ("clicked_at");
+CREATE INDEX IF NOT EXISTS "idx_clicks_link_date" ON "clicks" ("link_id","clicked_at");
+CREATE INDEX IF NOT EXISTS "idx_clicks_link_id" ON "clicks" ("link_id");
+
+CREATE TABLE IF NOT EXISTS "link_check_history" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "link_id" uuid NOT NULL,
+    "checked_at" timestamptz DEFAULT now(),
+    "status_code" bigint NOT NULL,
+    "response_time" bigint,
+    "is_healthy" boolean NOT NULL,
+    "error_message" text,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_links_check_history" FOREIGN KEY ("link_id") REFERENCES "links"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_link_check_history_checked_at" ON "link_check_history" ("checked_at");
+CREATE INDEX IF NOT EXISTS "idx_link_check_history_link_date" ON "link_check_history" ("link_id","checked_at");
+CREATE INDEX IF NOT EXISTS "idx_link_check_history_link_id" ON "link_check_history" ("link_id");
+
+CREATE TABLE IF NOT EXISTS "subscriptions" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "stripe_subscription_id" varchar(255) NOT NULL,
+    "stripe_customer_id" varchar(255) NOT NULL,
+    "status" varchar(20) NOT NULL,
+    "plan" varchar(20) NOT NULL,
+    "current_period_start" timestamptz NOT NULL,
+    "current_period_end" timestamptz NOT NULL,
+    "cancel_at_period_end" boolean DEFAULT false,
+    "canceled_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_subscriptions" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_subscriptions_status" ON "subscriptions" ("status");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_subscriptions_stripe_subscription_id" ON "subscriptions" ("stripe_subscription_id");
+CREATE INDEX IF NOT EXISTS "idx_subscriptions_user_id" ON "subscriptions" ("user_id");
@@ -0,0 +1,13 @@
+-- Per-country destinations for a link.
+
+CREATE TABLE IF NOT EXISTS "link_geo_rules" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "link_id" uuid NOT NULL,
+    "country" varchar(2) NOT NULL,
+    "destination_url" text NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_links_geo_rules" FOREIGN KEY ("link_id") REFERENCES "links"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_link_geo_rules_link_country" ON "link_geo_rules" ("link_id","country");
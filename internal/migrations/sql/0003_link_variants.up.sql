@@ -0,0 +1,17 @@
+-- Weighted A/B destinations, and the variant each click was sent to.
+
+CREATE TABLE IF NOT EXISTS "link_variants" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "link_id" uuid NOT NULL,
+    "label" varchar(100) NOT NULL,
+    "destination_url" text NOT NULL,
+    "weight" bigint NOT NULL DEFAULT 1,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_links_variants" FOREIGN KEY ("link_id") REFERENCES "links"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_link_variants_link_id" ON "link_variants" ("link_id");
+
+ALTER TABLE "clicks" ADD COLUMN IF NOT EXISTS "variant_id" uuid;
+CREATE INDEX IF NOT EXISTS "idx_clicks_variant_id" ON "clicks" ("variant_id");
@@ -0,0 +1,14 @@
+-- Per-platform deep links.
+
+CREATE TABLE IF NOT EXISTS "link_device_rules" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "link_id" uuid NOT NULL,
+    "platform" varchar(20) NOT NULL,
+    "deep_link_url" text NOT NULL,
+    "fallback_url" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_links_device_rules" FOREIGN KEY ("link_id") REFERENCES "links"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_link_device_rules_link_platform" ON "link_device_rules" ("link_id","platform");
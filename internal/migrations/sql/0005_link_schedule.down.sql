@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS "idx_links_expires_at";
+DROP INDEX IF EXISTS "idx_links_starts_at";
+ALTER TABLE "links"
+    DROP COLUMN IF EXISTS "fallback_url",
+    DROP COLUMN IF EXISTS "max_clicks",
+    DROP COLUMN IF EXISTS "expires_at",
+    DROP COLUMN IF EXISTS "starts_at";
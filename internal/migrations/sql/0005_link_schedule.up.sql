@@ -0,0 +1,9 @@
+-- Scheduling, expiry and click caps.
+
+ALTER TABLE "links"
+    ADD COLUMN IF NOT EXISTS "starts_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "expires_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "max_clicks" bigint,
+    ADD COLUMN IF NOT EXISTS "fallback_url" text;
+CREATE INDEX IF NOT EXISTS "idx_links_starts_at" ON "links" ("starts_at");
+CREATE INDEX IF NOT EXISTS "idx_links_expires_at" ON "links" ("expires_at");
@@ -0,0 +1,3 @@
+ALTER TABLE "links"
+    DROP COLUMN IF EXISTS "is_private",
+    DROP COLUMN IF EXISTS "password_hash";
@@ -0,0 +1,5 @@
+-- Password-protected and private links.
+
+ALTER TABLE "links"
+    ADD COLUMN IF NOT EXISTS "password_hash" varchar(255),
+    ADD COLUMN IF NOT EXISTS "is_private" boolean DEFAULT false;
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS "link_channels";
+
+ALTER TABLE "links"
+    DROP COLUMN IF EXISTS "utm_content",
+    DROP COLUMN IF EXISTS "utm_campaign",
+    DROP COLUMN IF EXISTS "utm_medium",
+    DROP COLUMN IF EXISTS "utm_source";
+
+ALTER TABLE "users"
+    DROP COLUMN IF EXISTS "utm_content",
+    DROP COLUMN IF EXISTS "utm_campaign",
+    DROP COLUMN IF EXISTS "utm_medium",
+    DROP COLUMN IF EXISTS "utm_source";
@@ -0,0 +1,28 @@
+-- UTM templates: a user default, a per-link template and per-channel overrides.
+
+ALTER TABLE "users"
+    ADD COLUMN IF NOT EXISTS "utm_source" varchar(255),
+    ADD COLUMN IF NOT EXISTS "utm_medium" varchar(255),
+    ADD COLUMN IF NOT EXISTS "utm_campaign" varchar(255),
+    ADD COLUMN IF NOT EXISTS "utm_content" varchar(255);
+
+ALTER TABLE "links"
+    ADD COLUMN IF NOT EXISTS "utm_source" varchar(255),
+    ADD COLUMN IF NOT EXISTS "utm_medium" varchar(255),
+    ADD COLUMN IF NOT EXISTS "utm_campaign" varchar(255),
+    ADD COLUMN IF NOT EXISTS "utm_content" varchar(255);
+
+CREATE TABLE IF NOT EXISTS "link_channels" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "link_id" uuid NOT NULL,
+    "name" varchar(50) NOT NULL,
+    "utm_source" varchar(255),
+    "utm_medium" varchar(255),
+    "utm_campaign" varchar(255),
+    "utm_content" varchar(255),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_links_channels" FOREIGN KEY ("link_id") REFERENCES "links"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_link_channels_link_channel" ON "link_channels" ("link_id","name");
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "affiliate_tags";
+
+ALTER TABLE "links"
+    DROP COLUMN IF EXISTS "affiliate_id",
+    DROP COLUMN IF EXISTS "affiliate_network";
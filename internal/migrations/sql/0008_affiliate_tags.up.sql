@@ -0,0 +1,16 @@
+-- The affiliate network and ID detected on a link, and the tags a user expects.
+
+ALTER TABLE "links"
+    ADD COLUMN IF NOT EXISTS "affiliate_network" varchar(50),
+    ADD COLUMN IF NOT EXISTS "affiliate_id" varchar(255);
+
+CREATE TABLE IF NOT EXISTS "affiliate_tags" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "network" varchar(50) NOT NULL,
+    "tag" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_affiliate_tags" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_affiliate_tags_user_network_tag" ON "affiliate_tags" ("user_id","network","tag");
@@ -0,0 +1,7 @@
+ALTER TABLE "link_check_history"
+    DROP COLUMN IF EXISTS "soft_broken",
+    DROP COLUMN IF EXISTS "hop_count",
+    DROP COLUMN IF EXISTS "redirect_chain",
+    DROP COLUMN IF EXISTS "final_url";
+
+ALTER TABLE "links" DROP COLUMN IF EXISTS "final_url";
@@ -0,0 +1,9 @@
+-- Where each check's redirect chain went, and whether the page looked soft-broken.
+
+ALTER TABLE "links" ADD COLUMN IF NOT EXISTS "final_url" text;
+
+ALTER TABLE "link_check_history"
+    ADD COLUMN IF NOT EXISTS "final_url" text,
+    ADD COLUMN IF NOT EXISTS "redirect_chain" jsonb,
+    ADD COLUMN IF NOT EXISTS "hop_count" bigint DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "soft_broken" boolean DEFAULT false;
@@ -0,0 +1,17 @@
+-- User-defined patterns that mark a page as broken.
+
+CREATE TABLE IF NOT EXISTS "content_rules" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "link_id" uuid,
+    "name" varchar(100) NOT NULL,
+    "pattern" text NOT NULL,
+    "is_regex" boolean DEFAULT false,
+    "message" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_content_rules_link" FOREIGN KEY ("link_id") REFERENCES "links"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_content_rules_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_content_rules_link_id" ON "content_rules" ("link_id");
+CREATE INDEX IF NOT EXISTS "idx_content_rules_user_id" ON "content_rules" ("user_id");
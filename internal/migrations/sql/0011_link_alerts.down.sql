@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "alerts";
+DROP TABLE IF EXISTS "notification_preferences";
+
+ALTER TABLE "links"
+    DROP COLUMN IF EXISTS "recovered_at",
+    DROP COLUMN IF EXISTS "alerted_down_at",
+    DROP COLUMN IF EXISTS "consecutive_failures";
@@ -0,0 +1,36 @@
+-- Link-down alert state, queued alerts and notification preferences.
+
+ALTER TABLE "links"
+    ADD COLUMN IF NOT EXISTS "consecutive_failures" bigint DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "alerted_down_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "recovered_at" timestamptz;
+
+CREATE TABLE IF NOT EXISTS "notification_preferences" (
+    "user_id" uuid,
+    "email_enabled" boolean DEFAULT true,
+    "webhook_enabled" boolean DEFAULT false,
+    "webhook_url" text,
+    "failure_threshold" bigint DEFAULT 3,
+    "digest_minutes" bigint DEFAULT 60,
+    "recovery_notices" boolean DEFAULT true,
+    "flap_cooldown_mins" bigint DEFAULT 60,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("user_id"),
+    CONSTRAINT "fk_notification_preferences_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS "alerts" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "link_id" uuid NOT NULL,
+    "kind" varchar(20) NOT NULL,
+    "message" text NOT NULL,
+    "delivered_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_alerts_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_alerts_link" FOREIGN KEY ("link_id") REFERENCES "links"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_alerts_user_pending" ON "alerts" ("user_id","delivered_at");
+CREATE INDEX IF NOT EXISTS "idx_alerts_link_id" ON "alerts" ("link_id");
@@ -0,0 +1,7 @@
+ALTER TABLE "notification_preferences"
+    DROP COLUMN IF EXISTS "last_weekly_summary_at",
+    DROP COLUMN IF EXISTS "weekly_summary",
+    DROP COLUMN IF EXISTS "discord_webhook_url",
+    DROP COLUMN IF EXISTS "discord_enabled",
+    DROP COLUMN IF EXISTS "slack_webhook_url",
+    DROP COLUMN IF EXISTS "slack_enabled";
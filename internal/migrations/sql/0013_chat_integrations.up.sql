@@ -0,0 +1,9 @@
+-- Slack and Discord alerts and the weekly summary.
+
+ALTER TABLE "notification_preferences"
+    ADD COLUMN IF NOT EXISTS "slack_enabled" boolean DEFAULT false,
+    ADD COLUMN IF NOT EXISTS "slack_webhook_url" text,
+    ADD COLUMN IF NOT EXISTS "discord_enabled" boolean DEFAULT false,
+    ADD COLUMN IF NOT EXISTS "discord_webhook_url" text,
+    ADD COLUMN IF NOT EXISTS "weekly_summary" boolean DEFAULT true,
+    ADD COLUMN IF NOT EXISTS "last_weekly_summary_at" timestamptz;
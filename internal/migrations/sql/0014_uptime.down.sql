@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "link_check_rollups";
+DROP TABLE IF EXISTS "link_incidents";
@@ -0,0 +1,28 @@
+-- Downtime incidents and daily check rollups for uptime reports.
+
+CREATE TABLE IF NOT EXISTS "link_incidents" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "link_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "started_at" timestamptz NOT NULL,
+    "ended_at" timestamptz,
+    "cause" text,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_link_incidents_link" FOREIGN KEY ("link_id") REFERENCES "links"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_link_incidents_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_link_incidents_user_started" ON "link_incidents" ("user_id","started_at");
+CREATE INDEX IF NOT EXISTS "idx_link_incidents_link_started" ON "link_incidents" ("link_id","started_at");
+
+CREATE TABLE IF NOT EXISTS "link_check_rollups" (
+    "link_id" uuid,
+    "day" date,
+    "user_id" uuid NOT NULL,
+    "checks" bigint NOT NULL,
+    "healthy_checks" bigint NOT NULL,
+    "response_times" jsonb,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("link_id","day"),
+    CONSTRAINT "fk_link_check_rollups_link" FOREIGN KEY ("link_id") REFERENCES "links"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_link_check_rollups_user_day" ON "link_check_rollups" ("user_id","day");
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "idx_links_next_check_at";
+ALTER TABLE "links"
+    DROP COLUMN IF EXISTS "check_click_count",
+    DROP COLUMN IF EXISTS "next_check_at";
@@ -0,0 +1,6 @@
+-- Adaptive check scheduling.
+
+ALTER TABLE "links"
+    ADD COLUMN IF NOT EXISTS "next_check_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "check_click_count" bigint DEFAULT 0;
+CREATE INDEX IF NOT EXISTS "idx_links_next_check_at" ON "links" ("next_check_at") WHERE status = 'active' AND archived_at IS NULL;
@@ -0,0 +1 @@
+ALTER TABLE "link_check_history" DROP COLUMN IF EXISTS "rate_limited";
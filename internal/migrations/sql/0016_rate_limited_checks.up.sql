@@ -0,0 +1,3 @@
+-- Checks the host throttled, which say nothing about health.
+
+ALTER TABLE "link_check_history" ADD COLUMN IF NOT EXISTS "rate_limited" boolean DEFAULT false;
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AffiliateTag is a publisher tag/ID a user expects to see on their links for a network
+type AffiliateTag struct {
+	ID      uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_affiliate_tags_user_network_tag;constraint:OnDelete:CASCADE" json:"user_id"`
+	Network string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_affiliate_tags_user_network_tag" json:"network"` // merchant.Network* constant
+	Tag     string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_affiliate_tags_user_network_tag" json:"tag"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// BeforeCreate hook to generate UUID
+func (a *AffiliateTag) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (AffiliateTag) TableName() string {
+	return "affiliate_tags"
+}
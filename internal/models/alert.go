@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Alert kinds
+const (
+	AlertKindDown      = "down"
+	AlertKindRecovered = "recovered"
+)
+
+// Alert is a pending or delivered link-down/recovery notification, batched into per-user digests
+type Alert struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index:idx_alerts_user_pending;constraint:OnDelete:CASCADE" json:"user_id"`
+	LinkID      uuid.UUID  `gorm:"type:uuid;not null;index:idx_alerts_link_id;constraint:OnDelete:CASCADE" json:"link_id"`
+	Kind        string     `gorm:"type:varchar(20);not null" json:"kind"` // 'down', 'recovered'
+	Message     string     `gorm:"type:text;not null" json:"message"`
+	DeliveredAt *time.Time `gorm:"index:idx_alerts_user_pending" json:"delivered_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID" json:"-"`
+	Link Link `gorm:"foreignKey:LinkID" json:"-"`
+}
+
+// BeforeCreate hook to generate UUID
+func (a *Alert) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (Alert) TableName() string {
+	return "alerts"
+}
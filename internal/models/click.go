@@ -9,13 +9,14 @@ import (
 
 // Click represents a click on an affiliate link (Pro feature)
 type Click struct {
-	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	LinkID    uuid.UUID `gorm:"type:uuid;not null;index:idx_clicks_link_id;index:idx_clicks_link_date" json:"link_id"`
-	ClickedAt time.Time `gorm:"default:now();index:idx_clicks_clicked_at;index:idx_clicks_link_date" json:"clicked_at"`
-	Referrer  *string   `gorm:"type:text" json:"referrer"`
-	UserAgent *string   `gorm:"type:text" json:"user_agent"`
-	IPAddress *string   `gorm:"type:varchar(45)" json:"ip_address"` // IPv6 compatible
-	Country   *string   `gorm:"type:varchar(2)" json:"country"`     // ISO country code
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	LinkID    uuid.UUID  `gorm:"type:uuid;not null;index:idx_clicks_link_id;index:idx_clicks_link_date" json:"link_id"`
+	ClickedAt time.Time  `gorm:"default:now();index:idx_clicks_clicked_at;index:idx_clicks_link_date" json:"clicked_at"`
+	Referrer  *string    `gorm:"type:text" json:"referrer"`
+	UserAgent *string    `gorm:"type:text" json:"user_agent"`
+	IPAddress *string    `gorm:"type:varchar(45)" json:"ip_address"`                      // IPv6 compatible
+	Country   *string    `gorm:"type:varchar(2)" json:"country"`                          // ISO country code
+	VariantID *uuid.UUID `gorm:"type:uuid;index:idx_clicks_variant_id" json:"variant_id"` // A/B variant served, if any
 
 	// Relationships
 	Link Link `gorm:"foreignKey:LinkID" json:"-"`
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ContentRule is a user-defined text or regex rule that marks a checked page unhealthy when it matches
+type ContentRule struct {
+	ID      uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID  uuid.UUID  `gorm:"type:uuid;not null;index:idx_content_rules_user_id;constraint:OnDelete:CASCADE" json:"user_id"`
+	LinkID  *uuid.UUID `gorm:"type:uuid;index:idx_content_rules_link_id;constraint:OnDelete:CASCADE" json:"link_id"` // nil applies to all of the user's links
+	Name    string     `gorm:"type:varchar(100);not null" json:"name"`
+	Pattern string     `gorm:"type:text;not null" json:"pattern"`
+	IsRegex bool       `gorm:"default:false" json:"is_regex"`
+	Message *string    `gorm:"type:text" json:"message"` // recorded as the check's error message
+
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relationships
+	User User  `gorm:"foreignKey:UserID" json:"-"`
+	Link *Link `gorm:"foreignKey:LinkID" json:"-"`
+}
+
+// BeforeCreate hook to generate UUID
+func (r *ContentRule) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (ContentRule) TableName() string {
+	return "content_rules"
+}
@@ -7,6 +7,13 @@ import (
 	"gorm.io/gorm"
 )
 
+// Link lifecycle statuses
+const (
+	LinkStatusScheduled = "scheduled"
+	LinkStatusActive    = "active"
+	LinkStatusExpired   = "expired"
+)
+
 // Link represents an affiliate link
 type Link struct {
 	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
@@ -20,6 +27,10 @@ type Link struct {
 	Platform *string  `gorm:"type:varchar(100)" json:"platform"`
 	Tags     []string `gorm:"type:text[]" json:"tags"`
 
+	// Detected from OriginalURL by the merchant parser
+	AffiliateNetwork *string `gorm:"type:varchar(50)" json:"affiliate_network"`
+	AffiliateID      *string `gorm:"type:varchar(255)" json:"affiliate_id"`
+
 	// Status tracking
 	Status           string     `gorm:"type:varchar(20);default:'active';index:idx_links_status;index:idx_links_user_status" json:"status"`
 	IsHealthy        bool       `gorm:"default:true;index:idx_links_is_healthy" json:"is_healthy"`
@@ -27,6 +38,30 @@ type Link struct {
 	LastResponseTime *int       `json:"last_response_time"`
 	LastCheckedAt    *time.Time `gorm:"index:idx_links_last_checked" json:"last_checked_at"`
 	LastWorkingAt    *time.Time `json:"last_working_at"`
+	FinalURL         *string    `gorm:"type:text" json:"final_url"` // where the redirect chain ended on the last check
+
+	// Adaptive check scheduling
+	NextCheckAt     *time.Time `gorm:"index:idx_links_next_check_at,where:status = 'active' AND archived_at IS NULL" json:"next_check_at"` // nil means check as soon as possible
+	CheckClickCount int        `gorm:"default:0" json:"-"`                                                                                 // ClickCount at the last check, for click velocity
+
+	// Alerting state
+	ConsecutiveFailures int        `gorm:"default:0" json:"consecutive_failures"`
+	AlertedDownAt       *time.Time `json:"alerted_down_at"` // set while a down alert is outstanding
+	RecoveredAt         *time.Time `json:"recovered_at"`    // last time a down link healed
+
+	// Guardrails: scheduling, expiry and click caps
+	StartsAt    *time.Time `gorm:"index:idx_links_starts_at" json:"starts_at"`
+	ExpiresAt   *time.Time `gorm:"index:idx_links_expires_at" json:"expires_at"`
+	MaxClicks   *int       `json:"max_clicks"`
+	FallbackURL *string    `gorm:"type:text" json:"fallback_url"` // served once expired; 410 Gone if unset
+
+	// Access control: a password and/or a signed access token gates the redirect
+	PasswordHash *string `gorm:"type:varchar(255)" json:"-"`
+	IsPrivate    bool    `gorm:"default:false" json:"is_private"` // token-only, no password form
+	HasPassword  bool    `gorm:"-" json:"has_password"`
+
+	// UTM tags merged into the destination at redirect time, overriding the user's defaults
+	UTM UTMTemplate `gorm:"embedded;embeddedPrefix:utm_" json:"utm"`
 
 	// Analytics (Pro feature)
 	ClickCount int `gorm:"default:0" json:"click_count"`
@@ -40,6 +75,10 @@ type Link struct {
 	User         User               `gorm:"foreignKey:UserID" json:"-"`
 	Clicks       []Click            `gorm:"foreignKey:LinkID;constraint:OnDelete:CASCADE" json:"-"`
 	CheckHistory []LinkCheckHistory `gorm:"foreignKey:LinkID;constraint:OnDelete:CASCADE" json:"-"`
+	GeoRules     []LinkGeoRule      `gorm:"foreignKey:LinkID;constraint:OnDelete:CASCADE" json:"geo_rules,omitempty"`
+	Variants     []LinkVariant      `gorm:"foreignKey:LinkID;constraint:OnDelete:CASCADE" json:"variants,omitempty"`
+	DeviceRules  []LinkDeviceRule   `gorm:"foreignKey:LinkID;constraint:OnDelete:CASCADE" json:"device_rules,omitempty"`
+	Channels     []LinkChannel      `gorm:"foreignKey:LinkID;constraint:OnDelete:CASCADE" json:"channels,omitempty"`
 }
 
 // BeforeCreate hook to generate UUID
@@ -54,3 +93,32 @@ func (l *Link) BeforeCreate(tx *gorm.DB) error {
 func (Link) TableName() string {
 	return "links"
 }
+
+// AfterFind hook to expose whether a password is set without leaking the hash
+func (l *Link) AfterFind(tx *gorm.DB) error {
+	l.HasPassword = l.PasswordHash != nil
+	return nil
+}
+
+// RequiresAccess checks if visitors must present a password or access token
+func (l *Link) RequiresAccess() bool {
+	return l.PasswordHash != nil || l.IsPrivate
+}
+
+// IsStarted checks if the link's scheduled start time has passed
+func (l *Link) IsStarted(now time.Time) bool {
+	return l.StartsAt == nil || !now.Before(*l.StartsAt)
+}
+
+// IsPastLimits checks if the link has passed its expiry time or click cap
+func (l *Link) IsPastLimits(now time.Time) bool {
+	if l.ExpiresAt != nil && !now.Before(*l.ExpiresAt) {
+		return true
+	}
+	return l.MaxClicks != nil && l.ClickCount >= *l.MaxClicks
+}
+
+// IsExpired checks if the link has been marked expired or has passed its limits
+func (l *Link) IsExpired(now time.Time) bool {
+	return l.Status == LinkStatusExpired || l.IsPastLimits(now)
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LinkChannel is a channel-specific variant of a link (e.g. /r/:id/instagram) with its own UTM tags
+type LinkChannel struct {
+	ID     uuid.UUID   `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	LinkID uuid.UUID   `gorm:"type:uuid;not null;uniqueIndex:idx_link_channels_link_channel" json:"link_id"`
+	Name   string      `gorm:"type:varchar(50);not null;uniqueIndex:idx_link_channels_link_channel" json:"name"` // URL-safe slug
+	UTM    UTMTemplate `gorm:"embedded;embeddedPrefix:utm_" json:"utm"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	Link Link `gorm:"foreignKey:LinkID" json:"-"`
+}
+
+// BeforeCreate hook to generate UUID
+func (ch *LinkChannel) BeforeCreate(tx *gorm.DB) error {
+	if ch.ID == uuid.Nil {
+		ch.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (LinkChannel) TableName() string {
+	return "link_channels"
+}
@@ -17,10 +17,24 @@ type LinkCheckHistory struct {
 	IsHealthy    bool      `gorm:"not null" json:"is_healthy"`
 	ErrorMessage *string   `gorm:"type:text" json:"error_message"`
 
+	// Redirect tracing
+	FinalURL      *string       `gorm:"type:text" json:"final_url"`
+	RedirectChain []RedirectHop `gorm:"type:jsonb;serializer:json" json:"redirect_chain"`
+	HopCount      int           `gorm:"default:0" json:"hop_count"`
+	SoftBroken    bool          `gorm:"default:false" json:"soft_broken"`  // 2xx, but drifted to a generic page
+	RateLimited   bool          `gorm:"default:false" json:"rate_limited"` // throttled by the host; says nothing about health
+
 	// Relationships
 	Link Link `gorm:"foreignKey:LinkID" json:"-"`
 }
 
+// RedirectHop is a single request in a checked link's redirect chain
+type RedirectHop struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+	LatencyMs  int    `json:"latency_ms"`
+}
+
 // BeforeCreate hook to generate UUID
 func (l *LinkCheckHistory) BeforeCreate(tx *gorm.DB) error {
 	if l.ID == uuid.Nil {
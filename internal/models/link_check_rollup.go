@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ResponseTimeBuckets are the upper bounds, in milliseconds, of the response time histogram
+// buckets. A final overflow bucket catches anything slower.
+var ResponseTimeBuckets = []int{50, 100, 200, 300, 500, 750, 1000, 1500, 2000, 3000, 5000, 7500, 10000, 15000}
+
+// ResponseHistogram counts response times per ResponseTimeBuckets bucket, plus an overflow
+// bucket. Histograms of different days merge by adding counts, which keeps percentiles over
+// long windows cheap.
+type ResponseHistogram []int64
+
+// NewResponseHistogram returns an empty histogram
+func NewResponseHistogram() ResponseHistogram {
+	return make(ResponseHistogram, len(ResponseTimeBuckets)+1)
+}
+
+// Add records a response time in milliseconds
+func (h ResponseHistogram) Add(ms int) {
+	for i, bound := range ResponseTimeBuckets {
+		if ms <= bound {
+			h[i]++
+			return
+		}
+	}
+	h[len(ResponseTimeBuckets)]++
+}
+
+// Merge adds other's counts into h
+func (h ResponseHistogram) Merge(other ResponseHistogram) {
+	for i := range other {
+		if i < len(h) {
+			h[i] += other[i]
+		}
+	}
+}
+
+// Percentile estimates the p-th percentile (0-100) in milliseconds by interpolating within
+// the bucket it falls in. It returns nil for an empty histogram.
+func (h ResponseHistogram) Percentile(p float64) *int {
+	var total int64
+	for _, n := range h {
+		total += n
+	}
+	if total == 0 {
+		return nil
+	}
+
+	rank := p / 100 * float64(total)
+	var seen int64
+	for i, n := range h {
+		if n == 0 || float64(seen+n) < rank {
+			seen += n
+			continue
+		}
+		lower := 0
+		if i > 0 {
+			lower = ResponseTimeBuckets[i-1]
+		}
+		if i == len(ResponseTimeBuckets) {
+			// Overflow bucket has no upper bound; report its lower edge
+			return &lower
+		}
+		upper := ResponseTimeBuckets[i]
+		ms := lower + int(float64(upper-lower)*(rank-float64(seen))/float64(n))
+		return &ms
+	}
+	return nil
+}
+
+// LinkCheckRollup aggregates one link's health checks over one UTC day
+type LinkCheckRollup struct {
+	LinkID        uuid.UUID         `gorm:"type:uuid;primaryKey;constraint:OnDelete:CASCADE" json:"link_id"`
+	Day           time.Time         `gorm:"type:date;primaryKey;index:idx_link_check_rollups_user_day,priority:2" json:"day"`
+	UserID        uuid.UUID         `gorm:"type:uuid;not null;index:idx_link_check_rollups_user_day,priority:1" json:"user_id"`
+	Checks        int64             `gorm:"not null" json:"checks"`
+	HealthyChecks int64             `gorm:"not null" json:"healthy_checks"`
+	ResponseTimes ResponseHistogram `gorm:"type:jsonb;serializer:json" json:"response_times"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	Link Link `gorm:"foreignKey:LinkID" json:"-"`
+}
+
+// TableName specifies the table name
+func (LinkCheckRollup) TableName() string {
+	return "link_check_rollups"
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LinkDeviceRule sends visitors on a given device platform to an app deep link (Pro feature)
+type LinkDeviceRule struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	LinkID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_link_device_rules_link_platform" json:"link_id"`
+	Platform    string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_link_device_rules_link_platform" json:"platform"` // 'ios', 'android', 'desktop'
+	DeepLinkURL string    `gorm:"type:text;not null" json:"deep_link_url"`                                                   // app scheme or universal link
+	FallbackURL *string   `gorm:"type:text" json:"fallback_url"`                                                             // web fallback, defaults to the link's destination
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	Link Link `gorm:"foreignKey:LinkID" json:"-"`
+}
+
+// BeforeCreate hook to generate UUID
+func (r *LinkDeviceRule) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (LinkDeviceRule) TableName() string {
+	return "link_device_rules"
+}
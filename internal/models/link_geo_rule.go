@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LinkGeoRule routes visitors from a given country to an alternate destination (Guardrails feature)
+type LinkGeoRule struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	LinkID         uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_link_geo_rules_link_country" json:"link_id"`
+	Country        string    `gorm:"type:varchar(2);not null;uniqueIndex:idx_link_geo_rules_link_country" json:"country"` // ISO country code
+	DestinationURL string    `gorm:"type:text;not null" json:"destination_url"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	Link Link `gorm:"foreignKey:LinkID" json:"-"`
+}
+
+// BeforeCreate hook to generate UUID
+func (r *LinkGeoRule) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (LinkGeoRule) TableName() string {
+	return "link_geo_rules"
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LinkIncident is a period during which a link failed its health checks. It opens on the first
+// failed check and closes on the next healthy one.
+type LinkIncident struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	LinkID    uuid.UUID  `gorm:"type:uuid;not null;index:idx_link_incidents_link_started;constraint:OnDelete:CASCADE" json:"link_id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index:idx_link_incidents_user_started;constraint:OnDelete:CASCADE" json:"user_id"`
+	StartedAt time.Time  `gorm:"not null;index:idx_link_incidents_link_started;index:idx_link_incidents_user_started" json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`               // nil while the link is still down
+	Cause     *string    `gorm:"type:text" json:"cause"` // error of the check that opened the incident
+
+	// Relationships
+	Link Link `gorm:"foreignKey:LinkID" json:"-"`
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// BeforeCreate hook to generate UUID
+func (i *LinkIncident) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (LinkIncident) TableName() string {
+	return "link_incidents"
+}
+
+// Duration returns how long the incident lasted, measuring open incidents up to now
+func (i *LinkIncident) Duration(now time.Time) time.Duration {
+	if i.EndedAt != nil {
+		return i.EndedAt.Sub(i.StartedAt)
+	}
+	return now.Sub(i.StartedAt)
+}
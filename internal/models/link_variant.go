@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LinkVariant is one weighted destination of an A/B split link (Pro feature)
+type LinkVariant struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	LinkID         uuid.UUID `gorm:"type:uuid;not null;index:idx_link_variants_link_id" json:"link_id"`
+	Label          string    `gorm:"type:varchar(100);not null" json:"label"`
+	DestinationURL string    `gorm:"type:text;not null" json:"destination_url"`
+	Weight         int       `gorm:"not null;default:1" json:"weight"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	Link Link `gorm:"foreignKey:LinkID" json:"-"`
+}
+
+// BeforeCreate hook to generate UUID
+func (v *LinkVariant) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (LinkVariant) TableName() string {
+	return "link_variants"
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationPreference holds a user's alerting settings
+type NotificationPreference struct {
+	UserID uuid.UUID `gorm:"type:uuid;primary_key;constraint:OnDelete:CASCADE" json:"user_id"`
+
+	// Channels
+	EmailEnabled   bool    `gorm:"default:true" json:"email_enabled"`
+	WebhookEnabled bool    `gorm:"default:false" json:"webhook_enabled"`
+	WebhookURL     *string `gorm:"type:text" json:"webhook_url"`
+
+	// Chat integrations (Slack/Discord incoming webhooks)
+	SlackEnabled      bool    `gorm:"default:false" json:"slack_enabled"`
+	SlackWebhookURL   *string `gorm:"type:text" json:"slack_webhook_url"`
+	DiscordEnabled    bool    `gorm:"default:false" json:"discord_enabled"`
+	DiscordWebhookURL *string `gorm:"type:text" json:"discord_webhook_url"`
+
+	// Alert behaviour
+	FailureThreshold int  `gorm:"default:3" json:"failure_threshold"`      // consecutive failed checks before a link is reported down
+	DigestMinutes    int  `gorm:"default:60" json:"digest_minutes"`        // batch alerts for this long; 0 sends immediately
+	RecoveryNotices  bool `gorm:"default:true" json:"recovery_notices"`    // notify when a down link heals
+	FlapCooldownMins int  `gorm:"default:60" json:"flap_cooldown_minutes"` // suppress re-alerting a link that recovered this recently
+
+	// Weekly summary posted to chat integrations
+	WeeklySummary       bool       `gorm:"default:true" json:"weekly_summary"`
+	LastWeeklySummaryAt *time.Time `json:"last_weekly_summary_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// TableName specifies the table name
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+// DefaultNotificationPreference returns the settings used until a user saves their own
+func DefaultNotificationPreference(userID uuid.UUID) *NotificationPreference {
+	return &NotificationPreference{
+		UserID:           userID,
+		EmailEnabled:     true,
+		FailureThreshold: 3,
+		DigestMinutes:    60,
+		RecoveryNotices:  true,
+		FlapCooldownMins: 60,
+		WeeklySummary:    true,
+	}
+}
@@ -7,6 +7,12 @@ import (
 	"gorm.io/gorm"
 )
 
+// Subscription plans
+const (
+	PlanFree = "free"
+	PlanPro  = "pro"
+)
+
 // User represents a user in the system
 type User struct {
 	ID            uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
@@ -24,6 +30,9 @@ type User struct {
 	TrialEndsAt        *time.Time `json:"trial_ends_at"`
 	CurrentPeriodEnd   *time.Time `json:"current_period_end"`
 
+	// Default UTM tags applied to every link unless the link or channel overrides them
+	DefaultUTM UTMTemplate `gorm:"embedded;embeddedPrefix:utm_" json:"default_utm"`
+
 	// Timestamps
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
@@ -33,6 +42,7 @@ type User struct {
 	OAuthAccounts []OAuthAccount `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
 	Links         []Link         `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
 	Subscriptions []Subscription `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	AffiliateTags []AffiliateTag `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
 }
 
 // BeforeCreate hook to generate UUID
@@ -47,3 +57,8 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 func (User) TableName() string {
 	return "users"
 }
+
+// IsPro checks if the user is on the Pro plan
+func (u *User) IsPro() bool {
+	return u.Plan == PlanPro
+}
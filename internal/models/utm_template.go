@@ -0,0 +1,43 @@
+package models
+
+// UTMTemplate holds UTM tag templates that are merged into destination URLs at redirect time.
+// Values may reference variables such as {platform}, {category} or {channel}.
+type UTMTemplate struct {
+	Source   *string `gorm:"type:varchar(255)" json:"source"`
+	Medium   *string `gorm:"type:varchar(255)" json:"medium"`
+	Campaign *string `gorm:"type:varchar(255)" json:"campaign"`
+	Content  *string `gorm:"type:varchar(255)" json:"content"`
+}
+
+// Overlay returns a copy of t with every field set in override taking precedence
+func (t UTMTemplate) Overlay(override UTMTemplate) UTMTemplate {
+	if override.Source != nil {
+		t.Source = override.Source
+	}
+	if override.Medium != nil {
+		t.Medium = override.Medium
+	}
+	if override.Campaign != nil {
+		t.Campaign = override.Campaign
+	}
+	if override.Content != nil {
+		t.Content = override.Content
+	}
+	return t
+}
+
+// Params returns the template as utm_* query parameter names mapped to their raw templates
+func (t UTMTemplate) Params() map[string]string {
+	params := make(map[string]string, 4)
+	for key, value := range map[string]*string{
+		"utm_source":   t.Source,
+		"utm_medium":   t.Medium,
+		"utm_campaign": t.Campaign,
+		"utm_content":  t.Content,
+	} {
+		if value != nil && *value != "" {
+			params[key] = *value
+		}
+	}
+	return params
+}
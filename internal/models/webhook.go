@@ -0,0 +1,106 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Webhook event types
+const (
+	WebhookEventLinkCreated   = "link.created"
+	WebhookEventLinkDown      = "link.down"
+	WebhookEventLinkRecovered = "link.recovered"
+	WebhookEventClickRecorded = "click.recorded"
+)
+
+// WebhookEvents lists every event an endpoint can subscribe to
+var WebhookEvents = []string{
+	WebhookEventLinkCreated,
+	WebhookEventLinkDown,
+	WebhookEventLinkRecovered,
+	WebhookEventClickRecorded,
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookEndpoint is a user-registered URL that receives signed event payloads
+type WebhookEndpoint struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index:idx_webhook_endpoints_user_id;constraint:OnDelete:CASCADE" json:"user_id"`
+	URL         string    `gorm:"type:text;not null" json:"url"`
+	Description *string   `gorm:"type:varchar(255)" json:"description"`
+	Events      []string  `gorm:"type:jsonb;serializer:json" json:"events"`
+	Secret      string    `gorm:"type:varchar(100);not null" json:"-"` // HMAC-SHA256 signing key
+	IsActive    bool      `gorm:"default:true" json:"is_active"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	User       User              `gorm:"foreignKey:UserID" json:"-"`
+	Deliveries []WebhookDelivery `gorm:"foreignKey:EndpointID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook to generate UUID
+func (e *WebhookEndpoint) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (WebhookEndpoint) TableName() string {
+	return "webhook_endpoints"
+}
+
+// Subscribes checks if the endpoint wants an event type
+func (e *WebhookEndpoint) Subscribes(event string) bool {
+	for _, subscribed := range e.Events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one queued event for an endpoint, along with the outcome of its attempts
+type WebhookDelivery struct {
+	ID             uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	EndpointID     uuid.UUID       `gorm:"type:uuid;not null;index:idx_webhook_deliveries_endpoint_id;constraint:OnDelete:CASCADE" json:"endpoint_id"`
+	Event          string          `gorm:"type:varchar(50);not null" json:"event"`
+	Payload        json.RawMessage `gorm:"type:jsonb;not null" json:"payload"`
+	Status         string          `gorm:"type:varchar(20);default:'pending';index:idx_webhook_deliveries_due" json:"status"` // 'pending', 'succeeded', 'failed'
+	Attempts       int             `gorm:"default:0" json:"attempts"`
+	NextAttemptAt  time.Time       `gorm:"index:idx_webhook_deliveries_due" json:"next_attempt_at"`
+	LastStatusCode *int            `json:"last_status_code"`
+	LastError      *string         `gorm:"type:text" json:"last_error"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	Endpoint WebhookEndpoint `gorm:"foreignKey:EndpointID" json:"-"`
+}
+
+// BeforeCreate hook to generate UUID
+func (d *WebhookDelivery) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
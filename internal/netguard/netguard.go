@@ -0,0 +1,106 @@
+// Package netguard keeps requests to user-supplied URLs, such as webhooks, from reaching the
+// server's own network: loopback, private ranges, link-local addresses (including cloud metadata
+// endpoints like 169.254.169.254) and other non-public addresses.
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateAddress is returned for destinations that are not on the public internet
+var ErrPrivateAddress = errors.New("destination is not a public address")
+
+// reserved lists non-public ranges that netip.Addr has no predicate for
+var reserved = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64, which can reach any IPv4 address
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // local-use NAT64
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+}
+
+// IsPublic reports whether addr is a publicly routable unicast address
+func IsPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range reserved {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckURL resolves the host of rawURL and returns ErrPrivateAddress if any of its addresses is
+// not public. It gives early feedback when a URL is saved; Transport enforces the same rule on
+// every connection, since DNS can change after the check.
+func CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return fmt.Errorf("invalid URL %q", rawURL)
+	}
+	host := u.Hostname()
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !IsPublic(addr) {
+			return ErrPrivateAddress
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("could not resolve %s", host)
+	}
+	for _, addr := range addrs {
+		if !IsPublic(addr) {
+			return ErrPrivateAddress
+		}
+	}
+	return nil
+}
+
+// Control is a net.Dialer Control hook that refuses connections to non-public addresses. It runs
+// after DNS resolution, so a hostname cannot be re-pointed at an internal address after CheckURL.
+func Control(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("netguard: unexpected address %q", address)
+	}
+	if !IsPublic(addrPort.Addr()) {
+		return fmt.Errorf("netguard: dial %s %s: %w", network, address, ErrPrivateAddress)
+	}
+	return nil
+}
+
+// Transport returns an http.Transport like http.DefaultTransport that only connects to public
+// addresses. It ignores proxy settings, since the dial would then go to the proxy rather than
+// the destination.
+func Transport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   Control,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
@@ -0,0 +1,152 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Discord embed colors
+const (
+	discordColorDown      = 0xE5484D
+	discordColorRecovered = 0x30A46C
+	discordColorInfo      = 0x3E63DD
+)
+
+// maxDiscordEmbeds is Discord's limit on embeds per webhook message
+const maxDiscordEmbeds = 10
+
+// DiscordNotifier posts digests to a Discord channel webhook using embeds
+type DiscordNotifier struct {
+	client *http.Client
+}
+
+// NewDiscordNotifier creates a Discord notifier
+func NewDiscordNotifier() *DiscordNotifier {
+	return &DiscordNotifier{client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Name identifies the channel
+func (n *DiscordNotifier) Name() string {
+	return "discord"
+}
+
+// Send posts the digest to the user's Discord webhook
+func (n *DiscordNotifier) Send(ctx context.Context, digest *Digest) error {
+	if digest.DiscordWebhookURL == "" {
+		return nil
+	}
+	if err := postJSON(ctx, n.client, digest.DiscordWebhookURL, "LinkVault-Alerts/1.0", DiscordMessage(digest)); err != nil {
+		return fmt.Errorf("discord: %w", err)
+	}
+	return nil
+}
+
+// discordEmbed is a Discord rich embed
+type discordEmbed struct {
+	Title       string              `json:"title,omitempty"`
+	URL         string              `json:"url,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Color       int                 `json:"color"`
+	Fields      []discordEmbedField `json:"fields,omitempty"`
+	Timestamp   string              `json:"timestamp,omitempty"`
+}
+
+// discordEmbedField is a name/value pair inside an embed
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// DiscordMessage builds the Discord webhook payload for a digest: one embed per alert,
+// or a single stats embed for weekly summaries
+func DiscordMessage(digest *Digest) interface{} {
+	var embeds []discordEmbed
+
+	switch {
+	case digest.Test:
+		embeds = append(embeds, discordEmbed{
+			Description: "Link-down alerts, recovery notices and weekly summaries will be posted here.",
+			Color:       discordColorInfo,
+		})
+	case digest.Weekly != nil:
+		w := digest.Weekly
+		embed := discordEmbed{
+			Title: fmt.Sprintf("%s – %s", w.PeriodStart.Format("Jan 2"), w.PeriodEnd.Format("Jan 2, 2006")),
+			Color: discordColorInfo,
+			Fields: []discordEmbedField{
+				{Name: "Links", Value: fmt.Sprint(w.TotalLinks), Inline: true},
+				{Name: "Unhealthy", Value: fmt.Sprint(w.UnhealthyLinks), Inline: true},
+				{Name: "Clicks", Value: fmt.Sprint(w.Clicks), Inline: true},
+				{Name: "Down alerts", Value: fmt.Sprint(w.DownAlerts), Inline: true},
+			},
+			Timestamp: w.PeriodEnd.UTC().Format(time.RFC3339),
+		}
+		if len(w.Unhealthy) > 0 {
+			embed.Description = "**Still failing**\n" + discordItemList(w.Unhealthy, int(w.UnhealthyLinks))
+		}
+		embeds = append(embeds, embed)
+	default:
+		for i, item := range digest.Items {
+			if i == maxDiscordEmbeds-1 && len(digest.Items) > maxDiscordEmbeds {
+				embeds = append(embeds, discordEmbed{
+					Description: fmt.Sprintf("…and %d more", len(digest.Items)-i),
+					Color:       discordColorInfo,
+				})
+				break
+			}
+			embeds = append(embeds, discordItemEmbed(item))
+		}
+	}
+
+	return struct {
+		Username string         `json:"username"`
+		Content  string         `json:"content"`
+		Embeds   []discordEmbed `json:"embeds"`
+	}{
+		Username: "LinkVault",
+		Content:  digest.Summary(),
+		Embeds:   embeds,
+	}
+}
+
+// discordItemEmbed renders a single alert as an embed
+func discordItemEmbed(item Item) discordEmbed {
+	label, color := "Recovered", discordColorRecovered
+	if item.Kind == KindDown {
+		label, color = "Down", discordColorDown
+	}
+	return discordEmbed{
+		Title:       fmt.Sprintf("%s: %s", label, truncate(item.LinkTitle, 200)),
+		URL:         item.LinkURL,
+		Description: truncate(item.Message, 1000),
+		Color:       color,
+		Timestamp:   item.At.UTC().Format(time.RFC3339),
+	}
+}
+
+// discordItemList renders items as a markdown list, noting how many of total were left out
+func discordItemList(items []Item, total int) string {
+	var list string
+	for i, item := range items {
+		if i == maxChatItems {
+			break
+		}
+		list += fmt.Sprintf("• [%s](%s)\n", truncate(item.LinkTitle, 100), item.LinkURL)
+	}
+	if shown := min(len(items), maxChatItems); total > shown {
+		list += fmt.Sprintf("…and %d more\n", total-shown)
+	}
+	return list
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
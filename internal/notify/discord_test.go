@@ -0,0 +1,122 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type discordPayload struct {
+	Username string         `json:"username"`
+	Content  string         `json:"content"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+func TestDiscordNotifierSend(t *testing.T) {
+	server, received := newWebhookStub(t, http.StatusNoContent, "")
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	digest := &Digest{
+		DiscordWebhookURL: server.URL,
+		Items: []Item{
+			{LinkTitle: "Tent", LinkURL: "https://example.com/tent", Kind: KindDown, Message: "HTTP 404", At: at},
+			{LinkTitle: "Stove", LinkURL: "https://example.com/stove", Kind: KindRecovered, At: at},
+		},
+	}
+
+	if err := NewDiscordNotifier().Send(context.Background(), digest); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(*received) != 1 {
+		t.Fatalf("stub received %d requests, want 1", len(*received))
+	}
+	req := (*received)[0]
+	if req.Method != http.MethodPost || req.ContentType != "application/json" {
+		t.Errorf("request = %s %q, want a JSON POST", req.Method, req.ContentType)
+	}
+
+	var payload discordPayload
+	if err := json.Unmarshal(req.Body, &payload); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if payload.Username != "LinkVault" || payload.Content != "1 link down, 1 recovered" {
+		t.Errorf("username, content = %q, %q", payload.Username, payload.Content)
+	}
+	want := []discordEmbed{
+		{Title: "Down: Tent", URL: "https://example.com/tent", Description: "HTTP 404", Color: discordColorDown, Timestamp: "2026-03-02T09:00:00Z"},
+		{Title: "Recovered: Stove", URL: "https://example.com/stove", Color: discordColorRecovered, Timestamp: "2026-03-02T09:00:00Z"},
+	}
+	if len(payload.Embeds) != len(want) {
+		t.Fatalf("got %d embeds, want %d", len(payload.Embeds), len(want))
+	}
+	for i := range want {
+		got := payload.Embeds[i]
+		if got.Title != want[i].Title || got.URL != want[i].URL || got.Description != want[i].Description ||
+			got.Color != want[i].Color || got.Timestamp != want[i].Timestamp {
+			t.Errorf("embed %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestDiscordNotifierCapsEmbeds(t *testing.T) {
+	server, received := newWebhookStub(t, http.StatusNoContent, "")
+	digest := &Digest{DiscordWebhookURL: server.URL, Items: testItems(maxDiscordEmbeds+3, KindDown)}
+
+	if err := NewDiscordNotifier().Send(context.Background(), digest); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	var payload discordPayload
+	if err := json.Unmarshal((*received)[0].Body, &payload); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if len(payload.Embeds) != maxDiscordEmbeds {
+		t.Fatalf("got %d embeds, want Discord's limit of %d", len(payload.Embeds), maxDiscordEmbeds)
+	}
+	if last := payload.Embeds[maxDiscordEmbeds-1]; last.Description != "…and 4 more" {
+		t.Errorf("last embed = %+v, want the overflow note", last)
+	}
+}
+
+func TestDiscordNotifierWeekly(t *testing.T) {
+	server, received := newWebhookStub(t, http.StatusNoContent, "")
+	digest := &Digest{
+		DiscordWebhookURL: server.URL,
+		Weekly: &WeeklyStats{
+			PeriodStart:    time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC),
+			PeriodEnd:      time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+			TotalLinks:     12,
+			UnhealthyLinks: 1,
+			Clicks:         340,
+			Unhealthy:      testItems(1, KindDown),
+		},
+	}
+
+	if err := NewDiscordNotifier().Send(context.Background(), digest); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	var payload discordPayload
+	if err := json.Unmarshal((*received)[0].Body, &payload); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if len(payload.Embeds) != 1 {
+		t.Fatalf("got %d embeds, want 1", len(payload.Embeds))
+	}
+	embed := payload.Embeds[0]
+	if embed.Title != "Feb 23 – Mar 2, 2026" || len(embed.Fields) != 4 || embed.Fields[2].Value != "340" {
+		t.Errorf("embed = %+v", embed)
+	}
+	if embed.Description != "**Still failing**\n• [Link 1](https://example.com/p/1)\n" {
+		t.Errorf("description = %q", embed.Description)
+	}
+}
+
+func TestDiscordNotifierErrors(t *testing.T) {
+	server, _ := newWebhookStub(t, http.StatusBadRequest, `{"message": "Invalid Webhook Token"}`)
+	digest := &Digest{DiscordWebhookURL: server.URL, Items: testItems(1, KindDown)}
+
+	err := NewDiscordNotifier().Send(context.Background(), digest)
+	if want := `discord: HTTP 400: {"message": "Invalid Webhook Token"}`; err == nil || err.Error() != want {
+		t.Errorf("Send error = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,137 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Mailer sends transactional email
+type Mailer interface {
+	SendEmail(ctx context.Context, to, subject, html, text string) error
+}
+
+// ResendMailer sends email through the Resend API
+type ResendMailer struct {
+	apiKey string
+	from   string
+	client *http.Client
+}
+
+// NewResendMailer creates a mailer that sends from the given address
+func NewResendMailer(apiKey, from string) *ResendMailer {
+	return &ResendMailer{
+		apiKey: apiKey,
+		from:   from,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SendEmail sends a single email
+func (m *ResendMailer) SendEmail(ctx context.Context, to, subject, html, text string) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"from":    m.from,
+		"to":      []string{to},
+		"subject": subject,
+		"html":    html,
+		"text":    text,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.resend.com/emails", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("resend request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("resend returned HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// NewMailer returns a Resend mailer when an API key is configured and a LogMailer otherwise
+func NewMailer(apiKey, from string) Mailer {
+	if apiKey == "" {
+		return LogMailer{}
+	}
+	return NewResendMailer(apiKey, from)
+}
+
+// LogMailer writes emails to the log instead of sending them, for development
+type LogMailer struct{}
+
+// SendEmail logs the email
+func (LogMailer) SendEmail(ctx context.Context, to, subject, html, text string) error {
+	slog.InfoContext(ctx, "Email not sent, no mail provider configured", "to", to, "subject", subject, "body", text)
+	return nil
+}
+
+// emailDigestTemplate renders the HTML body of an alert digest
+var emailDigestTemplate = template.Must(template.New("digest").Parse(`<p>Hi{{if .Name}} {{.Name}}{{end}},</p>
+{{if .Test}}<p>This is a test message. Link alerts will be delivered to this address.</p>
+{{else if .Weekly}}<p>Your links this week: {{.Weekly.TotalLinks}} total, {{.Weekly.UnhealthyLinks}} unhealthy, {{.Weekly.Clicks}} clicks, {{.Weekly.DownAlerts}} down alerts.</p>
+{{else}}<p>Here's what changed with your links:</p>
+<ul>
+{{range .Items}}<li><strong>{{if eq .Kind "down"}}Down{{else}}Recovered{{end}}:</strong> {{.LinkTitle}} (<a href="{{.LinkURL}}">{{.LinkURL}}</a>)<br>{{.Message}}</li>
+{{end}}</ul>{{end}}
+<p>— LinkVault</p>
+`))
+
+// EmailNotifier delivers digests by email
+type EmailNotifier struct {
+	mailer Mailer
+}
+
+// NewEmailNotifier creates an email notifier
+func NewEmailNotifier(mailer Mailer) *EmailNotifier {
+	return &EmailNotifier{mailer: mailer}
+}
+
+// Name identifies the channel
+func (n *EmailNotifier) Name() string {
+	return "email"
+}
+
+// Send emails the digest to the user
+func (n *EmailNotifier) Send(ctx context.Context, digest *Digest) error {
+	if digest.Email == "" {
+		return nil
+	}
+
+	var html bytes.Buffer
+	if err := emailDigestTemplate.Execute(&html, digest); err != nil {
+		return err
+	}
+
+	var text strings.Builder
+	if digest.Test || digest.Weekly != nil {
+		text.WriteString(digest.Summary() + "\n")
+	}
+	for _, item := range digest.Items {
+		label := "Recovered"
+		if item.Kind == KindDown {
+			label = "Down"
+		}
+		fmt.Fprintf(&text, "%s: %s (%s)\n  %s\n", label, item.LinkTitle, item.LinkURL, item.Message)
+	}
+
+	subject := "LinkVault: " + digest.Summary()
+	return n.mailer.SendEmail(ctx, digest.Email, subject, html.String(), text.String())
+}
@@ -0,0 +1,130 @@
+// Package notify delivers alert digests to users through pluggable channels.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Alert kinds carried in digests
+const (
+	KindDown      = "down"
+	KindRecovered = "recovered"
+)
+
+// Item is a single alert inside a digest
+type Item struct {
+	LinkID    uuid.UUID `json:"link_id"`
+	LinkTitle string    `json:"link_title"`
+	LinkURL   string    `json:"link_url"`
+	Kind      string    `json:"kind"` // KindDown or KindRecovered
+	Message   string    `json:"message"`
+	At        time.Time `json:"at"`
+}
+
+// WeeklyStats summarizes a user's links over the past week
+type WeeklyStats struct {
+	PeriodStart    time.Time `json:"period_start"`
+	PeriodEnd      time.Time `json:"period_end"`
+	TotalLinks     int64     `json:"total_links"`
+	UnhealthyLinks int64     `json:"unhealthy_links"`
+	Clicks         int64     `json:"clicks"`
+	DownAlerts     int64     `json:"down_alerts"`
+	Unhealthy      []Item    `json:"unhealthy"` // the longest-failing links, capped
+}
+
+// Digest is a batch of alerts for one user, along with the addresses of their channels.
+// A digest carrying Weekly is a weekly summary; one with Test set is a test message.
+type Digest struct {
+	UserID            uuid.UUID
+	Name              string
+	Email             string
+	WebhookURL        string
+	SlackWebhookURL   string
+	DiscordWebhookURL string
+	Items             []Item
+	Weekly            *WeeklyStats
+	Test              bool
+}
+
+// Notifier delivers digests over one channel
+type Notifier interface {
+	// Name identifies the channel, e.g. "email" or "webhook"
+	Name() string
+	// Send delivers the digest; it should return an error only for failures worth retrying
+	Send(ctx context.Context, digest *Digest) error
+}
+
+// Counts returns how many items in the digest are down and recovered alerts
+func (d *Digest) Counts() (down, recovered int) {
+	for _, item := range d.Items {
+		if item.Kind == KindDown {
+			down++
+		} else {
+			recovered++
+		}
+	}
+	return down, recovered
+}
+
+// Summary returns a one-line description of the digest, e.g. "2 links down, 1 recovered"
+func (d *Digest) Summary() string {
+	if d.Test {
+		return "Test notification: your LinkVault integration is working"
+	}
+	if d.Weekly != nil {
+		return fmt.Sprintf("Weekly summary: %s, %d unhealthy, %d clicks",
+			pluralLinks(int(d.Weekly.TotalLinks)), d.Weekly.UnhealthyLinks, d.Weekly.Clicks)
+	}
+
+	down, recovered := d.Counts()
+	switch {
+	case down > 0 && recovered > 0:
+		return fmt.Sprintf("%s down, %d recovered", pluralLinks(down), recovered)
+	case down > 0:
+		return fmt.Sprintf("%s down", pluralLinks(down))
+	default:
+		return fmt.Sprintf("%s recovered", pluralLinks(recovered))
+	}
+}
+
+// postJSON posts payload as JSON to url, treating any non-2xx response as a failure
+func postJSON(ctx context.Context, client *http.Client, url, userAgent string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+func pluralLinks(n int) string {
+	if n == 1 {
+		return "1 link"
+	}
+	return fmt.Sprintf("%d links", n)
+}
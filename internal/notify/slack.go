@@ -0,0 +1,140 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxChatItems caps how many links are listed in one chat message; the rest are summarized
+const maxChatItems = 20
+
+// SlackNotifier posts digests to a Slack incoming webhook using Block Kit
+type SlackNotifier struct {
+	client *http.Client
+}
+
+// NewSlackNotifier creates a Slack notifier
+func NewSlackNotifier() *SlackNotifier {
+	return &SlackNotifier{client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Name identifies the channel
+func (n *SlackNotifier) Name() string {
+	return "slack"
+}
+
+// Send posts the digest to the user's Slack incoming webhook
+func (n *SlackNotifier) Send(ctx context.Context, digest *Digest) error {
+	if digest.SlackWebhookURL == "" {
+		return nil
+	}
+	if err := postJSON(ctx, n.client, digest.SlackWebhookURL, "LinkVault-Alerts/1.0", SlackMessage(digest)); err != nil {
+		return fmt.Errorf("slack: %w", err)
+	}
+	return nil
+}
+
+// slackBlock is a Block Kit layout block
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+// slackText is a Block Kit text object
+type slackText struct {
+	Type string `json:"type"` // "plain_text" or "mrkdwn"
+	Text string `json:"text"`
+}
+
+// SlackMessage builds the Slack webhook payload for a digest. Text is the notification
+// fallback; blocks carry the formatted body.
+func SlackMessage(digest *Digest) interface{} {
+	blocks := []slackBlock{{
+		Type: "header",
+		Text: &slackText{Type: "plain_text", Text: digest.Summary()},
+	}}
+
+	switch {
+	case digest.Test:
+		blocks = append(blocks, slackBlock{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: "Link-down alerts, recovery notices and weekly summaries will be posted here."},
+		})
+	case digest.Weekly != nil:
+		w := digest.Weekly
+		blocks = append(blocks, slackBlock{
+			Type: "section",
+			Fields: []slackText{
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Links*\n%d", w.TotalLinks)},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Unhealthy*\n%d", w.UnhealthyLinks)},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Clicks*\n%d", w.Clicks)},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Down alerts*\n%d", w.DownAlerts)},
+			},
+		})
+		if len(w.Unhealthy) > 0 {
+			blocks = append(blocks, slackBlock{
+				Type: "section",
+				Text: &slackText{Type: "mrkdwn", Text: "*Still failing*\n" + slackItemList(w.Unhealthy, int(w.UnhealthyLinks))},
+			})
+		}
+		blocks = append(blocks, slackBlock{
+			Type: "context",
+			Elements: []slackText{{
+				Type: "mrkdwn",
+				Text: fmt.Sprintf("%s – %s", w.PeriodStart.Format("Jan 2"), w.PeriodEnd.Format("Jan 2, 2006")),
+			}},
+		})
+	default:
+		blocks = append(blocks, slackBlock{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: slackItemList(digest.Items, len(digest.Items))},
+		})
+	}
+
+	return struct {
+		Text   string       `json:"text"`
+		Blocks []slackBlock `json:"blocks"`
+	}{
+		Text:   "LinkVault: " + digest.Summary(),
+		Blocks: blocks,
+	}
+}
+
+// slackItemList renders items as an mrkdwn bullet list, noting how many of total were left out
+func slackItemList(items []Item, total int) string {
+	var b strings.Builder
+	for i, item := range items {
+		if i == maxChatItems {
+			break
+		}
+		icon := ":white_check_mark:"
+		if item.Kind == KindDown {
+			icon = ":red_circle:"
+		}
+		fmt.Fprintf(&b, "%s <%s|%s>", icon, slackLinkURL(item.LinkURL), slackEscape(item.LinkTitle))
+		if item.Message != "" {
+			fmt.Fprintf(&b, " – %s", slackEscape(item.Message))
+		}
+		b.WriteString("\n")
+	}
+	if shown := min(len(items), maxChatItems); total > shown {
+		fmt.Fprintf(&b, "…and %d more", total-shown)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// slackLinkURL escapes a URL for the target of a <url|text> link, percent-encoding any "|"
+// so it can't end the URL early and spill the rest into the link text
+func slackLinkURL(u string) string {
+	return slackEscape(strings.ReplaceAll(u, "|", "%7C"))
+}
+
+// slackEscape escapes the characters Slack treats as control sequences in mrkdwn
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
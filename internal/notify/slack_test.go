@@ -0,0 +1,139 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// capturedRequest is a request received by a webhook stub
+type capturedRequest struct {
+	Method      string
+	ContentType string
+	UserAgent   string
+	Body        []byte
+}
+
+// newWebhookStub starts a server answering every request with status and body, recording what
+// it receives
+func newWebhookStub(t *testing.T, status int, body string) (*httptest.Server, *[]capturedRequest) {
+	t.Helper()
+	var received []capturedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		received = append(received, capturedRequest{
+			Method:      r.Method,
+			ContentType: r.Header.Get("Content-Type"),
+			UserAgent:   r.Header.Get("User-Agent"),
+			Body:        payload,
+		})
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+func testItems(n int, kind string) []Item {
+	items := make([]Item, n)
+	for i := range items {
+		items[i] = Item{
+			LinkTitle: fmt.Sprintf("Link %d", i+1),
+			LinkURL:   fmt.Sprintf("https://example.com/p/%d", i+1),
+			Kind:      kind,
+			Message:   "HTTP 404",
+			At:        time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+		}
+	}
+	return items
+}
+
+type slackPayload struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+func TestSlackNotifierSend(t *testing.T) {
+	server, received := newWebhookStub(t, http.StatusOK, "ok")
+	digest := &Digest{
+		SlackWebhookURL: server.URL,
+		Items: []Item{
+			{LinkTitle: "Tent <2p> & poles", LinkURL: "https://example.com/tent?a=1&b=2|3", Kind: KindDown, Message: "HTTP 404"},
+			{LinkTitle: "Stove", LinkURL: "https://example.com/stove", Kind: KindRecovered},
+		},
+	}
+
+	if err := NewSlackNotifier().Send(context.Background(), digest); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(*received) != 1 {
+		t.Fatalf("stub received %d requests, want 1", len(*received))
+	}
+	req := (*received)[0]
+	if req.Method != http.MethodPost || req.ContentType != "application/json" || req.UserAgent != "LinkVault-Alerts/1.0" {
+		t.Errorf("request = %s %q %q, want a JSON POST from LinkVault-Alerts/1.0", req.Method, req.ContentType, req.UserAgent)
+	}
+
+	var payload slackPayload
+	if err := json.Unmarshal(req.Body, &payload); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if payload.Text != "LinkVault: 1 link down, 1 recovered" {
+		t.Errorf("text = %q", payload.Text)
+	}
+	if len(payload.Blocks) != 2 || payload.Blocks[0].Type != "header" || payload.Blocks[1].Type != "section" {
+		t.Fatalf("blocks = %+v, want a header and a section", payload.Blocks)
+	}
+	want := ":red_circle: <https://example.com/tent?a=1&amp;b=2%7C3|Tent &lt;2p&gt; &amp; poles> – HTTP 404\n" +
+		":white_check_mark: <https://example.com/stove|Stove>"
+	if got := payload.Blocks[1].Text.Text; got != want {
+		t.Errorf("section text =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSlackNotifierCapsItems(t *testing.T) {
+	server, received := newWebhookStub(t, http.StatusOK, "ok")
+	digest := &Digest{SlackWebhookURL: server.URL, Items: testItems(maxChatItems+5, KindDown)}
+
+	if err := NewSlackNotifier().Send(context.Background(), digest); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	var payload slackPayload
+	if err := json.Unmarshal((*received)[0].Body, &payload); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	text := payload.Blocks[1].Text.Text
+	if lines := strings.Count(text, ":red_circle:"); lines != maxChatItems {
+		t.Errorf("listed %d links, want %d", lines, maxChatItems)
+	}
+	if !strings.HasSuffix(text, "…and 5 more") {
+		t.Errorf("section text does not end with the overflow note: %q", text)
+	}
+}
+
+func TestSlackNotifierErrors(t *testing.T) {
+	server, _ := newWebhookStub(t, http.StatusNotFound, "no_service\n")
+	digest := &Digest{SlackWebhookURL: server.URL, Items: testItems(1, KindDown)}
+
+	err := NewSlackNotifier().Send(context.Background(), digest)
+	if err == nil || err.Error() != "slack: HTTP 404: no_service" {
+		t.Errorf("Send error = %v, want %q", err, "slack: HTTP 404: no_service")
+	}
+
+	server.Close()
+	if err := NewSlackNotifier().Send(context.Background(), digest); err == nil || !strings.HasPrefix(err.Error(), "slack: request failed") {
+		t.Errorf("Send to a closed server error = %v, want a request failure", err)
+	}
+}
+
+func TestSlackNotifierSkipsUnconfigured(t *testing.T) {
+	if err := NewSlackNotifier().Send(context.Background(), &Digest{Items: testItems(1, KindDown)}); err != nil {
+		t.Errorf("Send without a webhook URL = %v, want nil", err)
+	}
+}
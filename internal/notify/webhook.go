@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookNotifier delivers digests as a JSON POST to a user-configured URL
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a webhook notifier. transport may be nil to use
+// http.DefaultTransport.
+func NewWebhookNotifier(transport http.RoundTripper) *WebhookNotifier {
+	return &WebhookNotifier{client: &http.Client{Timeout: 10 * time.Second, Transport: transport}}
+}
+
+// Name identifies the channel
+func (n *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+// Send posts the digest to the user's webhook URL
+func (n *WebhookNotifier) Send(ctx context.Context, digest *Digest) error {
+	if digest.WebhookURL == "" {
+		return nil
+	}
+
+	eventType := "alert.digest"
+	switch {
+	case digest.Test:
+		eventType = "alert.test"
+	case digest.Weekly != nil:
+		eventType = "alert.weekly_summary"
+	}
+
+	err := postJSON(ctx, n.client, digest.WebhookURL, "LinkVault-Alerts/1.0", struct {
+		Type    string       `json:"type"`
+		UserID  uuid.UUID    `json:"user_id"`
+		Summary string       `json:"summary"`
+		Alerts  []Item       `json:"alerts"`
+		Weekly  *WeeklyStats `json:"weekly,omitempty"`
+	}{
+		Type:    eventType,
+		UserID:  digest.UserID,
+		Summary: digest.Summary(),
+		Alerts:  digest.Items,
+		Weekly:  digest.Weekly,
+	})
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AlertRepository handles database operations for alerts and notification preferences
+type AlertRepository struct {
+	db *gorm.DB
+}
+
+// NewAlertRepository creates a new alert repository
+func NewAlertRepository(db *gorm.DB) *AlertRepository {
+	return &AlertRepository{db: db}
+}
+
+// GetPreferences retrieves a user's notification preferences, falling back to defaults
+func (r *AlertRepository) GetPreferences(ctx context.Context, userID uuid.UUID) (*models.NotificationPreference, error) {
+	var prefs models.NotificationPreference
+	err := r.db.WithContext(ctx).First(&prefs, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.DefaultNotificationPreference(userID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// SavePreferences creates or updates a user's notification preferences. Every column is written
+// so disabled flags aren't replaced by their column defaults on first insert.
+func (r *AlertRepository) SavePreferences(ctx context.Context, prefs *models.NotificationPreference) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Select("*").Create(prefs).Error
+}
+
+// GetWeeklySummaryDue retrieves preferences of users with a chat integration whose last
+// weekly summary was sent before the given time
+func (r *AlertRepository) GetWeeklySummaryDue(ctx context.Context, before time.Time) ([]models.NotificationPreference, error) {
+	var prefs []models.NotificationPreference
+	err := r.db.WithContext(ctx).Where("weekly_summary = ? AND (slack_enabled = ? OR discord_enabled = ?)", true, true, true).
+		Where("last_weekly_summary_at IS NULL OR last_weekly_summary_at < ?", before).
+		Find(&prefs).Error
+	if err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// MarkWeeklySummarySent records when a user's weekly summary went out
+func (r *AlertRepository) MarkWeeklySummarySent(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.NotificationPreference{}).
+		Where("user_id = ?", userID).
+		Update("last_weekly_summary_at", at).Error
+}
+
+// CountSince counts a user's alerts of the given kind created since a time
+func (r *AlertRepository) CountSince(ctx context.Context, userID uuid.UUID, kind string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Alert{}).
+		Where("user_id = ? AND kind = ? AND created_at >= ?", userID, kind, since).
+		Count(&count).Error
+	return count, err
+}
+
+// Create stores a pending alert
+func (r *AlertRepository) Create(ctx context.Context, alert *models.Alert) error {
+	return r.db.WithContext(ctx).Create(alert).Error
+}
+
+// DeletePending removes an undelivered alert of the given kind for a link, reporting whether one existed
+func (r *AlertRepository) DeletePending(ctx context.Context, linkID uuid.UUID, kind string) (bool, error) {
+	result := r.db.WithContext(ctx).Where("link_id = ? AND kind = ? AND delivered_at IS NULL", linkID, kind).Delete(&models.Alert{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetUsersWithPending retrieves the IDs of users who have undelivered alerts, with the
+// creation time of each user's oldest pending alert
+func (r *AlertRepository) GetUsersWithPending(ctx context.Context) (map[uuid.UUID]time.Time, error) {
+	var rows []struct {
+		UserID uuid.UUID
+		Oldest time.Time
+	}
+	err := r.db.WithContext(ctx).Model(&models.Alert{}).
+		Select("user_id, MIN(created_at) AS oldest").
+		Where("delivered_at IS NULL").
+		Group("user_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make(map[uuid.UUID]time.Time, len(rows))
+	for _, row := range rows {
+		pending[row.UserID] = row.Oldest
+	}
+	return pending, nil
+}
+
+// GetPending retrieves a user's undelivered alerts with their links, oldest first
+func (r *AlertRepository) GetPending(ctx context.Context, userID uuid.UUID) ([]models.Alert, error) {
+	var alerts []models.Alert
+	err := r.db.WithContext(ctx).Preload("Link").
+		Where("user_id = ? AND delivered_at IS NULL", userID).
+		Order("created_at").
+		Find(&alerts).Error
+	if err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// MarkDelivered marks alerts as delivered
+func (r *AlertRepository) MarkDelivered(ctx context.Context, ids []uuid.UUID, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Model(&models.Alert{}).Where("id IN ?", ids).Update("delivered_at", at).Error
+}
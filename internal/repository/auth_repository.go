@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"github.com/1shoukr/linkvault/internal/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -17,29 +18,29 @@ func NewAuthRepository(db *gorm.DB) *AuthRepository {
 }
 
 // GetUserByEmail retrieves a user by their email
-func (r *AuthRepository) GetUserByEmail(email string) (*models.User, error) {
+func (r *AuthRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
 // CreateUser creates a new user
-func (r *AuthRepository) CreateUser(user *models.User) error {
-	return r.db.Create(user).Error
+func (r *AuthRepository) CreateUser(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Create(user).Error
 }
 
 // GetUserByID retrieves a user by their ID
-func (r *AuthRepository) GetUserByID(id uuid.UUID) (*models.User, error) {
+func (r *AuthRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	var user models.User
-	if err := r.db.First(&user, "id = ?", id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
 // UpdateUser updates a user
-func (r *AuthRepository) UpdateUser(user *models.User) error {
-	return r.db.Save(user).Error
+func (r *AuthRepository) UpdateUser(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Save(user).Error
 }
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CheckRepository handles database operations for link health check history
+type CheckRepository struct {
+	db *gorm.DB
+}
+
+// NewCheckRepository creates a new check repository
+func NewCheckRepository(db *gorm.DB) *CheckRepository {
+	return &CheckRepository{db: db}
+}
+
+// Create stores a check result
+func (r *CheckRepository) Create(ctx context.Context, history *models.LinkCheckHistory) error {
+	return r.db.WithContext(ctx).Create(history).Error
+}
+
+// GetByLinkID retrieves the most recent checks for a link, newest first
+func (r *CheckRepository) GetByLinkID(ctx context.Context, linkID uuid.UUID, limit int) ([]models.LinkCheckHistory, error) {
+	var history []models.LinkCheckHistory
+	if err := r.db.WithContext(ctx).Where("link_id = ?", linkID).Order("checked_at DESC").Limit(limit).Find(&history).Error; err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// GetRulesByUserID retrieves every content rule a user has defined
+func (r *CheckRepository) GetRulesByUserID(ctx context.Context, userID uuid.UUID) ([]models.ContentRule, error) {
+	var rules []models.ContentRule
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// GetRulesForLink retrieves the content rules that apply to a link: the user's global rules
+// plus rules scoped to the link
+func (r *CheckRepository) GetRulesForLink(ctx context.Context, userID, linkID uuid.UUID) ([]models.ContentRule, error) {
+	var rules []models.ContentRule
+	err := r.db.WithContext(ctx).Where("user_id = ? AND (link_id IS NULL OR link_id = ?)", userID, linkID).
+		Order("created_at").Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// CreateRule stores a content rule
+func (r *CheckRepository) CreateRule(ctx context.Context, rule *models.ContentRule) error {
+	return r.db.WithContext(ctx).Create(rule).Error
+}
+
+// DeleteRule deletes a content rule owned by a user, reporting whether it existed
+func (r *CheckRepository) DeleteRule(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.ContentRule{}, "id = ?", id)
+	return result.RowsAffected > 0, result.Error
+}
@@ -1,74 +1,107 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"strconv"
 
 	"github.com/1shoukr/linkvault/internal/config"
-	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/1shoukr/linkvault/internal/logging"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
-var DB *gorm.DB
-
-// InitDatabase initializes the database connection and runs AutoMigrate
+// InitDatabase connects to the primary database at DATABASE_URL. Schema changes are applied
+// separately by the migrations package.
 func InitDatabase(cfg *config.Config) (*gorm.DB, error) {
-	if cfg.DatabaseURL == "" {
+	if !cfg.DatabaseURL.IsSet() {
 		return nil, fmt.Errorf("DATABASE_URL is required")
 	}
 
-	// Configure GORM logger based on environment
-	var gormLogger logger.Interface
+	db, err := open(cfg, cfg.DatabaseURL.Value(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Database connection established")
+	return db, nil
+}
+
+// InitReplica connects to the read replica at DATABASE_REPLICA_URL, or returns nil when none is
+// configured. Its sessions are read-only, so a write routed there by mistake fails instead of
+// being lost to replication.
+func InitReplica(cfg *config.Config) (*gorm.DB, error) {
+	if !cfg.DatabaseReplicaURL.IsSet() {
+		return nil, nil
+	}
+
+	db, err := open(cfg, cfg.DatabaseReplicaURL.Value(), map[string]string{"default_transaction_read_only": "on"})
+	if err != nil {
+		return nil, fmt.Errorf("replica: %w", err)
+	}
+
+	slog.Info("Read replica connection established")
+	return db, nil
+}
+
+// open connects to dsn with the configured pool and statement timeout, setting params on every
+// session
+func open(cfg *config.Config, dsn string, params map[string]string) (*gorm.DB, error) {
+	connConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		// pgx's parse errors can quote the URL, password included, so keep them out of the logs
+		return nil, errors.New("invalid database URL")
+	}
+	// Postgres cancels any statement running longer than this, whatever the client does
+	if cfg.DBStatementTimeout > 0 {
+		connConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.DBStatementTimeout.Milliseconds(), 10)
+	}
+	for name, value := range params {
+		connConfig.RuntimeParams[name] = value
+	}
+	sqlDB := stdlib.OpenDB(*connConfig)
+
+	sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.DBConnMaxIdleTime)
+
+	// Log failed and slow queries everywhere; outside production every query is also logged at
+	// debug, visible with LOG_LEVEL=debug
+	level := logger.Info
 	if cfg.Env == "production" {
-		gormLogger = logger.Default.LogMode(logger.Silent)
-	} else {
-		gormLogger = logger.Default.LogMode(logger.Info)
+		level = logger.Warn
 	}
+	gormLogger := logging.NewGormLogger(slog.Default(), level, cfg.DBSlowQueryThreshold)
 
-	db, err := gorm.Open(postgres.Open(cfg.DatabaseURL), &gorm.Config{
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
 		Logger: gormLogger,
+		// Report unique and foreign key violations as gorm.ErrDuplicatedKey/ErrForeignKeyViolated,
+		// the same errors the in-memory stores return
+		TranslateError: true,
 	})
 	if err != nil {
+		sqlDB.Close()
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
 	// Test the connection
-	sqlDB, err := db.DB()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get database instance: %w", err)
-	}
-
 	if err := sqlDB.Ping(); err != nil {
+		sqlDB.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	// Set connection pool settings
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
-
-	// AutoMigrate all models
-	log.Println("Running database migrations...")
-	err = db.AutoMigrate(
-		&models.User{},
-		&models.OAuthAccount{},
-		&models.MagicLinkToken{},
-		&models.Link{},
-		&models.Click{},
-		&models.LinkCheckHistory{},
-		&models.Subscription{},
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to run migrations: %w", err)
-	}
-
-	DB = db
-	log.Println("Database connection established and migrations completed successfully")
 	return db, nil
 }
 
-// GetDB returns the database instance
-func GetDB() *gorm.DB {
-	return DB
+// orPrimary returns replica, or primary when no replica is configured
+func orPrimary(replica, primary *gorm.DB) *gorm.DB {
+	if replica != nil {
+		return replica
+	}
+	return primary
 }
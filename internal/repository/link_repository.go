@@ -0,0 +1,288 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// VariantClickStats aggregates clicks recorded against a single A/B variant
+type VariantClickStats struct {
+	VariantID      uuid.UUID
+	Clicks         int64
+	UniqueVisitors int64
+}
+
+// LinkRepository handles database operations for links
+type LinkRepository struct {
+	db *gorm.DB
+	// reads serves click and health statistics; it is the read replica when one is configured
+	reads *gorm.DB
+}
+
+// NewLinkRepository creates a new link repository. Statistics queries go to replica, or to db
+// when replica is nil.
+func NewLinkRepository(db, replica *gorm.DB) *LinkRepository {
+	return &LinkRepository{db: db, reads: orPrimary(replica, db)}
+}
+
+// withRoutingRules preloads every routing rule a redirect may need
+func (r *LinkRepository) withRoutingRules(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Preload("GeoRules").Preload("Variants").Preload("DeviceRules").Preload("Channels")
+}
+
+// GetByID retrieves a link by its ID, including its routing rules and owner
+func (r *LinkRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Link, error) {
+	var link models.Link
+	if err := r.withRoutingRules(ctx).Preload("User").First(&link, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// GetByUserID retrieves all links owned by a user, newest first
+func (r *LinkRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]models.Link, error) {
+	var links []models.Link
+	if err := r.withRoutingRules(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&links).Error; err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// Create creates a new link
+func (r *LinkRepository) Create(ctx context.Context, link *models.Link) error {
+	return r.db.WithContext(ctx).Create(link).Error
+}
+
+// linkSettingsColumns are the columns users edit through the links API. The rest of a link is
+// written concurrently by click recording, the checker and the lifecycle worker.
+var linkSettingsColumns = []string{
+	"original_url", "title", "description", "category", "platform", "tags",
+	"affiliate_network", "affiliate_id", "status", "next_check_at",
+	"starts_at", "expires_at", "max_clicks", "fallback_url", "password_hash", "is_private",
+	"utm_source", "utm_medium", "utm_campaign", "utm_content",
+}
+
+// Update stores a link's user-editable settings, leaving its click count and health as they
+// are in the database
+func (r *LinkRepository) Update(ctx context.Context, link *models.Link) error {
+	return r.db.WithContext(ctx).Model(link).Select(linkSettingsColumns).Updates(link).Error
+}
+
+// Delete deletes a link by ID
+func (r *LinkRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Link{}, "id = ?", id).Error
+}
+
+// ReplaceGeoRules atomically replaces all geo rules for a link
+func (r *LinkRepository) ReplaceGeoRules(ctx context.Context, linkID uuid.UUID, rules []models.LinkGeoRule) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("link_id = ?", linkID).Delete(&models.LinkGeoRule{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		for i := range rules {
+			rules[i].LinkID = linkID
+		}
+		return tx.Create(&rules).Error
+	})
+}
+
+// ReplaceVariants atomically replaces all A/B variants for a link
+func (r *LinkRepository) ReplaceVariants(ctx context.Context, linkID uuid.UUID, variants []models.LinkVariant) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("link_id = ?", linkID).Delete(&models.LinkVariant{}).Error; err != nil {
+			return err
+		}
+		if len(variants) == 0 {
+			return nil
+		}
+		for i := range variants {
+			variants[i].LinkID = linkID
+		}
+		return tx.Create(&variants).Error
+	})
+}
+
+// ReplaceDeviceRules atomically replaces all device routing rules for a link
+func (r *LinkRepository) ReplaceDeviceRules(ctx context.Context, linkID uuid.UUID, rules []models.LinkDeviceRule) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("link_id = ?", linkID).Delete(&models.LinkDeviceRule{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		for i := range rules {
+			rules[i].LinkID = linkID
+		}
+		return tx.Create(&rules).Error
+	})
+}
+
+// ReplaceChannels atomically replaces all channel variants for a link
+func (r *LinkRepository) ReplaceChannels(ctx context.Context, linkID uuid.UUID, channels []models.LinkChannel) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("link_id = ?", linkID).Delete(&models.LinkChannel{}).Error; err != nil {
+			return err
+		}
+		if len(channels) == 0 {
+			return nil
+		}
+		for i := range channels {
+			channels[i].LinkID = linkID
+		}
+		return tx.Create(&channels).Error
+	})
+}
+
+// GetVariantClickStats counts clicks and distinct visitor IPs per variant of a link
+func (r *LinkRepository) GetVariantClickStats(ctx context.Context, linkID uuid.UUID) ([]VariantClickStats, error) {
+	var stats []VariantClickStats
+	err := r.reads.WithContext(ctx).Model(&models.Click{}).
+		Select("variant_id, COUNT(*) AS clicks, COUNT(DISTINCT ip_address) AS unique_visitors").
+		Where("link_id = ? AND variant_id IS NOT NULL", linkID).
+		Group("variant_id").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// ActivateScheduled moves scheduled links whose start time has passed to active
+func (r *LinkRepository) ActivateScheduled(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Link{}).
+		Where("status = ? AND archived_at IS NULL AND (starts_at IS NULL OR starts_at <= ?)", models.LinkStatusScheduled, now).
+		Updates(map[string]interface{}{"status": models.LinkStatusActive, "updated_at": now})
+	return result.RowsAffected, result.Error
+}
+
+// ExpireDue marks links past their expiry time or click cap as expired
+func (r *LinkRepository) ExpireDue(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Link{}).
+		Where("status IN ? AND archived_at IS NULL", []string{models.LinkStatusScheduled, models.LinkStatusActive}).
+		Where("(expires_at IS NOT NULL AND expires_at <= ?) OR (max_clicks IS NOT NULL AND click_count >= max_clicks)", now).
+		Updates(map[string]interface{}{"status": models.LinkStatusExpired, "updated_at": now})
+	return result.RowsAffected, result.Error
+}
+
+// ArchiveExpired archives links that expired before the given cutoff
+func (r *LinkRepository) ArchiveExpired(ctx context.Context, cutoff, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Link{}).
+		Where("status = ? AND archived_at IS NULL AND COALESCE(expires_at, updated_at) <= ?", models.LinkStatusExpired, cutoff).
+		Updates(map[string]interface{}{"archived_at": now, "updated_at": now})
+	return result.RowsAffected, result.Error
+}
+
+// ClaimDueForCheck claims live links whose next scheduled check is due, most overdue first, with
+// their owners. Claimed links are pushed lease into the future, so other workers skip them until
+// the check reschedules them, or until the lease runs out if this worker dies first.
+func (r *LinkRepository) ClaimDueForCheck(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.Link, error) {
+	var links []models.Link
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// SKIP LOCKED lets concurrent workers claim disjoint batches instead of waiting on each other
+		err := tx.Preload("User").
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND archived_at IS NULL", models.LinkStatusActive).
+			Where("next_check_at IS NULL OR next_check_at <= ?", now).
+			Order("next_check_at ASC NULLS FIRST").
+			Limit(limit).
+			Find(&links).Error
+		if err != nil || len(links) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(links))
+		for i := range links {
+			ids[i] = links[i].ID
+		}
+		return tx.Model(&models.Link{}).Where("id IN ?", ids).UpdateColumn("next_check_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// UpdateHealth stores the health fields of a link after a check
+func (r *LinkRepository) UpdateHealth(ctx context.Context, link *models.Link) error {
+	return r.db.WithContext(ctx).Model(link).Select(
+		"is_healthy", "last_status_code", "last_response_time", "last_checked_at", "last_working_at", "final_url",
+		"consecutive_failures", "alerted_down_at", "recovered_at", "next_check_at", "check_click_count",
+	).Updates(link).Error
+}
+
+// Reschedule sets when a link is next due for a health check
+func (r *LinkRepository) Reschedule(ctx context.Context, id uuid.UUID, nextCheckAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.Link{}).Where("id = ?", id).Update("next_check_at", nextCheckAt).Error
+}
+
+// ClaimClick increments a capped link's click counter if it is still below max_clicks,
+// reporting whether it was
+func (r *LinkRepository) ClaimClick(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.Link{}).
+		Where("id = ? AND max_clicks IS NOT NULL AND click_count < max_clicks", id).
+		UpdateColumn("click_count", gorm.Expr("click_count + 1"))
+	return result.RowsAffected > 0, result.Error
+}
+
+// RecordClick stores a click and, unless ClaimClick already has (counted), increments the
+// link's click counter
+func (r *LinkRepository) RecordClick(ctx context.Context, click *models.Click, counted bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(click).Error; err != nil {
+			return err
+		}
+		if counted {
+			return nil
+		}
+		return tx.Model(&models.Link{}).Where("id = ?", click.LinkID).
+			UpdateColumn("click_count", gorm.Expr("click_count + 1")).Error
+	})
+}
+
+// LinkHealthCounts summarizes the health of a user's active links
+type LinkHealthCounts struct {
+	Total     int64
+	Unhealthy int64
+}
+
+// GetHealthCounts counts a user's unarchived links and how many are unhealthy
+func (r *LinkRepository) GetHealthCounts(ctx context.Context, userID uuid.UUID) (LinkHealthCounts, error) {
+	var counts LinkHealthCounts
+	err := r.reads.WithContext(ctx).Model(&models.Link{}).
+		Select("COUNT(*) AS total, COUNT(*) FILTER (WHERE NOT is_healthy) AS unhealthy").
+		Where("user_id = ? AND archived_at IS NULL", userID).
+		Scan(&counts).Error
+	return counts, err
+}
+
+// GetUnhealthy retrieves a user's unhealthy, unarchived links, longest-failing first
+func (r *LinkRepository) GetUnhealthy(ctx context.Context, userID uuid.UUID, limit int) ([]models.Link, error) {
+	var links []models.Link
+	err := r.reads.WithContext(ctx).Where("user_id = ? AND archived_at IS NULL AND is_healthy = ?", userID, false).
+		Order("last_working_at ASC NULLS FIRST").
+		Limit(limit).
+		Find(&links).Error
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// CountClicksSince counts clicks on all of a user's links since a time
+func (r *LinkRepository) CountClicksSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	err := r.reads.WithContext(ctx).Model(&models.Click{}).
+		Joins("JOIN links ON links.id = clicks.link_id").
+		Where("links.user_id = ? AND clicks.clicked_at >= ?", userID, since).
+		Count(&count).Error
+	return count, err
+}
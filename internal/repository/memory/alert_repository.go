@@ -0,0 +1,206 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/1shoukr/linkvault/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var _ repository.AlertStore = (*AlertRepository)(nil)
+
+// AlertRepository is an in-memory repository.AlertStore
+type AlertRepository struct {
+	db *DB
+}
+
+// NewAlertRepository creates an alert repository backed by db
+func NewAlertRepository(db *DB) *AlertRepository {
+	return &AlertRepository{db: db}
+}
+
+// GetPreferences retrieves a user's notification preferences, falling back to defaults
+func (r *AlertRepository) GetPreferences(_ context.Context, userID uuid.UUID) (*models.NotificationPreference, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	prefs, ok := r.db.prefs[userID]
+	if !ok {
+		return models.DefaultNotificationPreference(userID), nil
+	}
+	prefs = clonePreferences(prefs)
+	return &prefs, nil
+}
+
+// SavePreferences creates or updates a user's notification preferences, writing every column
+func (r *AlertRepository) SavePreferences(_ context.Context, prefs *models.NotificationPreference) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.users[prefs.UserID]; !ok {
+		return gorm.ErrForeignKeyViolated
+	}
+	at := now()
+	if prefs.CreatedAt.IsZero() {
+		prefs.CreatedAt = at
+	}
+	prefs.UpdatedAt = at
+	r.db.prefs[prefs.UserID] = clonePreferences(*prefs)
+	return nil
+}
+
+// GetWeeklySummaryDue retrieves preferences of users with a chat integration whose last
+// weekly summary was sent before the given time
+func (r *AlertRepository) GetWeeklySummaryDue(_ context.Context, before time.Time) ([]models.NotificationPreference, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var due []models.NotificationPreference
+	for _, prefs := range r.db.prefs {
+		if !prefs.WeeklySummary || !(prefs.SlackEnabled || prefs.DiscordEnabled) {
+			continue
+		}
+		if prefs.LastWeeklySummaryAt == nil || prefs.LastWeeklySummaryAt.Before(before) {
+			due = append(due, clonePreferences(prefs))
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].UserID.String() < due[j].UserID.String() })
+	return due, nil
+}
+
+// MarkWeeklySummarySent records when a user's weekly summary went out
+func (r *AlertRepository) MarkWeeklySummarySent(_ context.Context, userID uuid.UUID, at time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if prefs, ok := r.db.prefs[userID]; ok {
+		prefs.LastWeeklySummaryAt = &at
+		prefs.UpdatedAt = now()
+		r.db.prefs[userID] = prefs
+	}
+	return nil
+}
+
+// CountSince counts a user's alerts of the given kind created since a time
+func (r *AlertRepository) CountSince(_ context.Context, userID uuid.UUID, kind string, since time.Time) (int64, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var count int64
+	for _, alert := range r.db.alerts {
+		if alert.UserID == userID && alert.Kind == kind && !alert.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Create stores a pending alert
+func (r *AlertRepository) Create(_ context.Context, alert *models.Alert) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.users[alert.UserID]; !ok {
+		return gorm.ErrForeignKeyViolated
+	}
+	if _, ok := r.db.links[alert.LinkID]; !ok {
+		return gorm.ErrForeignKeyViolated
+	}
+	if alert.ID == uuid.Nil {
+		alert.ID = uuid.New()
+	}
+	if _, ok := r.db.alerts[alert.ID]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	if alert.CreatedAt.IsZero() {
+		alert.CreatedAt = now()
+	}
+	r.db.alerts[alert.ID] = cloneAlert(*alert)
+	return nil
+}
+
+// DeletePending removes an undelivered alert of the given kind for a link, reporting whether one existed
+func (r *AlertRepository) DeletePending(_ context.Context, linkID uuid.UUID, kind string) (bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	deleted := false
+	for id, alert := range r.db.alerts {
+		if alert.LinkID == linkID && alert.Kind == kind && alert.DeliveredAt == nil {
+			delete(r.db.alerts, id)
+			deleted = true
+		}
+	}
+	return deleted, nil
+}
+
+// GetUsersWithPending retrieves the IDs of users who have undelivered alerts, with the
+// creation time of each user's oldest pending alert
+func (r *AlertRepository) GetUsersWithPending(_ context.Context) (map[uuid.UUID]time.Time, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	pending := make(map[uuid.UUID]time.Time)
+	for _, alert := range r.db.alerts {
+		if alert.DeliveredAt != nil {
+			continue
+		}
+		if oldest, ok := pending[alert.UserID]; !ok || alert.CreatedAt.Before(oldest) {
+			pending[alert.UserID] = alert.CreatedAt
+		}
+	}
+	return pending, nil
+}
+
+// GetPending retrieves a user's undelivered alerts with their links, oldest first
+func (r *AlertRepository) GetPending(_ context.Context, userID uuid.UUID) ([]models.Alert, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var alerts []models.Alert
+	for _, alert := range r.db.alerts {
+		if alert.UserID == userID && alert.DeliveredAt == nil {
+			alert = cloneAlert(alert)
+			alert.Link = cloneLink(r.db.links[alert.LinkID])
+			alerts = append(alerts, alert)
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].CreatedAt.Before(alerts[j].CreatedAt) })
+	return alerts, nil
+}
+
+// MarkDelivered marks alerts as delivered
+func (r *AlertRepository) MarkDelivered(_ context.Context, ids []uuid.UUID, at time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for _, id := range ids {
+		if alert, ok := r.db.alerts[id]; ok {
+			alert.DeliveredAt = &at
+			r.db.alerts[id] = alert
+		}
+	}
+	return nil
+}
+
+// clonePreferences copies notification preferences, dropping the user relationship
+func clonePreferences(p models.NotificationPreference) models.NotificationPreference {
+	p.WebhookURL = clonePtr(p.WebhookURL)
+	p.SlackWebhookURL = clonePtr(p.SlackWebhookURL)
+	p.DiscordWebhookURL = clonePtr(p.DiscordWebhookURL)
+	p.LastWeeklySummaryAt = clonePtr(p.LastWeeklySummaryAt)
+	p.User = models.User{}
+	return p
+}
+
+// cloneAlert copies an alert's columns, dropping its relationships
+func cloneAlert(a models.Alert) models.Alert {
+	a.DeliveredAt = clonePtr(a.DeliveredAt)
+	a.User = models.User{}
+	a.Link = models.Link{}
+	return a
+}
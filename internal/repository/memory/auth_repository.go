@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/1shoukr/linkvault/internal/repository"
+	"github.com/google/uuid"
+)
+
+var _ repository.AuthStore = (*AuthRepository)(nil)
+
+// AuthRepository is an in-memory repository.AuthStore sharing its users with UserRepository
+type AuthRepository struct {
+	users *UserRepository
+}
+
+// NewAuthRepository creates an auth repository backed by db
+func NewAuthRepository(db *DB) *AuthRepository {
+	return &AuthRepository{users: NewUserRepository(db)}
+}
+
+// GetUserByEmail retrieves a user by their email
+func (r *AuthRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.users.GetByEmail(ctx, email)
+}
+
+// CreateUser creates a new user
+func (r *AuthRepository) CreateUser(ctx context.Context, user *models.User) error {
+	return r.users.Create(ctx, user)
+}
+
+// GetUserByID retrieves a user by their ID
+func (r *AuthRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	return r.users.GetByID(ctx, id)
+}
+
+// UpdateUser updates a user
+func (r *AuthRepository) UpdateUser(ctx context.Context, user *models.User) error {
+	return r.users.Update(ctx, user)
+}
@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/1shoukr/linkvault/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var _ repository.CheckStore = (*CheckRepository)(nil)
+
+// CheckRepository is an in-memory repository.CheckStore
+type CheckRepository struct {
+	db *DB
+}
+
+// NewCheckRepository creates a check repository backed by db
+func NewCheckRepository(db *DB) *CheckRepository {
+	return &CheckRepository{db: db}
+}
+
+// Create stores a check result
+func (r *CheckRepository) Create(_ context.Context, history *models.LinkCheckHistory) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.links[history.LinkID]; !ok {
+		return gorm.ErrForeignKeyViolated
+	}
+	if history.ID == uuid.Nil {
+		history.ID = uuid.New()
+	}
+	if _, ok := r.db.checks[history.ID]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	if history.CheckedAt.IsZero() {
+		history.CheckedAt = now()
+	}
+	r.db.checks[history.ID] = cloneCheck(*history)
+	return nil
+}
+
+// GetByLinkID retrieves the most recent checks for a link, newest first
+func (r *CheckRepository) GetByLinkID(_ context.Context, linkID uuid.UUID, limit int) ([]models.LinkCheckHistory, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var history []models.LinkCheckHistory
+	for _, check := range r.db.checks {
+		if check.LinkID == linkID {
+			history = append(history, cloneCheck(check))
+		}
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].CheckedAt.After(history[j].CheckedAt) })
+	return limited(history, limit), nil
+}
+
+// GetRulesByUserID retrieves every content rule a user has defined
+func (r *CheckRepository) GetRulesByUserID(_ context.Context, userID uuid.UUID) ([]models.ContentRule, error) {
+	return r.rulesWhere(func(rule models.ContentRule) bool { return rule.UserID == userID }), nil
+}
+
+// GetRulesForLink retrieves the content rules that apply to a link: the user's global rules
+// plus rules scoped to the link
+func (r *CheckRepository) GetRulesForLink(_ context.Context, userID, linkID uuid.UUID) ([]models.ContentRule, error) {
+	return r.rulesWhere(func(rule models.ContentRule) bool {
+		return rule.UserID == userID && (rule.LinkID == nil || *rule.LinkID == linkID)
+	}), nil
+}
+
+// CreateRule stores a content rule
+func (r *CheckRepository) CreateRule(_ context.Context, rule *models.ContentRule) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.users[rule.UserID]; !ok {
+		return gorm.ErrForeignKeyViolated
+	}
+	if rule.LinkID != nil {
+		if _, ok := r.db.links[*rule.LinkID]; !ok {
+			return gorm.ErrForeignKeyViolated
+		}
+	}
+	if rule.ID == uuid.Nil {
+		rule.ID = uuid.New()
+	}
+	if _, ok := r.db.contentRules[rule.ID]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	if rule.CreatedAt.IsZero() {
+		rule.CreatedAt = now()
+	}
+	r.db.contentRules[rule.ID] = cloneContentRule(*rule)
+	return nil
+}
+
+// DeleteRule deletes a content rule owned by a user, reporting whether it existed
+func (r *CheckRepository) DeleteRule(_ context.Context, userID, id uuid.UUID) (bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	rule, ok := r.db.contentRules[id]
+	if !ok || rule.UserID != userID {
+		return false, nil
+	}
+	delete(r.db.contentRules, id)
+	return true, nil
+}
+
+// rulesWhere retrieves the content rules matching match, oldest first
+func (r *CheckRepository) rulesWhere(match func(models.ContentRule) bool) []models.ContentRule {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var rules []models.ContentRule
+	for _, rule := range r.db.contentRules {
+		if match(rule) {
+			rules = append(rules, cloneContentRule(rule))
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].CreatedAt.Before(rules[j].CreatedAt) })
+	return rules
+}
+
+// cloneContentRule copies a content rule's columns, dropping its relationships
+func cloneContentRule(r models.ContentRule) models.ContentRule {
+	r.LinkID = clonePtr(r.LinkID)
+	r.Message = clonePtr(r.Message)
+	r.User = models.User{}
+	r.Link = nil
+	return r
+}
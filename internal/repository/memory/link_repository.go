@@ -0,0 +1,529 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/1shoukr/linkvault/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var _ repository.LinkStore = (*LinkRepository)(nil)
+
+// applyLinkSettings copies the user-editable columns the gorm repository's Update writes
+func applyLinkSettings(stored *models.Link, link models.Link) {
+	stored.OriginalURL = link.OriginalURL
+	stored.Title = link.Title
+	stored.Description = link.Description
+	stored.Category = link.Category
+	stored.Platform = link.Platform
+	stored.Tags = link.Tags
+	stored.AffiliateNetwork = link.AffiliateNetwork
+	stored.AffiliateID = link.AffiliateID
+	stored.Status = link.Status
+	stored.NextCheckAt = link.NextCheckAt
+	stored.StartsAt = link.StartsAt
+	stored.ExpiresAt = link.ExpiresAt
+	stored.MaxClicks = link.MaxClicks
+	stored.FallbackURL = link.FallbackURL
+	stored.PasswordHash = link.PasswordHash
+	stored.IsPrivate = link.IsPrivate
+	stored.UTM = link.UTM
+}
+
+// LinkRepository is an in-memory repository.LinkStore
+type LinkRepository struct {
+	db *DB
+}
+
+// NewLinkRepository creates a link repository backed by db
+func NewLinkRepository(db *DB) *LinkRepository {
+	return &LinkRepository{db: db}
+}
+
+// GetByID retrieves a link by its ID, including its routing rules and owner
+func (r *LinkRepository) GetByID(_ context.Context, id uuid.UUID) (*models.Link, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	stored, ok := r.db.links[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	link := r.db.withRoutingRules(stored)
+	link.User = cloneUser(r.db.users[link.UserID])
+	return &link, nil
+}
+
+// GetByUserID retrieves all links owned by a user, newest first
+func (r *LinkRepository) GetByUserID(_ context.Context, userID uuid.UUID) ([]models.Link, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var links []models.Link
+	for _, link := range r.db.links {
+		if link.UserID == userID {
+			links = append(links, r.db.withRoutingRules(link))
+		}
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if !links[i].CreatedAt.Equal(links[j].CreatedAt) {
+			return links[i].CreatedAt.After(links[j].CreatedAt)
+		}
+		return links[i].ID.String() < links[j].ID.String()
+	})
+	return links, nil
+}
+
+// Create creates a new link, filling in the ID, timestamps and column defaults
+func (r *LinkRepository) Create(_ context.Context, link *models.Link) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.users[link.UserID]; !ok {
+		return gorm.ErrForeignKeyViolated
+	}
+	if link.ID == uuid.Nil {
+		link.ID = uuid.New()
+	}
+	if _, ok := r.db.links[link.ID]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+
+	createdAt := now()
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = createdAt
+	}
+	if link.UpdatedAt.IsZero() {
+		link.UpdatedAt = createdAt
+	}
+	// gorm writes the column default in place of a zero value, so a new link is always healthy
+	if link.Status == "" {
+		link.Status = models.LinkStatusActive
+	}
+	link.IsHealthy = true
+	r.db.links[link.ID] = cloneLink(*link)
+	return nil
+}
+
+// Update stores a link's user-editable settings, leaving its click count and health as they
+// are stored
+func (r *LinkRepository) Update(_ context.Context, link *models.Link) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	stored, ok := r.db.links[link.ID]
+	if !ok {
+		return nil
+	}
+	link.UpdatedAt = now()
+	applyLinkSettings(&stored, *link)
+	stored.UpdatedAt = link.UpdatedAt
+	r.db.links[link.ID] = cloneLink(stored)
+	return nil
+}
+
+// Delete deletes a link by ID, cascading to its rules, clicks, checks, alerts and uptime history
+func (r *LinkRepository) Delete(_ context.Context, id uuid.UUID) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	r.db.deleteLink(id)
+	return nil
+}
+
+// ReplaceGeoRules atomically replaces all geo rules for a link
+func (r *LinkRepository) ReplaceGeoRules(_ context.Context, linkID uuid.UUID, rules []models.LinkGeoRule) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.checkRoutingRules(linkID, len(rules), func(i int) string { return rules[i].Country }); err != nil {
+		return err
+	}
+	createdAt := now()
+	stored := make([]models.LinkGeoRule, len(rules))
+	for i := range rules {
+		rules[i].LinkID = linkID
+		fillRowDefaults(&rules[i].ID, &rules[i].CreatedAt, &rules[i].UpdatedAt, createdAt)
+		stored[i] = rules[i]
+		stored[i].Link = models.Link{}
+	}
+	r.db.geoRules[linkID] = stored
+	return nil
+}
+
+// ReplaceVariants atomically replaces all A/B variants for a link
+func (r *LinkRepository) ReplaceVariants(_ context.Context, linkID uuid.UUID, variants []models.LinkVariant) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.checkRoutingRules(linkID, len(variants), nil); err != nil {
+		return err
+	}
+	createdAt := now()
+	stored := make([]models.LinkVariant, len(variants))
+	for i := range variants {
+		variants[i].LinkID = linkID
+		fillRowDefaults(&variants[i].ID, &variants[i].CreatedAt, &variants[i].UpdatedAt, createdAt)
+		if variants[i].Weight == 0 {
+			variants[i].Weight = 1
+		}
+		stored[i] = variants[i]
+		stored[i].Link = models.Link{}
+	}
+	r.db.variants[linkID] = stored
+	return nil
+}
+
+// ReplaceDeviceRules atomically replaces all device routing rules for a link
+func (r *LinkRepository) ReplaceDeviceRules(_ context.Context, linkID uuid.UUID, rules []models.LinkDeviceRule) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.checkRoutingRules(linkID, len(rules), func(i int) string { return rules[i].Platform }); err != nil {
+		return err
+	}
+	createdAt := now()
+	stored := make([]models.LinkDeviceRule, len(rules))
+	for i := range rules {
+		rules[i].LinkID = linkID
+		fillRowDefaults(&rules[i].ID, &rules[i].CreatedAt, &rules[i].UpdatedAt, createdAt)
+		stored[i] = rules[i]
+		stored[i].FallbackURL = clonePtr(rules[i].FallbackURL)
+		stored[i].Link = models.Link{}
+	}
+	r.db.deviceRules[linkID] = stored
+	return nil
+}
+
+// ReplaceChannels atomically replaces all channel variants for a link
+func (r *LinkRepository) ReplaceChannels(_ context.Context, linkID uuid.UUID, channels []models.LinkChannel) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.checkRoutingRules(linkID, len(channels), func(i int) string { return channels[i].Name }); err != nil {
+		return err
+	}
+	createdAt := now()
+	stored := make([]models.LinkChannel, len(channels))
+	for i := range channels {
+		channels[i].LinkID = linkID
+		fillRowDefaults(&channels[i].ID, &channels[i].CreatedAt, &channels[i].UpdatedAt, createdAt)
+		stored[i] = channels[i]
+		stored[i].UTM = cloneUTM(channels[i].UTM)
+		stored[i].Link = models.Link{}
+	}
+	r.db.channels[linkID] = stored
+	return nil
+}
+
+// GetVariantClickStats counts clicks and distinct visitor IPs per variant of a link
+func (r *LinkRepository) GetVariantClickStats(_ context.Context, linkID uuid.UUID) ([]repository.VariantClickStats, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	byVariant := make(map[uuid.UUID]*repository.VariantClickStats)
+	visitors := make(map[uuid.UUID]map[string]bool)
+	for _, click := range r.db.clicks {
+		if click.LinkID != linkID || click.VariantID == nil {
+			continue
+		}
+		stats, ok := byVariant[*click.VariantID]
+		if !ok {
+			stats = &repository.VariantClickStats{VariantID: *click.VariantID}
+			byVariant[*click.VariantID] = stats
+			visitors[*click.VariantID] = make(map[string]bool)
+		}
+		stats.Clicks++
+		// COUNT(DISTINCT ip_address) skips NULLs
+		if click.IPAddress != nil && !visitors[*click.VariantID][*click.IPAddress] {
+			visitors[*click.VariantID][*click.IPAddress] = true
+			stats.UniqueVisitors++
+		}
+	}
+
+	var stats []repository.VariantClickStats
+	for _, s := range byVariant {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].VariantID.String() < stats[j].VariantID.String() })
+	return stats, nil
+}
+
+// ActivateScheduled moves scheduled links whose start time has passed to active
+func (r *LinkRepository) ActivateScheduled(_ context.Context, at time.Time) (int64, error) {
+	return r.updateWhere(func(link *models.Link) bool {
+		if link.Status != models.LinkStatusScheduled || link.ArchivedAt != nil || !link.IsStarted(at) {
+			return false
+		}
+		link.Status = models.LinkStatusActive
+		link.UpdatedAt = at
+		return true
+	}), nil
+}
+
+// ExpireDue marks links past their expiry time or click cap as expired
+func (r *LinkRepository) ExpireDue(_ context.Context, at time.Time) (int64, error) {
+	return r.updateWhere(func(link *models.Link) bool {
+		live := link.Status == models.LinkStatusScheduled || link.Status == models.LinkStatusActive
+		if !live || link.ArchivedAt != nil || !link.IsPastLimits(at) {
+			return false
+		}
+		link.Status = models.LinkStatusExpired
+		link.UpdatedAt = at
+		return true
+	}), nil
+}
+
+// ArchiveExpired archives links that expired before the given cutoff
+func (r *LinkRepository) ArchiveExpired(_ context.Context, cutoff, at time.Time) (int64, error) {
+	return r.updateWhere(func(link *models.Link) bool {
+		expiredAt := link.UpdatedAt
+		if link.ExpiresAt != nil {
+			expiredAt = *link.ExpiresAt
+		}
+		if link.Status != models.LinkStatusExpired || link.ArchivedAt != nil || expiredAt.After(cutoff) {
+			return false
+		}
+		link.ArchivedAt = &at
+		link.UpdatedAt = at
+		return true
+	}), nil
+}
+
+// ClaimDueForCheck claims live links whose next scheduled check is due, most overdue first, with
+// their owners, pushing each claimed link lease into the future
+func (r *LinkRepository) ClaimDueForCheck(_ context.Context, at time.Time, lease time.Duration, limit int) ([]models.Link, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var links []models.Link
+	for _, link := range r.db.links {
+		if link.Status != models.LinkStatusActive || link.ArchivedAt != nil {
+			continue
+		}
+		if link.NextCheckAt == nil || !link.NextCheckAt.After(at) {
+			links = append(links, cloneLink(link))
+		}
+	}
+	sort.Slice(links, func(i, j int) bool { return beforeNullsFirst(links[i].NextCheckAt, links[j].NextCheckAt) })
+	links = limited(links, limit)
+
+	leaseEnd := at.Add(lease)
+	for i := range links {
+		stored := r.db.links[links[i].ID]
+		stored.NextCheckAt = &leaseEnd
+		r.db.links[stored.ID] = stored
+		links[i].User = cloneUser(r.db.users[links[i].UserID])
+	}
+	return links, nil
+}
+
+// UpdateHealth stores the health fields of a link after a check
+func (r *LinkRepository) UpdateHealth(_ context.Context, link *models.Link) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	stored, ok := r.db.links[link.ID]
+	if !ok {
+		return nil
+	}
+	link.UpdatedAt = now()
+	stored.IsHealthy = link.IsHealthy
+	stored.LastStatusCode = link.LastStatusCode
+	stored.LastResponseTime = link.LastResponseTime
+	stored.LastCheckedAt = link.LastCheckedAt
+	stored.LastWorkingAt = link.LastWorkingAt
+	stored.FinalURL = link.FinalURL
+	stored.ConsecutiveFailures = link.ConsecutiveFailures
+	stored.AlertedDownAt = link.AlertedDownAt
+	stored.RecoveredAt = link.RecoveredAt
+	stored.NextCheckAt = link.NextCheckAt
+	stored.CheckClickCount = link.CheckClickCount
+	stored.UpdatedAt = link.UpdatedAt
+	r.db.links[link.ID] = cloneLink(stored)
+	return nil
+}
+
+// Reschedule sets when a link is next due for a health check
+func (r *LinkRepository) Reschedule(_ context.Context, id uuid.UUID, nextCheckAt time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if stored, ok := r.db.links[id]; ok {
+		stored.NextCheckAt = &nextCheckAt
+		stored.UpdatedAt = now()
+		r.db.links[id] = stored
+	}
+	return nil
+}
+
+// ClaimClick increments a capped link's click counter if it is still below max_clicks,
+// reporting whether it was
+func (r *LinkRepository) ClaimClick(_ context.Context, id uuid.UUID) (bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	stored, ok := r.db.links[id]
+	if !ok || stored.MaxClicks == nil || stored.ClickCount >= *stored.MaxClicks {
+		return false, nil
+	}
+	stored.ClickCount++
+	r.db.links[id] = stored
+	return true, nil
+}
+
+// RecordClick stores a click and, unless ClaimClick already has (counted), increments the
+// link's click counter
+func (r *LinkRepository) RecordClick(_ context.Context, click *models.Click, counted bool) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	stored, ok := r.db.links[click.LinkID]
+	if !ok {
+		return gorm.ErrForeignKeyViolated
+	}
+	if click.ID == uuid.Nil {
+		click.ID = uuid.New()
+	}
+	if _, ok := r.db.clicks[click.ID]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	if click.ClickedAt.IsZero() {
+		click.ClickedAt = now()
+	}
+	row := *click
+	row.Referrer = clonePtr(click.Referrer)
+	row.UserAgent = clonePtr(click.UserAgent)
+	row.IPAddress = clonePtr(click.IPAddress)
+	row.Country = clonePtr(click.Country)
+	row.VariantID = clonePtr(click.VariantID)
+	row.Link = models.Link{}
+	r.db.clicks[click.ID] = row
+
+	if !counted {
+		stored.ClickCount++
+		r.db.links[stored.ID] = stored
+	}
+	return nil
+}
+
+// GetHealthCounts counts a user's unarchived links and how many are unhealthy
+func (r *LinkRepository) GetHealthCounts(_ context.Context, userID uuid.UUID) (repository.LinkHealthCounts, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var counts repository.LinkHealthCounts
+	for _, link := range r.db.links {
+		if link.UserID != userID || link.ArchivedAt != nil {
+			continue
+		}
+		counts.Total++
+		if !link.IsHealthy {
+			counts.Unhealthy++
+		}
+	}
+	return counts, nil
+}
+
+// GetUnhealthy retrieves a user's unhealthy, unarchived links, longest-failing first
+func (r *LinkRepository) GetUnhealthy(_ context.Context, userID uuid.UUID, limit int) ([]models.Link, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var links []models.Link
+	for _, link := range r.db.links {
+		if link.UserID == userID && link.ArchivedAt == nil && !link.IsHealthy {
+			links = append(links, cloneLink(link))
+		}
+	}
+	sort.Slice(links, func(i, j int) bool { return beforeNullsFirst(links[i].LastWorkingAt, links[j].LastWorkingAt) })
+	return limited(links, limit), nil
+}
+
+// CountClicksSince counts clicks on all of a user's links since a time
+func (r *LinkRepository) CountClicksSince(_ context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var count int64
+	for _, click := range r.db.clicks {
+		if r.db.links[click.LinkID].UserID == userID && !click.ClickedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// updateWhere applies update to every stored link, counting those it reports changing
+func (r *LinkRepository) updateWhere(update func(link *models.Link) bool) int64 {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var affected int64
+	for id, link := range r.db.links {
+		if update(&link) {
+			r.db.links[id] = link
+			affected++
+		}
+	}
+	return affected
+}
+
+// withRoutingRules copies a stored link along with its routing rules, as the gorm preloads
+// would; callers must hold the lock
+func (db *DB) withRoutingRules(stored models.Link) models.Link {
+	link := cloneLink(stored)
+	link.GeoRules = append([]models.LinkGeoRule{}, db.geoRules[link.ID]...)
+	link.Variants = append([]models.LinkVariant{}, db.variants[link.ID]...)
+	link.DeviceRules = make([]models.LinkDeviceRule, len(db.deviceRules[link.ID]))
+	for i, rule := range db.deviceRules[link.ID] {
+		rule.FallbackURL = clonePtr(rule.FallbackURL)
+		link.DeviceRules[i] = rule
+	}
+	link.Channels = make([]models.LinkChannel, len(db.channels[link.ID]))
+	for i, channel := range db.channels[link.ID] {
+		channel.UTM = cloneUTM(channel.UTM)
+		link.Channels[i] = channel
+	}
+	return link
+}
+
+// checkRoutingRules validates a replacement set of n routing rules for a link: the link must
+// exist, and key, when set, must be unique per link as the table's unique index requires.
+// Callers must hold the write lock.
+func (db *DB) checkRoutingRules(linkID uuid.UUID, n int, key func(i int) string) error {
+	if n == 0 {
+		return nil
+	}
+	if _, ok := db.links[linkID]; !ok {
+		return gorm.ErrForeignKeyViolated
+	}
+	if key == nil {
+		return nil
+	}
+	seen := make(map[string]bool, n)
+	for i := 0; i < n; i++ {
+		if seen[key(i)] {
+			return gorm.ErrDuplicatedKey
+		}
+		seen[key(i)] = true
+	}
+	return nil
+}
+
+// fillRowDefaults sets the ID and timestamps of a new row as an INSERT through gorm would
+func fillRowDefaults(id *uuid.UUID, createdAt, updatedAt *time.Time, at time.Time) {
+	if *id == uuid.Nil {
+		*id = uuid.New()
+	}
+	if createdAt.IsZero() {
+		*createdAt = at
+	}
+	if updatedAt.IsZero() {
+		*updatedAt = at
+	}
+}
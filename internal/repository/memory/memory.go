@@ -0,0 +1,264 @@
+// Package memory provides in-memory implementations of the repository stores for tests that
+// shouldn't need Postgres. They mirror the database's observable behaviour: missing rows return
+// gorm.ErrRecordNotFound, unique indexes return gorm.ErrDuplicatedKey, foreign keys return
+// gorm.ErrForeignKeyViolated, and deleting a user cascades to the rows they own.
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/google/uuid"
+)
+
+// DB holds the rows shared by every store built on it, so cascades and lookups across
+// aggregates behave as they would in one database
+type DB struct {
+	mu    sync.RWMutex
+	users map[uuid.UUID]models.User
+	tags  map[uuid.UUID]models.AffiliateTag
+
+	links map[uuid.UUID]models.Link
+	// Routing rules are keyed by link ID and kept in insertion order
+	geoRules    map[uuid.UUID][]models.LinkGeoRule
+	variants    map[uuid.UUID][]models.LinkVariant
+	deviceRules map[uuid.UUID][]models.LinkDeviceRule
+	channels    map[uuid.UUID][]models.LinkChannel
+	clicks      map[uuid.UUID]models.Click
+
+	checks       map[uuid.UUID]models.LinkCheckHistory
+	contentRules map[uuid.UUID]models.ContentRule
+
+	prefs  map[uuid.UUID]models.NotificationPreference // keyed by user ID
+	alerts map[uuid.UUID]models.Alert
+
+	endpoints  map[uuid.UUID]models.WebhookEndpoint
+	deliveries map[uuid.UUID]models.WebhookDelivery
+
+	incidents map[uuid.UUID]models.LinkIncident
+	rollups   map[rollupKey]models.LinkCheckRollup
+}
+
+// rollupKey is the primary key of a daily rollup
+type rollupKey struct {
+	LinkID uuid.UUID
+	Day    time.Time
+}
+
+// NewDB creates an empty in-memory database
+func NewDB() *DB {
+	return &DB{
+		users:        make(map[uuid.UUID]models.User),
+		tags:         make(map[uuid.UUID]models.AffiliateTag),
+		links:        make(map[uuid.UUID]models.Link),
+		geoRules:     make(map[uuid.UUID][]models.LinkGeoRule),
+		variants:     make(map[uuid.UUID][]models.LinkVariant),
+		deviceRules:  make(map[uuid.UUID][]models.LinkDeviceRule),
+		channels:     make(map[uuid.UUID][]models.LinkChannel),
+		clicks:       make(map[uuid.UUID]models.Click),
+		checks:       make(map[uuid.UUID]models.LinkCheckHistory),
+		contentRules: make(map[uuid.UUID]models.ContentRule),
+		prefs:        make(map[uuid.UUID]models.NotificationPreference),
+		alerts:       make(map[uuid.UUID]models.Alert),
+		endpoints:    make(map[uuid.UUID]models.WebhookEndpoint),
+		deliveries:   make(map[uuid.UUID]models.WebhookDelivery),
+		incidents:    make(map[uuid.UUID]models.LinkIncident),
+		rollups:      make(map[rollupKey]models.LinkCheckRollup),
+	}
+}
+
+// limited truncates rows to limit as a LIMIT clause would; a negative limit means no limit
+func limited[T any](rows []T, limit int) []T {
+	if limit >= 0 && len(rows) > limit {
+		return rows[:limit]
+	}
+	return rows
+}
+
+// beforeNullsFirst orders optional times ascending with NULLs first, as ORDER BY ... ASC NULLS FIRST
+func beforeNullsFirst(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b != nil
+	}
+	return a.Before(*b)
+}
+
+// now returns the current time at the microsecond precision Postgres stores
+func now() time.Time {
+	return time.Now().Round(time.Microsecond)
+}
+
+// clonePtr copies the value behind p so stored rows never alias a caller's struct
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// cloneUser copies a user's columns, dropping relationships as a query without preloads would
+func cloneUser(u models.User) models.User {
+	u.PasswordHash = clonePtr(u.PasswordHash)
+	u.Name = clonePtr(u.Name)
+	u.AvatarURL = clonePtr(u.AvatarURL)
+	u.StripeCustomerID = clonePtr(u.StripeCustomerID)
+	u.SubscriptionStatus = clonePtr(u.SubscriptionStatus)
+	u.SubscriptionID = clonePtr(u.SubscriptionID)
+	u.TrialEndsAt = clonePtr(u.TrialEndsAt)
+	u.CurrentPeriodEnd = clonePtr(u.CurrentPeriodEnd)
+	u.LastLoginAt = clonePtr(u.LastLoginAt)
+	u.DefaultUTM = models.UTMTemplate{
+		Source:   clonePtr(u.DefaultUTM.Source),
+		Medium:   clonePtr(u.DefaultUTM.Medium),
+		Campaign: clonePtr(u.DefaultUTM.Campaign),
+		Content:  clonePtr(u.DefaultUTM.Content),
+	}
+	u.OAuthAccounts = nil
+	u.Links = nil
+	u.Subscriptions = nil
+	u.AffiliateTags = nil
+	return u
+}
+
+// cloneTag copies an affiliate tag's columns, dropping its user relationship
+func cloneTag(t models.AffiliateTag) models.AffiliateTag {
+	t.User = models.User{}
+	return t
+}
+
+// cloneUTM copies a UTM template
+func cloneUTM(u models.UTMTemplate) models.UTMTemplate {
+	return models.UTMTemplate{
+		Source:   clonePtr(u.Source),
+		Medium:   clonePtr(u.Medium),
+		Campaign: clonePtr(u.Campaign),
+		Content:  clonePtr(u.Content),
+	}
+}
+
+// cloneLink copies a link's columns, dropping relationships as a query without preloads would.
+// HasPassword is derived as the model's AfterFind hook does.
+func cloneLink(l models.Link) models.Link {
+	l.Title = clonePtr(l.Title)
+	l.Description = clonePtr(l.Description)
+	l.Category = clonePtr(l.Category)
+	l.Platform = clonePtr(l.Platform)
+	if l.Tags != nil {
+		l.Tags = append([]string{}, l.Tags...)
+	}
+	l.AffiliateNetwork = clonePtr(l.AffiliateNetwork)
+	l.AffiliateID = clonePtr(l.AffiliateID)
+	l.LastStatusCode = clonePtr(l.LastStatusCode)
+	l.LastResponseTime = clonePtr(l.LastResponseTime)
+	l.LastCheckedAt = clonePtr(l.LastCheckedAt)
+	l.LastWorkingAt = clonePtr(l.LastWorkingAt)
+	l.FinalURL = clonePtr(l.FinalURL)
+	l.NextCheckAt = clonePtr(l.NextCheckAt)
+	l.AlertedDownAt = clonePtr(l.AlertedDownAt)
+	l.RecoveredAt = clonePtr(l.RecoveredAt)
+	l.StartsAt = clonePtr(l.StartsAt)
+	l.ExpiresAt = clonePtr(l.ExpiresAt)
+	l.MaxClicks = clonePtr(l.MaxClicks)
+	l.FallbackURL = clonePtr(l.FallbackURL)
+	l.PasswordHash = clonePtr(l.PasswordHash)
+	l.HasPassword = l.PasswordHash != nil
+	l.UTM = cloneUTM(l.UTM)
+	l.ArchivedAt = clonePtr(l.ArchivedAt)
+	l.User = models.User{}
+	l.Clicks = nil
+	l.CheckHistory = nil
+	l.GeoRules = nil
+	l.Variants = nil
+	l.DeviceRules = nil
+	l.Channels = nil
+	return l
+}
+
+// cloneCheck copies a check's columns, dropping its link relationship
+func cloneCheck(c models.LinkCheckHistory) models.LinkCheckHistory {
+	c.ResponseTime = clonePtr(c.ResponseTime)
+	c.ErrorMessage = clonePtr(c.ErrorMessage)
+	c.FinalURL = clonePtr(c.FinalURL)
+	if c.RedirectChain != nil {
+		c.RedirectChain = append([]models.RedirectHop{}, c.RedirectChain...)
+	}
+	c.Link = models.Link{}
+	return c
+}
+
+// cloneEndpoint copies a webhook endpoint's columns, dropping its relationships
+func cloneEndpoint(e models.WebhookEndpoint) models.WebhookEndpoint {
+	e.Description = clonePtr(e.Description)
+	if e.Events != nil {
+		e.Events = append([]string{}, e.Events...)
+	}
+	e.User = models.User{}
+	e.Deliveries = nil
+	return e
+}
+
+// cloneDelivery copies a webhook delivery's columns, dropping its endpoint relationship
+func cloneDelivery(d models.WebhookDelivery) models.WebhookDelivery {
+	if d.Payload != nil {
+		d.Payload = append([]byte{}, d.Payload...)
+	}
+	d.LastStatusCode = clonePtr(d.LastStatusCode)
+	d.LastError = clonePtr(d.LastError)
+	d.DeliveredAt = clonePtr(d.DeliveredAt)
+	d.Endpoint = models.WebhookEndpoint{}
+	return d
+}
+
+// deleteUser deletes a user and, as ON DELETE CASCADE would, everything they own; callers
+// must hold the write lock
+func (db *DB) deleteUser(id uuid.UUID) {
+	delete(db.users, id)
+	delete(db.prefs, id)
+	for linkID, link := range db.links {
+		if link.UserID == id {
+			db.deleteLink(linkID)
+		}
+	}
+	for endpointID, endpoint := range db.endpoints {
+		if endpoint.UserID == id {
+			db.deleteEndpoint(endpointID)
+		}
+	}
+	deleteWhere(db.tags, func(t models.AffiliateTag) bool { return t.UserID == id })
+	deleteWhere(db.contentRules, func(r models.ContentRule) bool { return r.UserID == id })
+	deleteWhere(db.alerts, func(a models.Alert) bool { return a.UserID == id })
+	deleteWhere(db.incidents, func(i models.LinkIncident) bool { return i.UserID == id })
+}
+
+// deleteLink deletes a link along with its rules, clicks, checks, alerts and uptime history;
+// callers must hold the write lock
+func (db *DB) deleteLink(id uuid.UUID) {
+	delete(db.links, id)
+	delete(db.geoRules, id)
+	delete(db.variants, id)
+	delete(db.deviceRules, id)
+	delete(db.channels, id)
+	deleteWhere(db.clicks, func(c models.Click) bool { return c.LinkID == id })
+	deleteWhere(db.checks, func(c models.LinkCheckHistory) bool { return c.LinkID == id })
+	deleteWhere(db.contentRules, func(r models.ContentRule) bool { return r.LinkID != nil && *r.LinkID == id })
+	deleteWhere(db.alerts, func(a models.Alert) bool { return a.LinkID == id })
+	deleteWhere(db.incidents, func(i models.LinkIncident) bool { return i.LinkID == id })
+	deleteWhere(db.rollups, func(r models.LinkCheckRollup) bool { return r.LinkID == id })
+}
+
+// deleteEndpoint deletes a webhook endpoint and its deliveries; callers must hold the write lock
+func (db *DB) deleteEndpoint(id uuid.UUID) {
+	delete(db.endpoints, id)
+	deleteWhere(db.deliveries, func(d models.WebhookDelivery) bool { return d.EndpointID == id })
+}
+
+// deleteWhere removes the rows of table matching match
+func deleteWhere[K comparable, V any](table map[K]V, match func(V) bool) {
+	for key, row := range table {
+		if match(row) {
+			delete(table, key)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/1shoukr/linkvault/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var _ repository.UptimeStore = (*UptimeRepository)(nil)
+
+// UptimeRepository is an in-memory repository.UptimeStore
+type UptimeRepository struct {
+	db *DB
+}
+
+// NewUptimeRepository creates an uptime repository backed by db
+func NewUptimeRepository(db *DB) *UptimeRepository {
+	return &UptimeRepository{db: db}
+}
+
+// OpenIncident starts an incident for a link unless one is already open
+func (r *UptimeRepository) OpenIncident(_ context.Context, incident *models.LinkIncident) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for _, open := range r.db.incidents {
+		if open.LinkID == incident.LinkID && open.EndedAt == nil {
+			return nil
+		}
+	}
+	if _, ok := r.db.links[incident.LinkID]; !ok {
+		return gorm.ErrForeignKeyViolated
+	}
+	if _, ok := r.db.users[incident.UserID]; !ok {
+		return gorm.ErrForeignKeyViolated
+	}
+	if incident.ID == uuid.Nil {
+		incident.ID = uuid.New()
+	}
+	if _, ok := r.db.incidents[incident.ID]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	r.db.incidents[incident.ID] = cloneIncident(*incident)
+	return nil
+}
+
+// CloseIncidents ends a link's open incident
+func (r *UptimeRepository) CloseIncidents(_ context.Context, linkID uuid.UUID, at time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for id, incident := range r.db.incidents {
+		if incident.LinkID == linkID && incident.EndedAt == nil {
+			incident.EndedAt = &at
+			r.db.incidents[id] = incident
+		}
+	}
+	return nil
+}
+
+// GetIncidents retrieves incidents in scope that overlap [from, to), oldest first
+func (r *UptimeRepository) GetIncidents(_ context.Context, scope repository.UptimeScope, from, to time.Time) ([]models.LinkIncident, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var incidents []models.LinkIncident
+	for _, incident := range r.db.incidents {
+		if !inScope(scope, incident.UserID, incident.LinkID) {
+			continue
+		}
+		if incident.StartedAt.Before(to) && (incident.EndedAt == nil || incident.EndedAt.After(from)) {
+			incidents = append(incidents, cloneIncident(incident))
+		}
+	}
+	sort.Slice(incidents, func(i, j int) bool { return incidents[i].StartedAt.Before(incidents[j].StartedAt) })
+	return incidents, nil
+}
+
+// GetRollups retrieves daily rollups in scope for days in [fromDay, toDay)
+func (r *UptimeRepository) GetRollups(_ context.Context, scope repository.UptimeScope, fromDay, toDay time.Time) ([]models.LinkCheckRollup, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var rollups []models.LinkCheckRollup
+	for _, rollup := range r.db.rollups {
+		if !inScope(scope, rollup.UserID, rollup.LinkID) {
+			continue
+		}
+		if !rollup.Day.Before(fromDay) && rollup.Day.Before(toDay) {
+			rollups = append(rollups, cloneRollup(rollup))
+		}
+	}
+	sort.Slice(rollups, func(i, j int) bool { return rollups[i].Day.Before(rollups[j].Day) })
+	return rollups, nil
+}
+
+// GetSamples retrieves checks in scope made in [from, to)
+func (r *UptimeRepository) GetSamples(_ context.Context, scope repository.UptimeScope, from, to time.Time) ([]repository.CheckSample, error) {
+	return r.samples(from, to, func(userID, linkID uuid.UUID) bool { return inScope(scope, userID, linkID) }), nil
+}
+
+// GetAllSamples retrieves every check made in [from, to), for building rollups
+func (r *UptimeRepository) GetAllSamples(_ context.Context, from, to time.Time) ([]repository.CheckSample, error) {
+	return r.samples(from, to, func(uuid.UUID, uuid.UUID) bool { return true }), nil
+}
+
+// GetLatestRollupDay returns the most recent day that has been rolled up, or nil if none has
+func (r *UptimeRepository) GetLatestRollupDay(_ context.Context) (*time.Time, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var latest *time.Time
+	for _, rollup := range r.db.rollups {
+		if latest == nil || rollup.Day.After(*latest) {
+			day := rollup.Day
+			latest = &day
+		}
+	}
+	return latest, nil
+}
+
+// GetEarliestCheckTime returns when the oldest recorded check ran, or nil if there are none
+func (r *UptimeRepository) GetEarliestCheckTime(_ context.Context) (*time.Time, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var earliest *time.Time
+	for _, check := range r.db.checks {
+		if earliest == nil || check.CheckedAt.Before(*earliest) {
+			checkedAt := check.CheckedAt
+			earliest = &checkedAt
+		}
+	}
+	return earliest, nil
+}
+
+// UpsertRollups stores rollups, replacing any existing rollup for the same link and day; if
+// any rollup is invalid none are stored
+func (r *UptimeRepository) UpsertRollups(_ context.Context, rollups []models.LinkCheckRollup) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for _, rollup := range rollups {
+		if _, ok := r.db.links[rollup.LinkID]; !ok {
+			return gorm.ErrForeignKeyViolated
+		}
+	}
+
+	updatedAt := now()
+	for i := range rollups {
+		rollup := &rollups[i]
+		rollup.UpdatedAt = updatedAt
+		key := rollupKey{LinkID: rollup.LinkID, Day: rollup.Day.UTC()}
+		if stored, ok := r.db.rollups[key]; ok {
+			stored.Checks = rollup.Checks
+			stored.HealthyChecks = rollup.HealthyChecks
+			stored.ResponseTimes = rollup.ResponseTimes
+			stored.UpdatedAt = updatedAt
+			r.db.rollups[key] = cloneRollup(stored)
+			continue
+		}
+		stored := cloneRollup(*rollup)
+		stored.Day = key.Day
+		r.db.rollups[key] = stored
+	}
+	return nil
+}
+
+// samples retrieves the checks made in [from, to) on links matching match, skipping
+// rate-limited checks
+func (r *UptimeRepository) samples(from, to time.Time, match func(userID, linkID uuid.UUID) bool) []repository.CheckSample {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var samples []repository.CheckSample
+	for _, check := range r.db.checks {
+		link, ok := r.db.links[check.LinkID]
+		if !ok || check.RateLimited || !match(link.UserID, link.ID) {
+			continue
+		}
+		if !check.CheckedAt.Before(from) && check.CheckedAt.Before(to) {
+			samples = append(samples, repository.CheckSample{
+				LinkID:       check.LinkID,
+				UserID:       link.UserID,
+				IsHealthy:    check.IsHealthy,
+				ResponseTime: clonePtr(check.ResponseTime),
+			})
+		}
+	}
+	return samples
+}
+
+// inScope reports whether a row owned by userID and linkID falls within scope
+func inScope(scope repository.UptimeScope, userID, linkID uuid.UUID) bool {
+	return userID == scope.UserID && (scope.LinkID == nil || linkID == *scope.LinkID)
+}
+
+// cloneIncident copies an incident's columns, dropping its relationships
+func cloneIncident(i models.LinkIncident) models.LinkIncident {
+	i.EndedAt = clonePtr(i.EndedAt)
+	i.Cause = clonePtr(i.Cause)
+	i.Link = models.Link{}
+	i.User = models.User{}
+	return i
+}
+
+// cloneRollup copies a rollup's columns, dropping its link relationship
+func cloneRollup(r models.LinkCheckRollup) models.LinkCheckRollup {
+	if r.ResponseTimes != nil {
+		r.ResponseTimes = append(models.ResponseHistogram{}, r.ResponseTimes...)
+	}
+	r.Link = models.Link{}
+	return r
+}
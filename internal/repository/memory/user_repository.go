@@ -0,0 +1,194 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/1shoukr/linkvault/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var _ repository.UserStore = (*UserRepository)(nil)
+
+// UserRepository is an in-memory repository.UserStore
+type UserRepository struct {
+	db *DB
+}
+
+// NewUserRepository creates a user repository backed by db
+func NewUserRepository(db *DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// GetByID retrieves a user by their ID
+func (r *UserRepository) GetByID(_ context.Context, id uuid.UUID) (*models.User, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	user, ok := r.db.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	user = cloneUser(user)
+	return &user, nil
+}
+
+// GetByEmail retrieves a user by their email; like the unique index, matching is case-sensitive
+func (r *UserRepository) GetByEmail(_ context.Context, email string) (*models.User, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	user, ok := r.db.userByEmail(email)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	user = cloneUser(user)
+	return &user, nil
+}
+
+// Create creates a new user, filling in the ID, timestamps and column defaults
+func (r *UserRepository) Create(_ context.Context, user *models.User) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+	return r.db.insertUser(user)
+}
+
+// Update saves every column of the user, inserting it if it doesn't exist yet
+func (r *UserRepository) Update(_ context.Context, user *models.User) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.users[user.ID]; !ok || user.ID == uuid.Nil {
+		return r.db.insertUser(user)
+	}
+	if other, ok := r.db.userByEmail(user.Email); ok && other.ID != user.ID {
+		return gorm.ErrDuplicatedKey
+	}
+	user.UpdatedAt = now()
+	r.db.users[user.ID] = cloneUser(*user)
+	return nil
+}
+
+// Delete deletes a user by ID, cascading to everything they own
+func (r *UserRepository) Delete(_ context.Context, id uuid.UUID) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	r.db.deleteUser(id)
+	return nil
+}
+
+// GetAll retrieves all users, oldest first
+func (r *UserRepository) GetAll(_ context.Context) ([]models.User, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	users := make([]models.User, 0, len(r.db.users))
+	for _, user := range r.db.users {
+		users = append(users, cloneUser(user))
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if !users[i].CreatedAt.Equal(users[j].CreatedAt) {
+			return users[i].CreatedAt.Before(users[j].CreatedAt)
+		}
+		return users[i].ID.String() < users[j].ID.String()
+	})
+	return users, nil
+}
+
+// GetAffiliateTags retrieves the affiliate tags configured by a user, ordered by network and tag
+func (r *UserRepository) GetAffiliateTags(_ context.Context, userID uuid.UUID) ([]models.AffiliateTag, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var tags []models.AffiliateTag
+	for _, tag := range r.db.tags {
+		if tag.UserID == userID {
+			tags = append(tags, cloneTag(tag))
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Network != tags[j].Network {
+			return tags[i].Network < tags[j].Network
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+	return tags, nil
+}
+
+// ReplaceAffiliateTags atomically replaces all affiliate tags configured by a user; on error
+// the existing tags are left untouched
+func (r *UserRepository) ReplaceAffiliateTags(_ context.Context, userID uuid.UUID, tags []models.AffiliateTag) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if len(tags) > 0 {
+		if _, ok := r.db.users[userID]; !ok {
+			return gorm.ErrForeignKeyViolated
+		}
+	}
+	seen := make(map[[2]string]bool, len(tags))
+	for _, tag := range tags {
+		key := [2]string{tag.Network, tag.Tag}
+		if seen[key] {
+			return gorm.ErrDuplicatedKey
+		}
+		seen[key] = true
+	}
+
+	for tagID, tag := range r.db.tags {
+		if tag.UserID == userID {
+			delete(r.db.tags, tagID)
+		}
+	}
+	createdAt := now()
+	for i := range tags {
+		tags[i].UserID = userID
+		if tags[i].ID == uuid.Nil {
+			tags[i].ID = uuid.New()
+		}
+		if tags[i].CreatedAt.IsZero() {
+			tags[i].CreatedAt = createdAt
+		}
+		r.db.tags[tags[i].ID] = cloneTag(tags[i])
+	}
+	return nil
+}
+
+// userByEmail finds a user by exact email; callers must hold the lock
+func (db *DB) userByEmail(email string) (models.User, bool) {
+	for _, user := range db.users {
+		if user.Email == email {
+			return user, true
+		}
+	}
+	return models.User{}, false
+}
+
+// insertUser stores a new user as an INSERT would; callers must hold the write lock
+func (db *DB) insertUser(user *models.User) error {
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+	if _, ok := db.users[user.ID]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	if _, ok := db.userByEmail(user.Email); ok {
+		return gorm.ErrDuplicatedKey
+	}
+
+	createdAt := now()
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = createdAt
+	}
+	if user.UpdatedAt.IsZero() {
+		user.UpdatedAt = createdAt
+	}
+	if user.Plan == "" {
+		user.Plan = models.PlanFree
+	}
+	db.users[user.ID] = cloneUser(*user)
+	return nil
+}
@@ -0,0 +1,216 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/1shoukr/linkvault/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var _ repository.WebhookStore = (*WebhookRepository)(nil)
+
+// WebhookRepository is an in-memory repository.WebhookStore
+type WebhookRepository struct {
+	db *DB
+}
+
+// NewWebhookRepository creates a webhook repository backed by db
+func NewWebhookRepository(db *DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// GetEndpointsByUserID retrieves all webhook endpoints for a user, oldest first
+func (r *WebhookRepository) GetEndpointsByUserID(_ context.Context, userID uuid.UUID) ([]models.WebhookEndpoint, error) {
+	return r.endpointsWhere(func(e models.WebhookEndpoint) bool { return e.UserID == userID }), nil
+}
+
+// GetActiveEndpoints retrieves a user's enabled webhook endpoints
+func (r *WebhookRepository) GetActiveEndpoints(_ context.Context, userID uuid.UUID) ([]models.WebhookEndpoint, error) {
+	return r.endpointsWhere(func(e models.WebhookEndpoint) bool { return e.UserID == userID && e.IsActive }), nil
+}
+
+// GetEndpoint retrieves a user's webhook endpoint by ID
+func (r *WebhookRepository) GetEndpoint(_ context.Context, userID, id uuid.UUID) (*models.WebhookEndpoint, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	endpoint, ok := r.db.endpoints[id]
+	if !ok || endpoint.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	endpoint = cloneEndpoint(endpoint)
+	return &endpoint, nil
+}
+
+// CreateEndpoint creates a new webhook endpoint
+func (r *WebhookRepository) CreateEndpoint(_ context.Context, endpoint *models.WebhookEndpoint) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+	return r.db.insertEndpoint(endpoint)
+}
+
+// UpdateEndpoint saves every column of a webhook endpoint, inserting it if it doesn't exist yet
+func (r *WebhookRepository) UpdateEndpoint(_ context.Context, endpoint *models.WebhookEndpoint) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.endpoints[endpoint.ID]; !ok || endpoint.ID == uuid.Nil {
+		return r.db.insertEndpoint(endpoint)
+	}
+	endpoint.UpdatedAt = now()
+	r.db.endpoints[endpoint.ID] = cloneEndpoint(*endpoint)
+	return nil
+}
+
+// DeleteEndpoint deletes a user's webhook endpoint and its deliveries, reporting whether it existed
+func (r *WebhookRepository) DeleteEndpoint(_ context.Context, userID, id uuid.UUID) (bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	endpoint, ok := r.db.endpoints[id]
+	if !ok || endpoint.UserID != userID {
+		return false, nil
+	}
+	r.db.deleteEndpoint(id)
+	return true, nil
+}
+
+// CreateDeliveries queues deliveries; if any is invalid none are stored
+func (r *WebhookRepository) CreateDeliveries(_ context.Context, deliveries []models.WebhookDelivery) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	seen := make(map[uuid.UUID]bool, len(deliveries))
+	for i := range deliveries {
+		if _, ok := r.db.endpoints[deliveries[i].EndpointID]; !ok {
+			return gorm.ErrForeignKeyViolated
+		}
+		if id := deliveries[i].ID; id != uuid.Nil {
+			if _, ok := r.db.deliveries[id]; ok || seen[id] {
+				return gorm.ErrDuplicatedKey
+			}
+			seen[id] = true
+		}
+	}
+
+	createdAt := now()
+	for i := range deliveries {
+		d := &deliveries[i]
+		fillRowDefaults(&d.ID, &d.CreatedAt, &d.UpdatedAt, createdAt)
+		if d.Status == "" {
+			d.Status = models.WebhookDeliveryPending
+		}
+		r.db.deliveries[d.ID] = cloneDelivery(*d)
+	}
+	return nil
+}
+
+// ClaimDueDeliveries claims pending deliveries whose next attempt is due, with their endpoints,
+// pushing each claimed delivery lease into the future
+func (r *WebhookRepository) ClaimDueDeliveries(_ context.Context, at time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var deliveries []models.WebhookDelivery
+	for _, delivery := range r.db.deliveries {
+		if delivery.Status == models.WebhookDeliveryPending && !delivery.NextAttemptAt.After(at) {
+			deliveries = append(deliveries, cloneDelivery(delivery))
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].NextAttemptAt.Before(deliveries[j].NextAttemptAt) })
+	deliveries = limited(deliveries, limit)
+
+	for i := range deliveries {
+		stored := r.db.deliveries[deliveries[i].ID]
+		stored.NextAttemptAt = at.Add(lease)
+		r.db.deliveries[stored.ID] = stored
+		deliveries[i].Endpoint = cloneEndpoint(r.db.endpoints[deliveries[i].EndpointID])
+	}
+	return deliveries, nil
+}
+
+// UpdateDelivery saves the outcome of a delivery attempt
+func (r *WebhookRepository) UpdateDelivery(_ context.Context, delivery *models.WebhookDelivery) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	stored, ok := r.db.deliveries[delivery.ID]
+	if !ok {
+		return nil
+	}
+	delivery.UpdatedAt = now()
+	stored.Status = delivery.Status
+	stored.Attempts = delivery.Attempts
+	stored.NextAttemptAt = delivery.NextAttemptAt
+	stored.LastStatusCode = delivery.LastStatusCode
+	stored.LastError = delivery.LastError
+	stored.DeliveredAt = delivery.DeliveredAt
+	stored.UpdatedAt = delivery.UpdatedAt
+	r.db.deliveries[delivery.ID] = cloneDelivery(stored)
+	return nil
+}
+
+// GetDeliveries retrieves the most recent deliveries for an endpoint
+func (r *WebhookRepository) GetDeliveries(_ context.Context, endpointID uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var deliveries []models.WebhookDelivery
+	for _, delivery := range r.db.deliveries {
+		if delivery.EndpointID == endpointID {
+			deliveries = append(deliveries, cloneDelivery(delivery))
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt) })
+	return limited(deliveries, limit), nil
+}
+
+// GetDelivery retrieves a delivery belonging to an endpoint
+func (r *WebhookRepository) GetDelivery(_ context.Context, endpointID, id uuid.UUID) (*models.WebhookDelivery, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	delivery, ok := r.db.deliveries[id]
+	if !ok || delivery.EndpointID != endpointID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	delivery = cloneDelivery(delivery)
+	return &delivery, nil
+}
+
+// endpointsWhere retrieves the webhook endpoints matching match, oldest first
+func (r *WebhookRepository) endpointsWhere(match func(models.WebhookEndpoint) bool) []models.WebhookEndpoint {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var endpoints []models.WebhookEndpoint
+	for _, endpoint := range r.db.endpoints {
+		if match(endpoint) {
+			endpoints = append(endpoints, cloneEndpoint(endpoint))
+		}
+	}
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].CreatedAt.Before(endpoints[j].CreatedAt) })
+	return endpoints
+}
+
+// insertEndpoint stores a new webhook endpoint as an INSERT would; callers must hold the write lock
+func (db *DB) insertEndpoint(endpoint *models.WebhookEndpoint) error {
+	if _, ok := db.users[endpoint.UserID]; !ok {
+		return gorm.ErrForeignKeyViolated
+	}
+	if endpoint.ID == uuid.Nil {
+		endpoint.ID = uuid.New()
+	}
+	if _, ok := db.endpoints[endpoint.ID]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	fillRowDefaults(&endpoint.ID, &endpoint.CreatedAt, &endpoint.UpdatedAt, now())
+	// gorm writes the column default in place of a zero value, so a new endpoint is always active
+	endpoint.IsActive = true
+	db.endpoints[endpoint.ID] = cloneEndpoint(*endpoint)
+	return nil
+}
@@ -0,0 +1,38 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/1shoukr/linkvault/internal/repository/repotest"
+)
+
+// TestStoreContracts runs every store contract against the in-memory stores and, when
+// TEST_DATABASE_URL is set, against the gorm stores on Postgres
+func TestStoreContracts(t *testing.T) {
+	contracts := []struct {
+		name string
+		run  func(*testing.T, repotest.StoresFactory)
+	}{
+		{"users", repotest.RunUserStoreContract},
+		{"links", repotest.RunLinkStoreContract},
+		{"checks", repotest.RunCheckStoreContract},
+		{"alerts", repotest.RunAlertStoreContract},
+		{"webhooks", repotest.RunWebhookStoreContract},
+		{"uptime", repotest.RunUptimeStoreContract},
+	}
+	factories := []struct {
+		name      string
+		newStores repotest.StoresFactory
+	}{
+		{"memory", repotest.MemoryStores},
+		{"postgres", repotest.PostgresStores},
+	}
+
+	for _, factory := range factories {
+		t.Run(factory.name, func(t *testing.T) {
+			for _, contract := range contracts {
+				t.Run(contract.name, func(t *testing.T) { contract.run(t, factory.newStores) })
+			}
+		})
+	}
+}
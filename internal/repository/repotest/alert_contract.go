@@ -0,0 +1,129 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/google/uuid"
+)
+
+// RunAlertStoreContract checks the behaviour services rely on from the alert store
+func RunAlertStoreContract(t *testing.T, newStores StoresFactory) {
+	t.Run("preferences default until saved", func(t *testing.T) {
+		stores := newStores(t)
+		user := newUser("prefs@example.com")
+		mustCreate(t, stores, user)
+
+		prefs, err := stores.Alerts.GetPreferences(t.Context(), user.ID)
+		if err != nil {
+			t.Fatalf("GetPreferences: %v", err)
+		}
+		want := models.DefaultNotificationPreference(user.ID)
+		if prefs.UserID != user.ID || prefs.EmailEnabled != want.EmailEnabled || prefs.FailureThreshold != want.FailureThreshold {
+			t.Fatalf("GetPreferences = %+v, want the defaults", prefs)
+		}
+
+		prefs.SlackEnabled = true
+		prefs.WeeklySummary = true
+		mustSucceed(t, "SavePreferences", stores.Alerts.SavePreferences(t.Context(), prefs))
+		// Saving again updates rather than inserting a second row
+		prefs.DiscordEnabled = true
+		mustSucceed(t, "SavePreferences", stores.Alerts.SavePreferences(t.Context(), prefs))
+
+		got, err := stores.Alerts.GetPreferences(t.Context(), user.ID)
+		if err != nil {
+			t.Fatalf("GetPreferences: %v", err)
+		}
+		if !got.SlackEnabled || !got.DiscordEnabled || !got.WeeklySummary {
+			t.Fatalf("saved preferences = %+v, want Slack, Discord and the weekly summary on", got)
+		}
+	})
+
+	t.Run("weekly summaries are due once a week", func(t *testing.T) {
+		stores := newStores(t)
+		user := newUser("weekly@example.com")
+		mustCreate(t, stores, user)
+		prefs := models.DefaultNotificationPreference(user.ID)
+		prefs.SlackEnabled = true
+		prefs.WeeklySummary = true
+		mustSucceed(t, "SavePreferences", stores.Alerts.SavePreferences(t.Context(), prefs))
+		now := time.Now().UTC().Truncate(time.Second)
+
+		if due, _ := stores.Alerts.GetWeeklySummaryDue(t.Context(), now); len(due) != 1 || due[0].UserID != user.ID {
+			t.Fatalf("GetWeeklySummaryDue before any summary = %+v, want the user", due)
+		}
+		mustSucceed(t, "MarkWeeklySummarySent", stores.Alerts.MarkWeeklySummarySent(t.Context(), user.ID, now))
+		if due, _ := stores.Alerts.GetWeeklySummaryDue(t.Context(), now); len(due) != 0 {
+			t.Fatalf("GetWeeklySummaryDue after a summary = %+v, want none", due)
+		}
+		if due, _ := stores.Alerts.GetWeeklySummaryDue(t.Context(), now.Add(7*24*time.Hour)); len(due) != 1 {
+			t.Fatalf("GetWeeklySummaryDue a week later = %+v, want the user", due)
+		}
+	})
+
+	t.Run("pending alerts are delivered once", func(t *testing.T) {
+		stores := newStores(t)
+		link := mustCreateLink(t, stores, "pending@example.com")
+		now := time.Now().UTC().Truncate(time.Second)
+		down := &models.Alert{UserID: link.UserID, LinkID: link.ID, Kind: models.AlertKindDown, Message: "down", CreatedAt: now.Add(-time.Minute)}
+		recovered := &models.Alert{UserID: link.UserID, LinkID: link.ID, Kind: models.AlertKindRecovered, Message: "up", CreatedAt: now}
+		mustSucceed(t, "Create", stores.Alerts.Create(t.Context(), down))
+		mustSucceed(t, "Create", stores.Alerts.Create(t.Context(), recovered))
+
+		if count, _ := stores.Alerts.CountSince(t.Context(), link.UserID, models.AlertKindDown, down.CreatedAt); count != 1 {
+			t.Fatalf("CountSince = %d, want 1", count)
+		}
+		users, err := stores.Alerts.GetUsersWithPending(t.Context())
+		if err != nil {
+			t.Fatalf("GetUsersWithPending: %v", err)
+		}
+		if oldest, ok := users[link.UserID]; !ok || !oldest.Equal(down.CreatedAt) {
+			t.Fatalf("GetUsersWithPending = %v, want the user with the down alert's time %v", users, down.CreatedAt)
+		}
+
+		pending, err := stores.Alerts.GetPending(t.Context(), link.UserID)
+		if err != nil {
+			t.Fatalf("GetPending: %v", err)
+		}
+		if len(pending) != 2 || pending[0].ID != down.ID || pending[0].Link.OriginalURL != link.OriginalURL {
+			t.Fatalf("GetPending = %+v, want both alerts, oldest first, with their link", pending)
+		}
+
+		mustSucceed(t, "MarkDelivered", stores.Alerts.MarkDelivered(t.Context(), []uuid.UUID{down.ID}, now))
+		if deleted, _ := stores.Alerts.DeletePending(t.Context(), link.ID, models.AlertKindDown); deleted {
+			t.Fatal("DeletePending removed a delivered alert")
+		}
+		if deleted, _ := stores.Alerts.DeletePending(t.Context(), link.ID, models.AlertKindRecovered); !deleted {
+			t.Fatal("DeletePending left the pending recovery alert")
+		}
+		if users, _ := stores.Alerts.GetUsersWithPending(t.Context()); len(users) != 0 {
+			t.Fatalf("GetUsersWithPending after delivery = %v, want none", users)
+		}
+	})
+
+	t.Run("alerts and preferences go with their link and owner", func(t *testing.T) {
+		stores := newStores(t)
+		link := mustCreateLink(t, stores, "alerts-cascade@example.com")
+		other := mustCreateLinkFor(t, stores, &models.Link{UserID: link.UserID, OriginalURL: "https://example.com/other"})
+		prefs := models.DefaultNotificationPreference(link.UserID)
+		prefs.SlackEnabled = true
+		prefs.WeeklySummary = true
+		mustSucceed(t, "SavePreferences", stores.Alerts.SavePreferences(t.Context(), prefs))
+		for _, linkID := range []uuid.UUID{link.ID, other.ID} {
+			mustSucceed(t, "Create", stores.Alerts.Create(t.Context(), &models.Alert{UserID: link.UserID, LinkID: linkID, Kind: models.AlertKindDown, Message: "down"}))
+		}
+
+		mustSucceed(t, "Delete link", stores.Links.Delete(t.Context(), link.ID))
+		if pending, _ := stores.Alerts.GetPending(t.Context(), link.UserID); len(pending) != 1 || pending[0].LinkID != other.ID {
+			t.Fatalf("alerts after deleting the link = %+v, want only the other link's", pending)
+		}
+		mustSucceed(t, "Delete user", stores.Users.Delete(t.Context(), link.UserID))
+		if users, _ := stores.Alerts.GetUsersWithPending(t.Context()); len(users) != 0 {
+			t.Fatalf("deleting the owner left alerts for %v", users)
+		}
+		if due, _ := stores.Alerts.GetWeeklySummaryDue(t.Context(), time.Now()); len(due) != 0 {
+			t.Fatalf("deleting the owner left preferences %+v", due)
+		}
+	})
+}
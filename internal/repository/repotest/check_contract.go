@@ -0,0 +1,95 @@
+package repotest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RunCheckStoreContract checks the behaviour services rely on from the check store
+func RunCheckStoreContract(t *testing.T, newStores StoresFactory) {
+	t.Run("history is newest first and limited", func(t *testing.T) {
+		stores := newStores(t)
+		link := mustCreateLink(t, stores, "history@example.com")
+		now := time.Now().UTC().Truncate(time.Second)
+		for i := range 3 {
+			check := &models.LinkCheckHistory{LinkID: link.ID, StatusCode: 200 + i, IsHealthy: true, CheckedAt: now.Add(time.Duration(i) * time.Minute)}
+			mustSucceed(t, "Create", stores.Checks.Create(t.Context(), check))
+		}
+
+		history, err := stores.Checks.GetByLinkID(t.Context(), link.ID, 2)
+		if err != nil {
+			t.Fatalf("GetByLinkID: %v", err)
+		}
+		if len(history) != 2 || history[0].StatusCode != 202 || history[1].StatusCode != 201 {
+			t.Fatalf("GetByLinkID = %+v, want the two newest checks, newest first", history)
+		}
+		if err := stores.Checks.Create(t.Context(), &models.LinkCheckHistory{LinkID: uuid.New(), StatusCode: 200}); !errors.Is(err, gorm.ErrForeignKeyViolated) {
+			t.Fatalf("Create for a missing link error = %v, want ErrForeignKeyViolated", err)
+		}
+	})
+
+	t.Run("rules for a link include the user's global rules", func(t *testing.T) {
+		stores := newStores(t)
+		link := mustCreateLink(t, stores, "rules@example.com")
+		other := mustCreateLinkFor(t, stores, &models.Link{UserID: link.UserID, OriginalURL: "https://example.com/other"})
+		now := time.Now().UTC().Truncate(time.Second)
+		global := &models.ContentRule{UserID: link.UserID, Name: "global", Pattern: "out of stock", CreatedAt: now.Add(-2 * time.Minute)}
+		scoped := &models.ContentRule{UserID: link.UserID, LinkID: &link.ID, Name: "scoped", Pattern: "sold out", CreatedAt: now.Add(-time.Minute)}
+		elsewhere := &models.ContentRule{UserID: link.UserID, LinkID: &other.ID, Name: "elsewhere", Pattern: "gone", CreatedAt: now}
+		for _, rule := range []*models.ContentRule{global, scoped, elsewhere} {
+			mustSucceed(t, "CreateRule", stores.Checks.CreateRule(t.Context(), rule))
+		}
+
+		rules, err := stores.Checks.GetRulesForLink(t.Context(), link.UserID, link.ID)
+		if err != nil {
+			t.Fatalf("GetRulesForLink: %v", err)
+		}
+		if len(rules) != 2 || rules[0].ID != global.ID || rules[1].ID != scoped.ID {
+			t.Fatalf("GetRulesForLink = %+v, want the global then the scoped rule", rules)
+		}
+		if rules, _ := stores.Checks.GetRulesByUserID(t.Context(), link.UserID); len(rules) != 3 {
+			t.Fatalf("GetRulesByUserID returned %d rules, want 3", len(rules))
+		}
+	})
+
+	t.Run("rules are deleted only by their owner", func(t *testing.T) {
+		stores := newStores(t)
+		link := mustCreateLink(t, stores, "owner@example.com")
+		rule := &models.ContentRule{UserID: link.UserID, Name: "rule", Pattern: "x"}
+		mustSucceed(t, "CreateRule", stores.Checks.CreateRule(t.Context(), rule))
+
+		if deleted, err := stores.Checks.DeleteRule(t.Context(), uuid.New(), rule.ID); err != nil || deleted {
+			t.Fatalf("DeleteRule by another user = %v, %v; want false", deleted, err)
+		}
+		if deleted, err := stores.Checks.DeleteRule(t.Context(), link.UserID, rule.ID); err != nil || !deleted {
+			t.Fatalf("DeleteRule by the owner = %v, %v; want true", deleted, err)
+		}
+		if deleted, _ := stores.Checks.DeleteRule(t.Context(), link.UserID, rule.ID); deleted {
+			t.Fatal("DeleteRule reported deleting a rule twice")
+		}
+	})
+
+	t.Run("rules go with their link and their owner", func(t *testing.T) {
+		stores := newStores(t)
+		link := mustCreateLink(t, stores, "rules-cascade@example.com")
+		global := &models.ContentRule{UserID: link.UserID, Name: "global", Pattern: "out of stock"}
+		scoped := &models.ContentRule{UserID: link.UserID, LinkID: &link.ID, Name: "scoped", Pattern: "sold out"}
+		for _, rule := range []*models.ContentRule{global, scoped} {
+			mustSucceed(t, "CreateRule", stores.Checks.CreateRule(t.Context(), rule))
+		}
+
+		mustSucceed(t, "Delete link", stores.Links.Delete(t.Context(), link.ID))
+		if rules, _ := stores.Checks.GetRulesByUserID(t.Context(), link.UserID); len(rules) != 1 || rules[0].ID != global.ID {
+			t.Fatalf("rules after deleting the link = %+v, want only the global rule", rules)
+		}
+		mustSucceed(t, "Delete user", stores.Users.Delete(t.Context(), link.UserID))
+		if rules, _ := stores.Checks.GetRulesByUserID(t.Context(), link.UserID); len(rules) != 0 {
+			t.Fatalf("deleting the owner left %d rules", len(rules))
+		}
+	})
+}
@@ -0,0 +1,285 @@
+package repotest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/1shoukr/linkvault/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RunLinkStoreContract checks the behaviour services rely on from the link store
+func RunLinkStoreContract(t *testing.T, newStores StoresFactory) {
+	t.Run("create fills ID, timestamps and defaults", func(t *testing.T) {
+		stores := newStores(t)
+		user := newUser("links@example.com")
+		mustCreate(t, stores, user)
+		link := &models.Link{UserID: user.ID, OriginalURL: "https://example.com/a"}
+		if err := stores.Links.Create(t.Context(), link); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		if link.ID == uuid.Nil || link.CreatedAt.IsZero() || link.UpdatedAt.IsZero() {
+			t.Fatalf("Create left the ID or timestamps unset: %+v", link)
+		}
+		got, err := stores.Links.GetByID(t.Context(), link.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Status != models.LinkStatusActive || !got.IsHealthy || got.ClickCount != 0 {
+			t.Fatalf("stored link = %+v, want an active, healthy link with no clicks", got)
+		}
+		if got.User.Email != user.Email {
+			t.Fatalf("GetByID owner = %q, want %q", got.User.Email, user.Email)
+		}
+	})
+
+	t.Run("links belong to existing users", func(t *testing.T) {
+		stores := newStores(t)
+		err := stores.Links.Create(t.Context(), &models.Link{UserID: uuid.New(), OriginalURL: "https://example.com"})
+		if !errors.Is(err, gorm.ErrForeignKeyViolated) {
+			t.Fatalf("Create for a missing user error = %v, want ErrForeignKeyViolated", err)
+		}
+		if _, err := stores.Links.GetByID(t.Context(), uuid.New()); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("GetByID error = %v, want ErrRecordNotFound", err)
+		}
+	})
+
+	t.Run("update leaves click count and health alone", func(t *testing.T) {
+		stores := newStores(t)
+		link := mustCreateLink(t, stores, "update-link@example.com")
+		if err := stores.Links.RecordClick(t.Context(), &models.Click{LinkID: link.ID}, false); err != nil {
+			t.Fatalf("RecordClick: %v", err)
+		}
+
+		// link is now stale: it still has no clicks and its health is about to be overwritten
+		title := "Renamed"
+		link.Title = &title
+		link.IsHealthy = false
+		if err := stores.Links.Update(t.Context(), link); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		got, err := stores.Links.GetByID(t.Context(), link.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Title == nil || *got.Title != title {
+			t.Fatalf("Update did not store the title: %v", got.Title)
+		}
+		if got.ClickCount != 1 || !got.IsHealthy {
+			t.Fatalf("Update overwrote click_count = %d, is_healthy = %v; want 1, true", got.ClickCount, got.IsHealthy)
+		}
+	})
+
+	t.Run("claim click stops at the cap", func(t *testing.T) {
+		stores := newStores(t)
+		user := newUser("capped@example.com")
+		mustCreate(t, stores, user)
+		maxClicks := 2
+		capped := &models.Link{UserID: user.ID, OriginalURL: "https://example.com/capped", MaxClicks: &maxClicks}
+		uncapped := &models.Link{UserID: user.ID, OriginalURL: "https://example.com/uncapped"}
+		for _, link := range []*models.Link{capped, uncapped} {
+			if err := stores.Links.Create(t.Context(), link); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+		}
+
+		for i, want := range []bool{true, true, false} {
+			claimed, err := stores.Links.ClaimClick(t.Context(), capped.ID)
+			if err != nil {
+				t.Fatalf("ClaimClick: %v", err)
+			}
+			if claimed != want {
+				t.Fatalf("ClaimClick #%d = %v, want %v", i+1, claimed, want)
+			}
+			if claimed {
+				if err := stores.Links.RecordClick(t.Context(), &models.Click{LinkID: capped.ID}, true); err != nil {
+					t.Fatalf("RecordClick: %v", err)
+				}
+			}
+		}
+		if got, _ := stores.Links.GetByID(t.Context(), capped.ID); got.ClickCount != maxClicks {
+			t.Fatalf("capped click_count = %d, want %d", got.ClickCount, maxClicks)
+		}
+		if claimed, _ := stores.Links.ClaimClick(t.Context(), uncapped.ID); claimed {
+			t.Fatal("ClaimClick counted a click on an uncapped link")
+		}
+		if count, _ := stores.Links.CountClicksSince(t.Context(), user.ID, time.Now().Add(-time.Hour)); count != 2 {
+			t.Fatalf("CountClicksSince = %d, want 2", count)
+		}
+	})
+
+	t.Run("claim due for check leases links", func(t *testing.T) {
+		stores := newStores(t)
+		user := newUser("due@example.com")
+		mustCreate(t, stores, user)
+		now := time.Now().UTC().Truncate(time.Second)
+		past, future := now.Add(-time.Minute), now.Add(time.Hour)
+		never := mustCreateLinkFor(t, stores, &models.Link{UserID: user.ID, OriginalURL: "https://example.com/never"})
+		overdue := mustCreateLinkFor(t, stores, &models.Link{UserID: user.ID, OriginalURL: "https://example.com/overdue", NextCheckAt: &past})
+		mustCreateLinkFor(t, stores, &models.Link{UserID: user.ID, OriginalURL: "https://example.com/later", NextCheckAt: &future})
+		mustCreateLinkFor(t, stores, &models.Link{UserID: user.ID, OriginalURL: "https://example.com/expired", Status: models.LinkStatusExpired})
+		mustCreateLinkFor(t, stores, &models.Link{UserID: user.ID, OriginalURL: "https://example.com/archived", ArchivedAt: &past})
+
+		claimed, err := stores.Links.ClaimDueForCheck(t.Context(), now, 5*time.Minute, 1)
+		if err != nil {
+			t.Fatalf("ClaimDueForCheck: %v", err)
+		}
+		if len(claimed) != 1 || claimed[0].ID != never.ID {
+			t.Fatalf("first claim = %v, want only the never-checked link", linkIDs(claimed))
+		}
+		if claimed[0].User.Email != user.Email {
+			t.Fatalf("claimed link owner = %q, want %q", claimed[0].User.Email, user.Email)
+		}
+
+		claimed, err = stores.Links.ClaimDueForCheck(t.Context(), now, 5*time.Minute, 10)
+		if err != nil {
+			t.Fatalf("ClaimDueForCheck: %v", err)
+		}
+		if len(claimed) != 1 || claimed[0].ID != overdue.ID {
+			t.Fatalf("second claim = %v, want only the overdue link", linkIDs(claimed))
+		}
+
+		if claimed, _ := stores.Links.ClaimDueForCheck(t.Context(), now, 5*time.Minute, 10); len(claimed) != 0 {
+			t.Fatalf("claim during the lease = %v, want none", linkIDs(claimed))
+		}
+		if claimed, _ := stores.Links.ClaimDueForCheck(t.Context(), now.Add(5*time.Minute), time.Minute, 10); len(claimed) != 2 {
+			t.Fatalf("claim after the lease = %v, want both leased links", linkIDs(claimed))
+		}
+	})
+
+	t.Run("routing rules are replaced atomically", func(t *testing.T) {
+		stores := newStores(t)
+		link := mustCreateLink(t, stores, "routing@example.com")
+		if err := stores.Links.ReplaceGeoRules(t.Context(), link.ID, []models.LinkGeoRule{
+			{Country: "DE", DestinationURL: "https://example.de"},
+		}); err != nil {
+			t.Fatalf("ReplaceGeoRules: %v", err)
+		}
+		if err := stores.Links.ReplaceVariants(t.Context(), link.ID, []models.LinkVariant{
+			{Label: "A", DestinationURL: "https://example.com/a"},
+		}); err != nil {
+			t.Fatalf("ReplaceVariants: %v", err)
+		}
+
+		err := stores.Links.ReplaceGeoRules(t.Context(), link.ID, []models.LinkGeoRule{
+			{Country: "FR", DestinationURL: "https://example.fr"},
+			{Country: "FR", DestinationURL: "https://example.fr/again"},
+		})
+		if !errors.Is(err, gorm.ErrDuplicatedKey) {
+			t.Fatalf("ReplaceGeoRules with a repeated country error = %v, want ErrDuplicatedKey", err)
+		}
+		err = stores.Links.ReplaceGeoRules(t.Context(), uuid.New(), []models.LinkGeoRule{{Country: "FR", DestinationURL: "https://example.fr"}})
+		if !errors.Is(err, gorm.ErrForeignKeyViolated) {
+			t.Fatalf("ReplaceGeoRules for a missing link error = %v, want ErrForeignKeyViolated", err)
+		}
+
+		got, err := stores.Links.GetByID(t.Context(), link.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if len(got.GeoRules) != 1 || got.GeoRules[0].Country != "DE" {
+			t.Fatalf("geo rules after a failed replace = %+v, want the original DE rule", got.GeoRules)
+		}
+		if len(got.Variants) != 1 || got.Variants[0].Weight != 1 {
+			t.Fatalf("variants = %+v, want one variant with the default weight", got.Variants)
+		}
+	})
+
+	t.Run("lifecycle moves links through their statuses", func(t *testing.T) {
+		stores := newStores(t)
+		user := newUser("lifecycle@example.com")
+		mustCreate(t, stores, user)
+		now := time.Now().UTC().Truncate(time.Second)
+		started, ended := now.Add(-time.Hour), now.Add(-time.Minute)
+		scheduled := mustCreateLinkFor(t, stores, &models.Link{UserID: user.ID, OriginalURL: "https://example.com/s", Status: models.LinkStatusScheduled, StartsAt: &started})
+		expiring := mustCreateLinkFor(t, stores, &models.Link{UserID: user.ID, OriginalURL: "https://example.com/e", ExpiresAt: &ended})
+
+		if n, err := stores.Links.ActivateScheduled(t.Context(), now); err != nil || n != 1 {
+			t.Fatalf("ActivateScheduled = %d, %v; want 1", n, err)
+		}
+		if n, err := stores.Links.ExpireDue(t.Context(), now); err != nil || n != 1 {
+			t.Fatalf("ExpireDue = %d, %v; want 1", n, err)
+		}
+		if n, err := stores.Links.ArchiveExpired(t.Context(), now, now); err != nil || n != 1 {
+			t.Fatalf("ArchiveExpired = %d, %v; want 1", n, err)
+		}
+
+		if got, _ := stores.Links.GetByID(t.Context(), scheduled.ID); got.Status != models.LinkStatusActive {
+			t.Fatalf("scheduled link status = %q, want active", got.Status)
+		}
+		got, _ := stores.Links.GetByID(t.Context(), expiring.ID)
+		if got.Status != models.LinkStatusExpired || got.ArchivedAt == nil {
+			t.Fatalf("expiring link = %q archived at %v, want expired and archived", got.Status, got.ArchivedAt)
+		}
+		if counts, _ := stores.Links.GetHealthCounts(t.Context(), user.ID); counts.Total != 1 {
+			t.Fatalf("GetHealthCounts total = %d, want 1 unarchived link", counts.Total)
+		}
+	})
+
+	t.Run("delete cascades to owned rows", func(t *testing.T) {
+		stores := newStores(t)
+		link := mustCreateLink(t, stores, "delete-link@example.com")
+		now := time.Now().UTC().Truncate(time.Second)
+		mustSucceed(t, "RecordClick", stores.Links.RecordClick(t.Context(), &models.Click{LinkID: link.ID}, false))
+		mustSucceed(t, "Create check", stores.Checks.Create(t.Context(), &models.LinkCheckHistory{LinkID: link.ID, StatusCode: 500}))
+		mustSucceed(t, "CreateRule", stores.Checks.CreateRule(t.Context(), &models.ContentRule{UserID: link.UserID, LinkID: &link.ID, Name: "sold out", Pattern: "sold out"}))
+		mustSucceed(t, "Create alert", stores.Alerts.Create(t.Context(), &models.Alert{UserID: link.UserID, LinkID: link.ID, Kind: models.AlertKindDown, Message: "down"}))
+		mustSucceed(t, "OpenIncident", stores.Uptime.OpenIncident(t.Context(), &models.LinkIncident{LinkID: link.ID, UserID: link.UserID, StartedAt: now}))
+
+		mustSucceed(t, "Delete", stores.Links.Delete(t.Context(), link.ID))
+		if _, err := stores.Links.GetByID(t.Context(), link.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("GetByID after delete error = %v, want ErrRecordNotFound", err)
+		}
+		if history, _ := stores.Checks.GetByLinkID(t.Context(), link.ID, 10); len(history) != 0 {
+			t.Fatalf("delete left %d checks", len(history))
+		}
+		if rules, _ := stores.Checks.GetRulesByUserID(t.Context(), link.UserID); len(rules) != 0 {
+			t.Fatalf("delete left %d content rules", len(rules))
+		}
+		if alerts, _ := stores.Alerts.GetPending(t.Context(), link.UserID); len(alerts) != 0 {
+			t.Fatalf("delete left %d alerts", len(alerts))
+		}
+		scope := repository.UptimeScope{UserID: link.UserID}
+		if incidents, _ := stores.Uptime.GetIncidents(t.Context(), scope, now.Add(-time.Hour), now.Add(time.Hour)); len(incidents) != 0 {
+			t.Fatalf("delete left %d incidents", len(incidents))
+		}
+		if count, _ := stores.Links.CountClicksSince(t.Context(), link.UserID, now.Add(-time.Hour)); count != 0 {
+			t.Fatalf("delete left %d clicks", count)
+		}
+	})
+}
+
+// mustCreateLink creates a user with the given email and a link they own
+func mustCreateLink(t *testing.T, stores Stores, email string) *models.Link {
+	t.Helper()
+	user := newUser(email)
+	mustCreate(t, stores, user)
+	return mustCreateLinkFor(t, stores, &models.Link{UserID: user.ID, OriginalURL: "https://example.com/" + email})
+}
+
+func mustCreateLinkFor(t *testing.T, stores Stores, link *models.Link) *models.Link {
+	t.Helper()
+	if err := stores.Links.Create(t.Context(), link); err != nil {
+		t.Fatalf("Create(%s): %v", link.OriginalURL, err)
+	}
+	return link
+}
+
+func mustSucceed(t *testing.T, op string, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s: %v", op, err)
+	}
+}
+
+func linkIDs(links []models.Link) []uuid.UUID {
+	ids := make([]uuid.UUID, len(links))
+	for i := range links {
+		ids[i] = links[i].ID
+	}
+	return ids
+}
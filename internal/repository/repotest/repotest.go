@@ -0,0 +1,108 @@
+// Package repotest is the contract every repository store implementation must satisfy. The
+// same suites run against the in-memory stores and, when TEST_DATABASE_URL points at a
+// disposable Postgres database, against the gorm stores:
+//
+//	func TestStores(t *testing.T) {
+//		t.Run("memory", func(t *testing.T) { repotest.RunLinkStoreContract(t, repotest.MemoryStores) })
+//		t.Run("postgres", func(t *testing.T) { repotest.RunLinkStoreContract(t, repotest.PostgresStores) })
+//	}
+package repotest
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/1shoukr/linkvault/internal/migrations"
+	"github.com/1shoukr/linkvault/internal/repository"
+	"github.com/1shoukr/linkvault/internal/repository/memory"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Stores are the stores for every aggregate, sharing one underlying database
+type Stores struct {
+	Users    repository.UserStore
+	Auth     repository.AuthStore
+	Links    repository.LinkStore
+	Checks   repository.CheckStore
+	Alerts   repository.AlertStore
+	Webhooks repository.WebhookStore
+	Uptime   repository.UptimeStore
+}
+
+// StoresFactory returns stores over an empty database; it is called once per contract case
+type StoresFactory func(t *testing.T) Stores
+
+// MemoryStores builds the in-memory stores on a fresh database
+func MemoryStores(t *testing.T) Stores {
+	db := memory.NewDB()
+	return Stores{
+		Users:    memory.NewUserRepository(db),
+		Auth:     memory.NewAuthRepository(db),
+		Links:    memory.NewLinkRepository(db),
+		Checks:   memory.NewCheckRepository(db),
+		Alerts:   memory.NewAlertRepository(db),
+		Webhooks: memory.NewWebhookRepository(db),
+		Uptime:   memory.NewUptimeRepository(db),
+	}
+}
+
+// PostgresStores builds the gorm stores on the database at TEST_DATABASE_URL, emptied of users
+// (and, by cascade, everything they own). The test is skipped when the variable is unset.
+func PostgresStores(t *testing.T) Stores {
+	return GormStores(t, OpenPostgres(t))
+}
+
+// GormStores builds the gorm stores on an already migrated db, emptied of users the same way
+func GormStores(t *testing.T, db *gorm.DB) Stores {
+	if err := db.Exec("TRUNCATE users CASCADE").Error; err != nil {
+		t.Fatalf("failed to empty users: %v", err)
+	}
+	return Stores{
+		Users:    repository.NewUserRepository(db),
+		Auth:     repository.NewAuthRepository(db),
+		Links:    repository.NewLinkRepository(db, nil),
+		Checks:   repository.NewCheckRepository(db),
+		Alerts:   repository.NewAlertRepository(db),
+		Webhooks: repository.NewWebhookRepository(db),
+		Uptime:   repository.NewUptimeRepository(db, nil),
+	}
+}
+
+var (
+	postgresOnce sync.Once
+	postgresDB   *gorm.DB
+	postgresErr  error
+)
+
+// OpenPostgres connects to TEST_DATABASE_URL and applies every migration, once per test binary.
+// The database is truncated by the factories, so it must be dedicated to tests.
+func OpenPostgres(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	postgresOnce.Do(func() {
+		postgresDB, postgresErr = gorm.Open(postgres.Open(dsn), &gorm.Config{
+			Logger:         logger.Default.LogMode(logger.Silent),
+			TranslateError: true,
+		})
+		if postgresErr != nil {
+			return
+		}
+		var migrator *migrations.Migrator
+		if migrator, postgresErr = migrations.New(postgresDB); postgresErr != nil {
+			return
+		}
+		_, postgresErr = migrator.Up(context.Background())
+	})
+	if postgresErr != nil {
+		t.Fatalf("failed to prepare test database: %v", postgresErr)
+	}
+	return postgresDB
+}
@@ -0,0 +1,138 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/1shoukr/linkvault/internal/repository"
+	"github.com/google/uuid"
+)
+
+// RunUptimeStoreContract checks the behaviour services rely on from the uptime store
+func RunUptimeStoreContract(t *testing.T, newStores StoresFactory) {
+	t.Run("one incident is open per link", func(t *testing.T) {
+		stores := newStores(t)
+		link := mustCreateLink(t, stores, "incidents@example.com")
+		now := time.Now().UTC().Truncate(time.Second)
+		first := &models.LinkIncident{LinkID: link.ID, UserID: link.UserID, StartedAt: now.Add(-time.Hour)}
+		mustSucceed(t, "OpenIncident", stores.Uptime.OpenIncident(t.Context(), first))
+		mustSucceed(t, "OpenIncident", stores.Uptime.OpenIncident(t.Context(), &models.LinkIncident{LinkID: link.ID, UserID: link.UserID, StartedAt: now}))
+
+		scope := repository.UptimeScope{UserID: link.UserID, LinkID: &link.ID}
+		incidents, err := stores.Uptime.GetIncidents(t.Context(), scope, now.Add(-2*time.Hour), now.Add(time.Hour))
+		if err != nil {
+			t.Fatalf("GetIncidents: %v", err)
+		}
+		if len(incidents) != 1 || incidents[0].ID != first.ID || incidents[0].EndedAt != nil {
+			t.Fatalf("GetIncidents = %+v, want only the first, still open incident", incidents)
+		}
+
+		mustSucceed(t, "CloseIncidents", stores.Uptime.CloseIncidents(t.Context(), link.ID, now.Add(-30*time.Minute)))
+		if incidents, _ := stores.Uptime.GetIncidents(t.Context(), scope, now.Add(-20*time.Minute), now); len(incidents) != 0 {
+			t.Fatalf("GetIncidents after the incident ended = %+v, want none", incidents)
+		}
+		if incidents, _ := stores.Uptime.GetIncidents(t.Context(), repository.UptimeScope{UserID: link.UserID}, now.Add(-2*time.Hour), now); len(incidents) != 1 {
+			t.Fatalf("GetIncidents for the user = %d incidents, want 1", len(incidents))
+		}
+	})
+
+	t.Run("samples skip rate-limited checks", func(t *testing.T) {
+		stores := newStores(t)
+		link := mustCreateLink(t, stores, "samples@example.com")
+		now := time.Now().UTC().Truncate(time.Second)
+		responseTime := 120
+		checks := []*models.LinkCheckHistory{
+			{LinkID: link.ID, StatusCode: 200, IsHealthy: true, ResponseTime: &responseTime, CheckedAt: now.Add(-2 * time.Hour)},
+			{LinkID: link.ID, StatusCode: 500, CheckedAt: now.Add(-time.Hour)},
+			{LinkID: link.ID, StatusCode: 429, RateLimited: true, CheckedAt: now.Add(-time.Hour)},
+			{LinkID: link.ID, StatusCode: 200, IsHealthy: true, CheckedAt: now},
+		}
+		for _, check := range checks {
+			mustSucceed(t, "Create check", stores.Checks.Create(t.Context(), check))
+		}
+
+		scope := repository.UptimeScope{UserID: link.UserID}
+		samples, err := stores.Uptime.GetSamples(t.Context(), scope, now.Add(-3*time.Hour), now)
+		if err != nil {
+			t.Fatalf("GetSamples: %v", err)
+		}
+		if len(samples) != 2 {
+			t.Fatalf("GetSamples = %+v, want the two unthrottled checks before now", samples)
+		}
+		for _, sample := range samples {
+			if sample.UserID != link.UserID || sample.LinkID != link.ID {
+				t.Fatalf("sample %+v is not attributed to the link and its owner", sample)
+			}
+		}
+		if all, _ := stores.Uptime.GetAllSamples(t.Context(), now.Add(-3*time.Hour), now.Add(time.Second)); len(all) != 3 {
+			t.Fatalf("GetAllSamples = %d samples, want 3", len(all))
+		}
+		earliest, err := stores.Uptime.GetEarliestCheckTime(t.Context())
+		if err != nil || earliest == nil || !earliest.Equal(now.Add(-2*time.Hour)) {
+			t.Fatalf("GetEarliestCheckTime = %v, %v; want %v", earliest, err, now.Add(-2*time.Hour))
+		}
+	})
+
+	t.Run("rollups are upserted per link and day", func(t *testing.T) {
+		stores := newStores(t)
+		link := mustCreateLink(t, stores, "rollups@example.com")
+		day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+		if latest, err := stores.Uptime.GetLatestRollupDay(t.Context()); err != nil || latest != nil {
+			t.Fatalf("GetLatestRollupDay with no rollups = %v, %v; want nil", latest, err)
+		}
+		mustSucceed(t, "UpsertRollups", stores.Uptime.UpsertRollups(t.Context(), []models.LinkCheckRollup{
+			{LinkID: link.ID, UserID: link.UserID, Day: day, Checks: 10, HealthyChecks: 9},
+			{LinkID: link.ID, UserID: link.UserID, Day: day.AddDate(0, 0, 1), Checks: 4, HealthyChecks: 4},
+		}))
+		mustSucceed(t, "UpsertRollups", stores.Uptime.UpsertRollups(t.Context(), []models.LinkCheckRollup{
+			{LinkID: link.ID, UserID: link.UserID, Day: day, Checks: 12, HealthyChecks: 11},
+		}))
+
+		scope := repository.UptimeScope{UserID: link.UserID, LinkID: &link.ID}
+		rollups, err := stores.Uptime.GetRollups(t.Context(), scope, day, day.AddDate(0, 0, 1))
+		if err != nil {
+			t.Fatalf("GetRollups: %v", err)
+		}
+		if len(rollups) != 1 || rollups[0].Checks != 12 || rollups[0].HealthyChecks != 11 {
+			t.Fatalf("GetRollups = %+v, want the re-upserted first day only", rollups)
+		}
+		latest, err := stores.Uptime.GetLatestRollupDay(t.Context())
+		if err != nil || latest == nil || !latest.Equal(day.AddDate(0, 0, 1)) {
+			t.Fatalf("GetLatestRollupDay = %v, %v; want %v", latest, err, day.AddDate(0, 0, 1))
+		}
+	})
+
+	t.Run("incidents and rollups go with their link and owner", func(t *testing.T) {
+		stores := newStores(t)
+		link := mustCreateLink(t, stores, "uptime-cascade@example.com")
+		other := mustCreateLinkFor(t, stores, &models.Link{UserID: link.UserID, OriginalURL: "https://example.com/other"})
+		now := time.Now().UTC().Truncate(time.Second)
+		day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		for _, linkID := range []uuid.UUID{link.ID, other.ID} {
+			mustSucceed(t, "OpenIncident", stores.Uptime.OpenIncident(t.Context(), &models.LinkIncident{LinkID: linkID, UserID: link.UserID, StartedAt: now}))
+		}
+		mustSucceed(t, "UpsertRollups", stores.Uptime.UpsertRollups(t.Context(), []models.LinkCheckRollup{
+			{LinkID: link.ID, UserID: link.UserID, Day: day, Checks: 1, HealthyChecks: 1},
+			{LinkID: other.ID, UserID: link.UserID, Day: day, Checks: 1, HealthyChecks: 0},
+		}))
+
+		scope := repository.UptimeScope{UserID: link.UserID}
+		mustSucceed(t, "Delete link", stores.Links.Delete(t.Context(), link.ID))
+		if incidents, _ := stores.Uptime.GetIncidents(t.Context(), scope, now.Add(-time.Hour), now.Add(time.Hour)); len(incidents) != 1 || incidents[0].LinkID != other.ID {
+			t.Fatalf("incidents after deleting the link = %+v, want only the other link's", incidents)
+		}
+		if rollups, _ := stores.Uptime.GetRollups(t.Context(), scope, day, day.AddDate(0, 0, 1)); len(rollups) != 1 || rollups[0].LinkID != other.ID {
+			t.Fatalf("rollups after deleting the link = %+v, want only the other link's", rollups)
+		}
+
+		mustSucceed(t, "Delete user", stores.Users.Delete(t.Context(), link.UserID))
+		if incidents, _ := stores.Uptime.GetIncidents(t.Context(), scope, now.Add(-time.Hour), now.Add(time.Hour)); len(incidents) != 0 {
+			t.Fatalf("deleting the owner left %d incidents", len(incidents))
+		}
+		if rollups, _ := stores.Uptime.GetRollups(t.Context(), scope, day, day.AddDate(0, 0, 1)); len(rollups) != 0 {
+			t.Fatalf("deleting the owner left %d rollups", len(rollups))
+		}
+	})
+}
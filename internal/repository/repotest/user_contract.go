@@ -0,0 +1,219 @@
+package repotest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/1shoukr/linkvault/pkg/merchant"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RunUserStoreContract checks the behaviour services rely on from the user and auth stores
+func RunUserStoreContract(t *testing.T, newStores StoresFactory) {
+	t.Run("create fills ID, timestamps and defaults", func(t *testing.T) {
+		stores := newStores(t)
+		user := newUser("create@example.com")
+		mustCreate(t, stores, user)
+
+		if user.ID == uuid.Nil {
+			t.Fatal("Create left the ID unset")
+		}
+		if user.CreatedAt.IsZero() || user.UpdatedAt.IsZero() {
+			t.Fatal("Create left the timestamps unset")
+		}
+		got, err := stores.Users.GetByID(t.Context(), user.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Email != user.Email || got.Plan != models.PlanFree || got.EmailVerified {
+			t.Fatalf("stored user = %+v, want email %q on the free plan, unverified", got, user.Email)
+		}
+	})
+
+	t.Run("missing users are ErrRecordNotFound", func(t *testing.T) {
+		stores := newStores(t)
+		if _, err := stores.Users.GetByID(t.Context(), uuid.New()); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("GetByID error = %v, want ErrRecordNotFound", err)
+		}
+		if _, err := stores.Users.GetByEmail(t.Context(), "nobody@example.com"); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("GetByEmail error = %v, want ErrRecordNotFound", err)
+		}
+		if _, err := stores.Auth.GetUserByID(t.Context(), uuid.New()); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("GetUserByID error = %v, want ErrRecordNotFound", err)
+		}
+		if _, err := stores.Auth.GetUserByEmail(t.Context(), "nobody@example.com"); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("GetUserByEmail error = %v, want ErrRecordNotFound", err)
+		}
+	})
+
+	t.Run("email is unique and matched exactly", func(t *testing.T) {
+		stores := newStores(t)
+		mustCreate(t, stores, newUser("taken@example.com"))
+
+		if err := stores.Users.Create(t.Context(), newUser("taken@example.com")); !errors.Is(err, gorm.ErrDuplicatedKey) {
+			t.Fatalf("Create with a taken email error = %v, want ErrDuplicatedKey", err)
+		}
+		if err := stores.Auth.CreateUser(t.Context(), newUser("taken@example.com")); !errors.Is(err, gorm.ErrDuplicatedKey) {
+			t.Fatalf("CreateUser with a taken email error = %v, want ErrDuplicatedKey", err)
+		}
+
+		other := newUser("other@example.com")
+		mustCreate(t, stores, other)
+		other.Email = "taken@example.com"
+		if err := stores.Users.Update(t.Context(), other); !errors.Is(err, gorm.ErrDuplicatedKey) {
+			t.Fatalf("Update to a taken email error = %v, want ErrDuplicatedKey", err)
+		}
+
+		if _, err := stores.Users.GetByEmail(t.Context(), "TAKEN@example.com"); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("GetByEmail with different case error = %v, want ErrRecordNotFound", err)
+		}
+	})
+
+	t.Run("update persists every column", func(t *testing.T) {
+		stores := newStores(t)
+		user := newUser("update@example.com")
+		mustCreate(t, stores, user)
+
+		name := "Renamed"
+		source := "newsletter"
+		user.Name = &name
+		user.Plan = models.PlanPro
+		user.DefaultUTM.Source = &source
+		if err := stores.Auth.UpdateUser(t.Context(), user); err != nil {
+			t.Fatalf("UpdateUser: %v", err)
+		}
+
+		got, err := stores.Users.GetByEmail(t.Context(), user.Email)
+		if err != nil {
+			t.Fatalf("GetByEmail: %v", err)
+		}
+		if got.Name == nil || *got.Name != name || !got.IsPro() || got.DefaultUTM.Source == nil || *got.DefaultUTM.Source != source {
+			t.Fatalf("updated user = %+v, want name %q, pro plan and utm source %q", got, name, source)
+		}
+	})
+
+	t.Run("returned users are copies", func(t *testing.T) {
+		stores := newStores(t)
+		user := newUser("copy@example.com")
+		mustCreate(t, stores, user)
+
+		got, err := stores.Users.GetByID(t.Context(), user.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		*got.Name = "Mutated"
+		got.Plan = models.PlanPro
+
+		again, err := stores.Users.GetByID(t.Context(), user.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if *again.Name == "Mutated" || again.Plan == models.PlanPro {
+			t.Fatal("mutating a returned user changed the stored row")
+		}
+	})
+
+	t.Run("auth and user stores share rows", func(t *testing.T) {
+		stores := newStores(t)
+		user := newUser("shared@example.com")
+		if err := stores.Auth.CreateUser(t.Context(), user); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		if _, err := stores.Users.GetByID(t.Context(), user.ID); err != nil {
+			t.Fatalf("user created through the auth store is not visible: %v", err)
+		}
+		all, err := stores.Users.GetAll(t.Context())
+		if err != nil {
+			t.Fatalf("GetAll: %v", err)
+		}
+		if len(all) != 1 || all[0].ID != user.ID {
+			t.Fatalf("GetAll = %d users, want only %s", len(all), user.ID)
+		}
+	})
+
+	t.Run("affiliate tags are replaced atomically", func(t *testing.T) {
+		stores := newStores(t)
+		user := newUser("tags@example.com")
+		mustCreate(t, stores, user)
+
+		err := stores.Users.ReplaceAffiliateTags(t.Context(), user.ID, []models.AffiliateTag{
+			{Network: merchant.Networks[0], Tag: "b-20"},
+			{Network: merchant.Networks[0], Tag: "a-20"},
+		})
+		if err != nil {
+			t.Fatalf("ReplaceAffiliateTags: %v", err)
+		}
+		tags, err := stores.Users.GetAffiliateTags(t.Context(), user.ID)
+		if err != nil {
+			t.Fatalf("GetAffiliateTags: %v", err)
+		}
+		if len(tags) != 2 || tags[0].Tag != "a-20" || tags[1].Tag != "b-20" || tags[0].UserID != user.ID {
+			t.Fatalf("tags = %+v, want a-20 then b-20 owned by the user", tags)
+		}
+
+		duplicate := []models.AffiliateTag{
+			{Network: merchant.Networks[0], Tag: "c-20"},
+			{Network: merchant.Networks[0], Tag: "c-20"},
+		}
+		if err := stores.Users.ReplaceAffiliateTags(t.Context(), user.ID, duplicate); !errors.Is(err, gorm.ErrDuplicatedKey) {
+			t.Fatalf("ReplaceAffiliateTags with duplicates error = %v, want ErrDuplicatedKey", err)
+		}
+		if tags, _ := stores.Users.GetAffiliateTags(t.Context(), user.ID); len(tags) != 2 {
+			t.Fatalf("a failed replace left %d tags, want the original 2", len(tags))
+		}
+
+		if err := stores.Users.ReplaceAffiliateTags(t.Context(), user.ID, nil); err != nil {
+			t.Fatalf("ReplaceAffiliateTags with no tags: %v", err)
+		}
+		if tags, _ := stores.Users.GetAffiliateTags(t.Context(), user.ID); len(tags) != 0 {
+			t.Fatalf("clearing tags left %d", len(tags))
+		}
+	})
+
+	t.Run("affiliate tags need an existing user", func(t *testing.T) {
+		stores := newStores(t)
+		err := stores.Users.ReplaceAffiliateTags(t.Context(), uuid.New(), []models.AffiliateTag{{Network: merchant.Networks[0], Tag: "x-20"}})
+		if !errors.Is(err, gorm.ErrForeignKeyViolated) {
+			t.Fatalf("ReplaceAffiliateTags for a missing user error = %v, want ErrForeignKeyViolated", err)
+		}
+	})
+
+	t.Run("delete cascades to owned rows", func(t *testing.T) {
+		stores := newStores(t)
+		user := newUser("delete@example.com")
+		mustCreate(t, stores, user)
+		if err := stores.Users.ReplaceAffiliateTags(t.Context(), user.ID, []models.AffiliateTag{{Network: merchant.Networks[0], Tag: "d-20"}}); err != nil {
+			t.Fatalf("ReplaceAffiliateTags: %v", err)
+		}
+
+		if err := stores.Users.Delete(t.Context(), user.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := stores.Users.GetByID(t.Context(), user.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("GetByID after delete error = %v, want ErrRecordNotFound", err)
+		}
+		if tags, _ := stores.Users.GetAffiliateTags(t.Context(), user.ID); len(tags) != 0 {
+			t.Fatalf("delete left %d affiliate tags", len(tags))
+		}
+		if err := stores.Users.Delete(t.Context(), user.ID); err != nil {
+			t.Fatalf("deleting a missing user: %v", err)
+		}
+
+		// The email is free again
+		mustCreate(t, stores, newUser("delete@example.com"))
+	})
+}
+
+func newUser(email string) *models.User {
+	name := "Test User"
+	return &models.User{Email: email, Name: &name}
+}
+
+func mustCreate(t *testing.T, stores Stores, user *models.User) {
+	t.Helper()
+	if err := stores.Users.Create(t.Context(), user); err != nil {
+		t.Fatalf("Create(%s): %v", user.Email, err)
+	}
+}
@@ -0,0 +1,129 @@
+package repotest
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RunWebhookStoreContract checks the behaviour services rely on from the webhook store
+func RunWebhookStoreContract(t *testing.T, newStores StoresFactory) {
+	t.Run("endpoints are scoped to their owner", func(t *testing.T) {
+		stores := newStores(t)
+		user := newUser("endpoints@example.com")
+		mustCreate(t, stores, user)
+		endpoint := mustCreateEndpoint(t, stores, user.ID)
+
+		if !endpoint.IsActive || endpoint.CreatedAt.IsZero() {
+			t.Fatalf("CreateEndpoint = %+v, want an active endpoint with timestamps", endpoint)
+		}
+		if _, err := stores.Webhooks.GetEndpoint(t.Context(), uuid.New(), endpoint.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("GetEndpoint by another user error = %v, want ErrRecordNotFound", err)
+		}
+
+		endpoint.IsActive = false
+		mustSucceed(t, "UpdateEndpoint", stores.Webhooks.UpdateEndpoint(t.Context(), endpoint))
+		if active, _ := stores.Webhooks.GetActiveEndpoints(t.Context(), user.ID); len(active) != 0 {
+			t.Fatalf("GetActiveEndpoints = %d endpoints, want none after disabling", len(active))
+		}
+		if all, _ := stores.Webhooks.GetEndpointsByUserID(t.Context(), user.ID); len(all) != 1 || all[0].IsActive {
+			t.Fatalf("GetEndpointsByUserID = %+v, want the disabled endpoint", all)
+		}
+
+		if deleted, _ := stores.Webhooks.DeleteEndpoint(t.Context(), uuid.New(), endpoint.ID); deleted {
+			t.Fatal("DeleteEndpoint removed another user's endpoint")
+		}
+		if deleted, err := stores.Webhooks.DeleteEndpoint(t.Context(), user.ID, endpoint.ID); err != nil || !deleted {
+			t.Fatalf("DeleteEndpoint = %v, %v; want true", deleted, err)
+		}
+	})
+
+	t.Run("due deliveries are claimed once", func(t *testing.T) {
+		stores := newStores(t)
+		user := newUser("deliveries@example.com")
+		mustCreate(t, stores, user)
+		endpoint := mustCreateEndpoint(t, stores, user.ID)
+		now := time.Now().UTC().Truncate(time.Second)
+		deliveries := []models.WebhookDelivery{
+			{EndpointID: endpoint.ID, Event: models.WebhookEventLinkDown, Payload: json.RawMessage(`{}`), NextAttemptAt: now.Add(-time.Minute)},
+			{EndpointID: endpoint.ID, Event: models.WebhookEventLinkRecovered, Payload: json.RawMessage(`{}`), NextAttemptAt: now.Add(time.Hour)},
+		}
+		mustSucceed(t, "CreateDeliveries", stores.Webhooks.CreateDeliveries(t.Context(), deliveries))
+		if deliveries[0].ID == uuid.Nil || deliveries[0].Status != models.WebhookDeliveryPending {
+			t.Fatalf("CreateDeliveries = %+v, want an ID and the pending status", deliveries[0])
+		}
+
+		claimed, err := stores.Webhooks.ClaimDueDeliveries(t.Context(), now, time.Minute, 10)
+		if err != nil {
+			t.Fatalf("ClaimDueDeliveries: %v", err)
+		}
+		if len(claimed) != 1 || claimed[0].ID != deliveries[0].ID || claimed[0].Endpoint.URL != endpoint.URL {
+			t.Fatalf("ClaimDueDeliveries = %+v, want the due delivery with its endpoint", claimed)
+		}
+		if again, _ := stores.Webhooks.ClaimDueDeliveries(t.Context(), now, time.Minute, 10); len(again) != 0 {
+			t.Fatalf("claim during the lease returned %d deliveries, want none", len(again))
+		}
+
+		delivered := claimed[0]
+		statusCode := 204
+		delivered.Status = models.WebhookDeliverySucceeded
+		delivered.Attempts = 1
+		delivered.LastStatusCode = &statusCode
+		delivered.DeliveredAt = &now
+		mustSucceed(t, "UpdateDelivery", stores.Webhooks.UpdateDelivery(t.Context(), &delivered))
+		got, err := stores.Webhooks.GetDelivery(t.Context(), endpoint.ID, delivered.ID)
+		if err != nil {
+			t.Fatalf("GetDelivery: %v", err)
+		}
+		if got.Status != models.WebhookDeliverySucceeded || got.Attempts != 1 || got.LastStatusCode == nil || *got.LastStatusCode != statusCode {
+			t.Fatalf("GetDelivery = %+v, want the recorded attempt", got)
+		}
+		if claimed, _ := stores.Webhooks.ClaimDueDeliveries(t.Context(), now.Add(2*time.Hour), time.Minute, 10); len(claimed) != 1 {
+			t.Fatalf("later claim returned %d deliveries, want only the pending one", len(claimed))
+		}
+		if history, _ := stores.Webhooks.GetDeliveries(t.Context(), endpoint.ID, 1); len(history) != 1 {
+			t.Fatalf("GetDeliveries with limit 1 returned %d deliveries", len(history))
+		}
+	})
+
+	t.Run("deliveries need an endpoint and go with it and its owner", func(t *testing.T) {
+		stores := newStores(t)
+		user := newUser("orphans@example.com")
+		mustCreate(t, stores, user)
+		endpoint := mustCreateEndpoint(t, stores, user.ID)
+
+		err := stores.Webhooks.CreateDeliveries(t.Context(), []models.WebhookDelivery{
+			{EndpointID: uuid.New(), Event: models.WebhookEventLinkDown, Payload: json.RawMessage(`{}`), NextAttemptAt: time.Now()},
+		})
+		if !errors.Is(err, gorm.ErrForeignKeyViolated) {
+			t.Fatalf("CreateDeliveries for a missing endpoint error = %v, want ErrForeignKeyViolated", err)
+		}
+
+		delivery := models.WebhookDelivery{EndpointID: endpoint.ID, Event: models.WebhookEventLinkDown, Payload: json.RawMessage(`{}`), NextAttemptAt: time.Now()}
+		mustSucceed(t, "CreateDeliveries", stores.Webhooks.CreateDeliveries(t.Context(), []models.WebhookDelivery{delivery}))
+		mustSucceed(t, "Delete", stores.Users.Delete(t.Context(), user.ID))
+		if endpoints, _ := stores.Webhooks.GetEndpointsByUserID(t.Context(), user.ID); len(endpoints) != 0 {
+			t.Fatalf("deleting the owner left %d endpoints", len(endpoints))
+		}
+		if history, _ := stores.Webhooks.GetDeliveries(t.Context(), endpoint.ID, 10); len(history) != 0 {
+			t.Fatalf("deleting the owner left %d deliveries", len(history))
+		}
+	})
+}
+
+func mustCreateEndpoint(t *testing.T, stores Stores, userID uuid.UUID) *models.WebhookEndpoint {
+	t.Helper()
+	endpoint := &models.WebhookEndpoint{
+		UserID: userID,
+		URL:    "https://hooks.example.com/" + userID.String(),
+		Events: []string{models.WebhookEventLinkDown},
+		Secret: "secret",
+	}
+	mustSucceed(t, "CreateEndpoint", stores.Webhooks.CreateEndpoint(t.Context(), endpoint))
+	return endpoint
+}
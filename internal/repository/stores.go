@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/google/uuid"
+)
+
+// The interfaces below describe each aggregate's persistence as the services use it. The gorm
+// repositories in this package implement them against Postgres; the memory package provides
+// in-memory implementations for tests. Lookups of missing rows return gorm.ErrRecordNotFound
+// and unique violations return gorm.ErrDuplicatedKey from every implementation.
+
+// UserStore persists users and their affiliate tags
+type UserStore interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	Create(ctx context.Context, user *models.User) error
+	Update(ctx context.Context, user *models.User) error
+	// Delete removes the user along with everything they own
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetAll(ctx context.Context) ([]models.User, error)
+	GetAffiliateTags(ctx context.Context, userID uuid.UUID) ([]models.AffiliateTag, error)
+	ReplaceAffiliateTags(ctx context.Context, userID uuid.UUID, tags []models.AffiliateTag) error
+}
+
+// AuthStore persists the user records used for signing in
+type AuthStore interface {
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	CreateUser(ctx context.Context, user *models.User) error
+	GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	UpdateUser(ctx context.Context, user *models.User) error
+}
+
+// LinkStore persists links, their routing rules, clicks and health
+type LinkStore interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Link, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]models.Link, error)
+	Create(ctx context.Context, link *models.Link) error
+	Update(ctx context.Context, link *models.Link) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ReplaceGeoRules(ctx context.Context, linkID uuid.UUID, rules []models.LinkGeoRule) error
+	ReplaceVariants(ctx context.Context, linkID uuid.UUID, variants []models.LinkVariant) error
+	ReplaceDeviceRules(ctx context.Context, linkID uuid.UUID, rules []models.LinkDeviceRule) error
+	ReplaceChannels(ctx context.Context, linkID uuid.UUID, channels []models.LinkChannel) error
+	GetVariantClickStats(ctx context.Context, linkID uuid.UUID) ([]VariantClickStats, error)
+	ActivateScheduled(ctx context.Context, now time.Time) (int64, error)
+	ExpireDue(ctx context.Context, now time.Time) (int64, error)
+	ArchiveExpired(ctx context.Context, cutoff, now time.Time) (int64, error)
+	// ClaimDueForCheck returns due links and pushes them lease into the future, so concurrent
+	// workers never claim the same link
+	ClaimDueForCheck(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.Link, error)
+	UpdateHealth(ctx context.Context, link *models.Link) error
+	Reschedule(ctx context.Context, id uuid.UUID, nextCheckAt time.Time) error
+	// ClaimClick counts a click against a capped link while it is below its cap, reporting
+	// whether it was counted
+	ClaimClick(ctx context.Context, id uuid.UUID) (bool, error)
+	// RecordClick stores a click, also counting it unless ClaimClick already has
+	RecordClick(ctx context.Context, click *models.Click, counted bool) error
+	GetHealthCounts(ctx context.Context, userID uuid.UUID) (LinkHealthCounts, error)
+	GetUnhealthy(ctx context.Context, userID uuid.UUID, limit int) ([]models.Link, error)
+	CountClicksSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error)
+}
+
+// CheckStore persists health check history and content rules
+type CheckStore interface {
+	Create(ctx context.Context, history *models.LinkCheckHistory) error
+	GetByLinkID(ctx context.Context, linkID uuid.UUID, limit int) ([]models.LinkCheckHistory, error)
+	GetRulesByUserID(ctx context.Context, userID uuid.UUID) ([]models.ContentRule, error)
+	GetRulesForLink(ctx context.Context, userID, linkID uuid.UUID) ([]models.ContentRule, error)
+	CreateRule(ctx context.Context, rule *models.ContentRule) error
+	DeleteRule(ctx context.Context, userID, id uuid.UUID) (bool, error)
+}
+
+// AlertStore persists notification preferences and pending alerts
+type AlertStore interface {
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*models.NotificationPreference, error)
+	SavePreferences(ctx context.Context, prefs *models.NotificationPreference) error
+	GetWeeklySummaryDue(ctx context.Context, before time.Time) ([]models.NotificationPreference, error)
+	MarkWeeklySummarySent(ctx context.Context, userID uuid.UUID, at time.Time) error
+	CountSince(ctx context.Context, userID uuid.UUID, kind string, since time.Time) (int64, error)
+	Create(ctx context.Context, alert *models.Alert) error
+	DeletePending(ctx context.Context, linkID uuid.UUID, kind string) (bool, error)
+	GetUsersWithPending(ctx context.Context) (map[uuid.UUID]time.Time, error)
+	GetPending(ctx context.Context, userID uuid.UUID) ([]models.Alert, error)
+	MarkDelivered(ctx context.Context, ids []uuid.UUID, at time.Time) error
+}
+
+// WebhookStore persists outbound webhook endpoints and their deliveries
+type WebhookStore interface {
+	GetEndpointsByUserID(ctx context.Context, userID uuid.UUID) ([]models.WebhookEndpoint, error)
+	GetActiveEndpoints(ctx context.Context, userID uuid.UUID) ([]models.WebhookEndpoint, error)
+	GetEndpoint(ctx context.Context, userID, id uuid.UUID) (*models.WebhookEndpoint, error)
+	CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error
+	UpdateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error
+	DeleteEndpoint(ctx context.Context, userID, id uuid.UUID) (bool, error)
+	CreateDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error
+	// ClaimDueDeliveries returns due deliveries and pushes them lease into the future, so
+	// concurrent workers never attempt the same delivery
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetDeliveries(ctx context.Context, endpointID uuid.UUID, limit int) ([]models.WebhookDelivery, error)
+	GetDelivery(ctx context.Context, endpointID, id uuid.UUID) (*models.WebhookDelivery, error)
+}
+
+// UptimeStore persists incidents and daily check rollups
+type UptimeStore interface {
+	OpenIncident(ctx context.Context, incident *models.LinkIncident) error
+	CloseIncidents(ctx context.Context, linkID uuid.UUID, at time.Time) error
+	GetIncidents(ctx context.Context, scope UptimeScope, from, to time.Time) ([]models.LinkIncident, error)
+	GetRollups(ctx context.Context, scope UptimeScope, fromDay, toDay time.Time) ([]models.LinkCheckRollup, error)
+	GetSamples(ctx context.Context, scope UptimeScope, from, to time.Time) ([]CheckSample, error)
+	GetAllSamples(ctx context.Context, from, to time.Time) ([]CheckSample, error)
+	GetLatestRollupDay(ctx context.Context) (*time.Time, error)
+	GetEarliestCheckTime(ctx context.Context) (*time.Time, error)
+	UpsertRollups(ctx context.Context, rollups []models.LinkCheckRollup) error
+}
+
+var (
+	_ UserStore    = (*UserRepository)(nil)
+	_ AuthStore    = (*AuthRepository)(nil)
+	_ LinkStore    = (*LinkRepository)(nil)
+	_ CheckStore   = (*CheckRepository)(nil)
+	_ AlertStore   = (*AlertRepository)(nil)
+	_ WebhookStore = (*WebhookRepository)(nil)
+	_ UptimeStore  = (*UptimeRepository)(nil)
+)
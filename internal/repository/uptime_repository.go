@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UptimeScope selects the links an uptime query covers: one link, or all of a user's links
+type UptimeScope struct {
+	UserID uuid.UUID
+	LinkID *uuid.UUID
+}
+
+// CheckSample is the slice of a health check that uptime reporting needs
+type CheckSample struct {
+	LinkID       uuid.UUID
+	UserID       uuid.UUID
+	IsHealthy    bool
+	ResponseTime *int
+}
+
+// UptimeRepository handles database operations for incidents and daily check rollups
+type UptimeRepository struct {
+	db *gorm.DB
+	// reads serves uptime reports and rollup sampling; it is the read replica when one is
+	// configured
+	reads *gorm.DB
+}
+
+// NewUptimeRepository creates a new uptime repository. Report queries go to replica, or to db
+// when replica is nil.
+func NewUptimeRepository(db, replica *gorm.DB) *UptimeRepository {
+	return &UptimeRepository{db: db, reads: orPrimary(replica, db)}
+}
+
+// OpenIncident starts an incident for a link unless one is already open
+func (r *UptimeRepository) OpenIncident(ctx context.Context, incident *models.LinkIncident) error {
+	var open int64
+	err := r.db.WithContext(ctx).Model(&models.LinkIncident{}).
+		Where("link_id = ? AND ended_at IS NULL", incident.LinkID).
+		Count(&open).Error
+	if err != nil || open > 0 {
+		return err
+	}
+	return r.db.WithContext(ctx).Create(incident).Error
+}
+
+// CloseIncidents ends a link's open incident
+func (r *UptimeRepository) CloseIncidents(ctx context.Context, linkID uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.LinkIncident{}).
+		Where("link_id = ? AND ended_at IS NULL", linkID).
+		Update("ended_at", at).Error
+}
+
+// GetIncidents retrieves incidents in scope that overlap [from, to), oldest first
+func (r *UptimeRepository) GetIncidents(ctx context.Context, scope UptimeScope, from, to time.Time) ([]models.LinkIncident, error) {
+	var incidents []models.LinkIncident
+	err := r.scoped(ctx, scope, "user_id", "link_id").
+		Where("started_at < ? AND (ended_at IS NULL OR ended_at > ?)", to, from).
+		Order("started_at").
+		Find(&incidents).Error
+	if err != nil {
+		return nil, err
+	}
+	return incidents, nil
+}
+
+// GetRollups retrieves daily rollups in scope for days in [fromDay, toDay)
+func (r *UptimeRepository) GetRollups(ctx context.Context, scope UptimeScope, fromDay, toDay time.Time) ([]models.LinkCheckRollup, error) {
+	var rollups []models.LinkCheckRollup
+	err := r.scoped(ctx, scope, "user_id", "link_id").
+		Where("day >= ? AND day < ?", fromDay, toDay).
+		Find(&rollups).Error
+	if err != nil {
+		return nil, err
+	}
+	return rollups, nil
+}
+
+// GetSamples retrieves checks in scope made in [from, to)
+func (r *UptimeRepository) GetSamples(ctx context.Context, scope UptimeScope, from, to time.Time) ([]CheckSample, error) {
+	var samples []CheckSample
+	err := r.scoped(ctx, scope, "links.user_id", "link_check_history.link_id").
+		Model(&models.LinkCheckHistory{}).
+		Select("link_check_history.link_id, links.user_id, link_check_history.is_healthy, link_check_history.response_time").
+		Joins("JOIN links ON links.id = link_check_history.link_id").
+		Where("link_check_history.checked_at >= ? AND link_check_history.checked_at < ?", from, to).
+		Where("NOT link_check_history.rate_limited").
+		Scan(&samples).Error
+	return samples, err
+}
+
+// GetAllSamples retrieves every check made in [from, to), for building rollups
+func (r *UptimeRepository) GetAllSamples(ctx context.Context, from, to time.Time) ([]CheckSample, error) {
+	var samples []CheckSample
+	err := r.reads.WithContext(ctx).Model(&models.LinkCheckHistory{}).
+		Select("link_check_history.link_id, links.user_id, link_check_history.is_healthy, link_check_history.response_time").
+		Joins("JOIN links ON links.id = link_check_history.link_id").
+		Where("link_check_history.checked_at >= ? AND link_check_history.checked_at < ?", from, to).
+		Where("NOT link_check_history.rate_limited").
+		Scan(&samples).Error
+	return samples, err
+}
+
+// GetLatestRollupDay returns the most recent day that has been rolled up, or nil if none has
+func (r *UptimeRepository) GetLatestRollupDay(ctx context.Context) (*time.Time, error) {
+	var rollup models.LinkCheckRollup
+	err := r.db.WithContext(ctx).Order("day DESC").First(&rollup).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rollup.Day, nil
+}
+
+// GetEarliestCheckTime returns when the oldest recorded check ran, or nil if there are none
+func (r *UptimeRepository) GetEarliestCheckTime(ctx context.Context) (*time.Time, error) {
+	var history models.LinkCheckHistory
+	err := r.db.WithContext(ctx).Order("checked_at").First(&history).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &history.CheckedAt, nil
+}
+
+// UpsertRollups stores rollups, replacing any existing rollup for the same link and day
+func (r *UptimeRepository) UpsertRollups(ctx context.Context, rollups []models.LinkCheckRollup) error {
+	if len(rollups) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "link_id"}, {Name: "day"}},
+		DoUpdates: clause.AssignmentColumns([]string{"checks", "healthy_checks", "response_times", "updated_at"}),
+	}).CreateInBatches(rollups, 500).Error
+}
+
+// scoped restricts a query to the scope's user and, if set, link
+func (r *UptimeRepository) scoped(ctx context.Context, scope UptimeScope, userColumn, linkColumn string) *gorm.DB {
+	query := r.reads.WithContext(ctx).Where(userColumn+" = ?", scope.UserID)
+	if scope.LinkID != nil {
+		query = query.Where(linkColumn+" = ?", *scope.LinkID)
+	}
+	return query
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"github.com/1shoukr/linkvault/internal/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -17,43 +18,68 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 }
 
 // GetByID retrieves a user by their ID
-func (r *UserRepository) GetByID(id uuid.UUID) (*models.User, error) {
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	var user models.User
-	if err := r.db.First(&user, "id = ?", id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
 // GetByEmail retrieves a user by their email
-func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	if err := r.db.First(&user, "email = ?", email).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&user, "email = ?", email).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
 // Create creates a new user
-func (r *UserRepository) Create(user *models.User) error {
-	return r.db.Create(user).Error
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Create(user).Error
 }
 
 // Update updates an existing user
-func (r *UserRepository) Update(user *models.User) error {
-	return r.db.Save(user).Error
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Save(user).Error
 }
 
 // Delete deletes a user by ID
-func (r *UserRepository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&models.User{}, "id = ?", id).Error
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.User{}, "id = ?", id).Error
 }
 
 // GetAll retrieves all users (for admin purposes, pagination can be added later)
-func (r *UserRepository) GetAll() ([]models.User, error) {
+func (r *UserRepository) GetAll(ctx context.Context) ([]models.User, error) {
 	var users []models.User
-	if err := r.db.Find(&users).Error; err != nil {
+	if err := r.db.WithContext(ctx).Find(&users).Error; err != nil {
 		return nil, err
 	}
 	return users, nil
 }
+
+// GetAffiliateTags retrieves the affiliate tags configured by a user
+func (r *UserRepository) GetAffiliateTags(ctx context.Context, userID uuid.UUID) ([]models.AffiliateTag, error) {
+	var tags []models.AffiliateTag
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("network, tag").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// ReplaceAffiliateTags atomically replaces all affiliate tags configured by a user
+func (r *UserRepository) ReplaceAffiliateTags(ctx context.Context, userID uuid.UUID, tags []models.AffiliateTag) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.AffiliateTag{}).Error; err != nil {
+			return err
+		}
+		if len(tags) == 0 {
+			return nil
+		}
+		for i := range tags {
+			tags[i].UserID = userID
+		}
+		return tx.Create(&tags).Error
+	})
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookRepository handles database operations for webhook endpoints and their delivery queue
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// GetEndpointsByUserID retrieves all webhook endpoints for a user
+func (r *WebhookRepository) GetEndpointsByUserID(ctx context.Context, userID uuid.UUID) ([]models.WebhookEndpoint, error) {
+	var endpoints []models.WebhookEndpoint
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at ASC").Find(&endpoints).Error
+	return endpoints, err
+}
+
+// GetActiveEndpoints retrieves a user's enabled webhook endpoints
+func (r *WebhookRepository) GetActiveEndpoints(ctx context.Context, userID uuid.UUID) ([]models.WebhookEndpoint, error) {
+	var endpoints []models.WebhookEndpoint
+	err := r.db.WithContext(ctx).Where("user_id = ? AND is_active = ?", userID, true).Find(&endpoints).Error
+	return endpoints, err
+}
+
+// GetEndpoint retrieves a user's webhook endpoint by ID
+func (r *WebhookRepository) GetEndpoint(ctx context.Context, userID, id uuid.UUID) (*models.WebhookEndpoint, error) {
+	var endpoint models.WebhookEndpoint
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&endpoint).Error; err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+// CreateEndpoint creates a new webhook endpoint
+func (r *WebhookRepository) CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	return r.db.WithContext(ctx).Create(endpoint).Error
+}
+
+// UpdateEndpoint saves changes to a webhook endpoint
+func (r *WebhookRepository) UpdateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	return r.db.WithContext(ctx).Save(endpoint).Error
+}
+
+// DeleteEndpoint deletes a user's webhook endpoint, reporting whether it existed
+func (r *WebhookRepository) DeleteEndpoint(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&models.WebhookEndpoint{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// CreateDeliveries queues deliveries in a single insert
+func (r *WebhookRepository) CreateDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&deliveries).Error
+}
+
+// ClaimDueDeliveries claims pending deliveries whose next attempt is due, with their endpoints.
+// Claimed deliveries are pushed lease into the future, so other workers skip them until the
+// attempt is recorded, or until the lease runs out if this worker dies first.
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// SKIP LOCKED lets concurrent workers claim disjoint batches instead of waiting on each other
+		err := tx.Preload("Endpoint").
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(deliveries))
+		for i := range deliveries {
+			ids[i] = deliveries[i].ID
+		}
+		return tx.Model(&models.WebhookDelivery{}).Where("id IN ?", ids).UpdateColumn("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// UpdateDelivery saves the outcome of a delivery attempt
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Model(delivery).
+		Select("status", "attempts", "next_attempt_at", "last_status_code", "last_error", "delivered_at", "updated_at").
+		Updates(delivery).Error
+}
+
+// GetDeliveries retrieves the most recent deliveries for an endpoint
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, endpointID uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.db.WithContext(ctx).Where("endpoint_id = ?", endpointID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// GetDelivery retrieves a delivery belonging to an endpoint
+func (r *WebhookRepository) GetDelivery(ctx context.Context, endpointID, id uuid.UUID) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	if err := r.db.WithContext(ctx).Where("id = ? AND endpoint_id = ?", id, endpointID).First(&delivery).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
@@ -1,26 +1,50 @@
 package routes
 
 import (
+	"net/http"
+
 	"github.com/1shoukr/linkvault/internal/controllers"
 	"github.com/1shoukr/linkvault/internal/middleware"
-	"github.com/1shoukr/linkvault/internal/repository"
 	"github.com/1shoukr/linkvault/internal/services"
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
-func SetupRoutes(router *gin.Engine, db *gorm.DB) {
-	// Initialize repositories
-	userRepo := repository.NewUserRepository(db)
-	authRepo := repository.NewAuthRepository(db)
+// Handlers are the controllers and middleware dependencies the routes are served by
+type Handlers struct {
+	AuthService *services.AuthService
+
+	User     *controllers.UserController
+	Auth     *controllers.AuthController
+	Link     *controllers.LinkController
+	Redirect *controllers.RedirectController
+	Check    *controllers.CheckController
+	Alert    *controllers.AlertController
+	Webhook  *controllers.WebhookController
+	Uptime   *controllers.UptimeController
+	Health   *controllers.HealthController
 
-	// Initialize services
-	userService := services.NewUserService(userRepo)
-	authService := services.NewAuthService(authRepo)
+	// Metrics is served on /metrics to callers presenting MetricsToken; with no token the
+	// endpoint is not registered
+	Metrics      http.Handler
+	MetricsToken string
+}
 
-	// Initialize controllers
-	userController := controllers.NewUserController(userService)
-	authController := controllers.NewAuthController(authService)
+// SetupRoutes registers every API route on router
+func SetupRoutes(router *gin.Engine, h Handlers) {
+	// Health and build info (unauthenticated, used by the platform healthcheck)
+	router.GET("/health", h.Health.Live)
+	router.GET("/health/ready", h.Health.Ready)
+	router.GET("/version", h.Health.Version)
+	router.GET("/api", h.Health.Info)
+	if h.Metrics != nil && h.MetricsToken != "" {
+		router.GET("/metrics", middleware.StaticToken(h.MetricsToken), gin.WrapH(h.Metrics))
+	}
+
+	// Public redirect route
+	router.GET("/r/:id", h.Redirect.Redirect)
+	router.POST("/r/:id", h.Redirect.Unlock)
+	router.GET("/r/:id/:channel", h.Redirect.Redirect)
+	router.POST("/r/:id/:channel", h.Redirect.Unlock)
 
 	// API routes
 	api := router.Group("/api")
@@ -28,22 +52,62 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB) {
 		// Public auth routes
 		auth := api.Group("/auth")
 		{
-			auth.POST("/login", authController.Login)
-			auth.POST("/register", authController.Register)
-			auth.POST("/logout", authController.Logout)
-			auth.POST("/forgot-password", authController.ForgotPassword)
-			auth.POST("/reset-password", authController.ResetPassword)
+			auth.POST("/login", h.Auth.Login)
+			auth.POST("/register", h.Auth.Register)
+			auth.POST("/logout", h.Auth.Logout)
+			auth.POST("/forgot-password", h.Auth.ForgotPassword)
+			auth.POST("/reset-password", h.Auth.ResetPassword)
 		}
 
 		// Protected routes (require authentication)
 		protected := api.Group("")
-		protected.Use(middleware.AuthMiddleware(authService))
+		protected.Use(middleware.AuthMiddleware(h.AuthService))
 		{
 			// User routes (protected)
 			users := protected.Group("/users")
 			{
-				users.GET("", userController.GetUsers)
-				users.GET("/:id", userController.GetUser)
+				users.GET("", h.User.GetUsers)
+				users.GET("/:id", h.User.GetUser)
+			}
+
+			// Link routes (protected)
+			links := protected.Group("/links")
+			{
+				links.GET("", h.Link.GetLinks)
+				links.POST("", h.Link.CreateLink)
+				links.POST("/detect", h.Link.DetectMerchant)
+				links.GET("/:id", h.Link.GetLink)
+				links.PATCH("/:id", h.Link.UpdateLink)
+				links.DELETE("/:id", h.Link.DeleteLink)
+				links.PUT("/:id/geo-rules", h.Link.SetGeoRules)
+				links.PUT("/:id/variants", h.Link.SetVariants)
+				links.PUT("/:id/device-rules", h.Link.SetDeviceRules)
+				links.POST("/:id/access-tokens", h.Link.CreateAccessToken)
+				links.PUT("/:id/channels", h.Link.SetChannels)
+				links.GET("/:id/utm-preview", h.Link.PreviewUTM)
+				links.POST("/:id/check", h.Check.CheckLink)
+				links.GET("/:id/checks", h.Check.GetCheckHistory)
+				links.GET("/:id/uptime", h.Uptime.GetLinkUptime)
+				links.GET("/:id/variants/stats", h.Link.GetVariantStats)
+			}
+
+			// Content rules for health checks (protected)
+			contentRules := protected.Group("/content-rules")
+			{
+				contentRules.GET("", h.Check.GetContentRules)
+				contentRules.POST("", h.Check.CreateContentRule)
+				contentRules.DELETE("/:id", h.Check.DeleteContentRule)
+			}
+
+			// Outbound webhooks (protected)
+			webhooks := protected.Group("/webhooks")
+			{
+				webhooks.GET("", h.Webhook.GetEndpoints)
+				webhooks.POST("", h.Webhook.CreateEndpoint)
+				webhooks.PATCH("/:id", h.Webhook.UpdateEndpoint)
+				webhooks.DELETE("/:id", h.Webhook.DeleteEndpoint)
+				webhooks.GET("/:id/deliveries", h.Webhook.GetDeliveries)
+				webhooks.POST("/:id/deliveries/:deliveryId/redeliver", h.Webhook.Redeliver)
 			}
 
 			// Example: Get current user profile
@@ -55,6 +119,13 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB) {
 				}
 				c.JSON(200, gin.H{"user": user})
 			})
+			protected.PUT("/me/utm-defaults", h.User.UpdateUTMDefaults)
+			protected.GET("/me/affiliate-tags", h.User.GetAffiliateTags)
+			protected.PUT("/me/affiliate-tags", h.User.SetAffiliateTags)
+			protected.GET("/me/notifications", h.Alert.GetPreferences)
+			protected.PUT("/me/notifications", h.Alert.UpdatePreferences)
+			protected.POST("/me/notifications/test", h.Alert.SendTestNotification)
+			protected.GET("/me/uptime", h.Uptime.GetUserUptime)
 		}
 	}
 }
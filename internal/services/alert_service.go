@@ -0,0 +1,505 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/1shoukr/linkvault/internal/netguard"
+	"github.com/1shoukr/linkvault/internal/notify"
+	"github.com/1shoukr/linkvault/internal/repository"
+	"github.com/google/uuid"
+)
+
+// NotificationPreferenceInput holds the user-editable alert settings. Nil fields are left unchanged.
+type NotificationPreferenceInput struct {
+	EmailEnabled     *bool   `json:"email_enabled"`
+	WebhookEnabled   *bool   `json:"webhook_enabled"`
+	WebhookURL       *string `json:"webhook_url"`
+	FailureThreshold *int    `json:"failure_threshold"`
+	DigestMinutes    *int    `json:"digest_minutes"`
+	RecoveryNotices  *bool   `json:"recovery_notices"`
+	FlapCooldownMins *int    `json:"flap_cooldown_minutes"`
+
+	SlackEnabled      *bool   `json:"slack_enabled"`
+	SlackWebhookURL   *string `json:"slack_webhook_url"`
+	DiscordEnabled    *bool   `json:"discord_enabled"`
+	DiscordWebhookURL *string `json:"discord_webhook_url"`
+	WeeklySummary     *bool   `json:"weekly_summary"`
+}
+
+const (
+	// weeklySummaryInterval is how often chat integrations receive a summary
+	weeklySummaryInterval = 7 * 24 * time.Hour
+	// weeklySummaryUnhealthyLimit caps how many failing links a summary lists
+	weeklySummaryUnhealthyLimit = 10
+)
+
+var (
+	// ErrUnknownChannel is returned when a test message names a channel that doesn't exist
+	ErrUnknownChannel = errors.New("unknown notification channel")
+	// ErrChannelNotConfigured is returned when a test message targets a channel without an address
+	ErrChannelNotConfigured = errors.New("notification channel is not configured")
+)
+
+// slackWebhookHosts and discordWebhookHosts are the hosts incoming-webhook URLs must point at
+var (
+	slackWebhookHosts   = []string{"hooks.slack.com"}
+	discordWebhookHosts = []string{"discord.com", "discordapp.com", "ptb.discord.com", "canary.discord.com"}
+)
+
+// AlertService turns health check results into debounced alerts and delivers them as digests
+type AlertService struct {
+	alertRepo      repository.AlertStore
+	userRepo       repository.UserStore
+	linkRepo       repository.LinkStore
+	webhookService *WebhookService
+	notifiers      []notify.Notifier
+
+	// AllowPrivateWebhooks accepts webhook URLs that resolve to loopback or private addresses
+	AllowPrivateWebhooks bool
+}
+
+// NewAlertService creates a new alert service delivering through the given channels
+func NewAlertService(alertRepo repository.AlertStore, userRepo repository.UserStore, linkRepo repository.LinkStore, webhookService *WebhookService, notifiers ...notify.Notifier) *AlertService {
+	return &AlertService{
+		alertRepo:      alertRepo,
+		userRepo:       userRepo,
+		linkRepo:       linkRepo,
+		webhookService: webhookService,
+		notifiers:      notifiers,
+	}
+}
+
+// EvaluateCheck updates a link's alerting state after a check and queues any resulting alert.
+// A link is reported down after the user's failure threshold of consecutive failed checks,
+// unless it recovered within the flap cooldown. If a link heals before its down alert was
+// delivered, both are dropped so flapping links don't generate noise.
+func (s *AlertService) EvaluateCheck(ctx context.Context, link *models.Link, healthy bool, reason string, now time.Time) {
+	prefs, err := s.alertRepo.GetPreferences(ctx, link.UserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load notification preferences", "user_id", link.UserID, "link_id", link.ID, "error", err)
+		prefs = models.DefaultNotificationPreference(link.UserID)
+	}
+
+	if healthy {
+		link.ConsecutiveFailures = 0
+		if link.AlertedDownAt == nil {
+			return
+		}
+		link.AlertedDownAt = nil
+		link.RecoveredAt = &now
+		s.webhookService.Publish(ctx, link.UserID, models.WebhookEventLinkRecovered, linkHealthEvent(link, "The link is working again", now))
+
+		dropped, err := s.alertRepo.DeletePending(ctx, link.ID, models.AlertKindDown)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to collapse pending down alert", "link_id", link.ID, "error", err)
+		}
+		if !dropped && prefs.RecoveryNotices {
+			s.queue(ctx, link, models.AlertKindRecovered, "The link is working again")
+		}
+		return
+	}
+
+	link.ConsecutiveFailures++
+	if link.AlertedDownAt != nil || link.ConsecutiveFailures < prefs.FailureThreshold {
+		return
+	}
+	cooldown := time.Duration(prefs.FlapCooldownMins) * time.Minute
+	if link.RecoveredAt != nil && now.Sub(*link.RecoveredAt) < cooldown {
+		return
+	}
+
+	link.AlertedDownAt = &now
+	message := fmt.Sprintf("Failed %d consecutive checks: %s", link.ConsecutiveFailures, reason)
+	s.queue(ctx, link, models.AlertKindDown, message)
+	s.webhookService.Publish(ctx, link.UserID, models.WebhookEventLinkDown, linkHealthEvent(link, message, now))
+}
+
+// SendDigests delivers pending alerts for every user whose digest window has elapsed
+func (s *AlertService) SendDigests(ctx context.Context, now time.Time) (int, error) {
+	pending, err := s.alertRepo.GetUsersWithPending(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for userID, oldest := range pending {
+		if ctx.Err() != nil {
+			break
+		}
+		prefs, err := s.alertRepo.GetPreferences(ctx, userID)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to load notification preferences", "user_id", userID, "error", err)
+			continue
+		}
+		if now.Sub(oldest) < time.Duration(prefs.DigestMinutes)*time.Minute {
+			continue
+		}
+		if err := s.sendDigest(ctx, userID, prefs, now); err != nil {
+			slog.ErrorContext(ctx, "Failed to deliver alert digest", "user_id", userID, "error", err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// GetPreferences retrieves a user's notification preferences
+func (s *AlertService) GetPreferences(ctx context.Context, userID uuid.UUID) (*models.NotificationPreference, error) {
+	return s.alertRepo.GetPreferences(ctx, userID)
+}
+
+// UpdatePreferences applies a partial update to a user's notification preferences
+func (s *AlertService) UpdatePreferences(ctx context.Context, userID uuid.UUID, input NotificationPreferenceInput) (*models.NotificationPreference, error) {
+	prefs, err := s.alertRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, errors.New("failed to load notification preferences")
+	}
+
+	if input.EmailEnabled != nil {
+		prefs.EmailEnabled = *input.EmailEnabled
+	}
+	if input.WebhookURL != nil {
+		if *input.WebhookURL == "" {
+			prefs.WebhookURL = nil
+		} else {
+			if err := validateDestinationURL(*input.WebhookURL); err != nil {
+				return nil, fmt.Errorf("invalid webhook URL %q", *input.WebhookURL)
+			}
+			if !s.AllowPrivateWebhooks {
+				if err := netguard.CheckURL(ctx, *input.WebhookURL); err != nil {
+					return nil, fmt.Errorf("invalid webhook URL %q: %w", *input.WebhookURL, err)
+				}
+			}
+			prefs.WebhookURL = input.WebhookURL
+		}
+	}
+	if input.WebhookEnabled != nil {
+		prefs.WebhookEnabled = *input.WebhookEnabled
+	}
+	if prefs.WebhookEnabled && prefs.WebhookURL == nil {
+		return nil, errors.New("webhook_url is required to enable webhook alerts")
+	}
+	if input.SlackWebhookURL != nil {
+		url, err := validateChatWebhookURL("slack_webhook_url", *input.SlackWebhookURL, slackWebhookHosts, "/services/")
+		if err != nil {
+			return nil, err
+		}
+		prefs.SlackWebhookURL = url
+	}
+	if input.SlackEnabled != nil {
+		prefs.SlackEnabled = *input.SlackEnabled
+	}
+	if prefs.SlackEnabled && prefs.SlackWebhookURL == nil {
+		return nil, errors.New("slack_webhook_url is required to enable Slack alerts")
+	}
+	if input.DiscordWebhookURL != nil {
+		url, err := validateChatWebhookURL("discord_webhook_url", *input.DiscordWebhookURL, discordWebhookHosts, "/api/webhooks/")
+		if err != nil {
+			return nil, err
+		}
+		prefs.DiscordWebhookURL = url
+	}
+	if input.DiscordEnabled != nil {
+		prefs.DiscordEnabled = *input.DiscordEnabled
+	}
+	if prefs.DiscordEnabled && prefs.DiscordWebhookURL == nil {
+		return nil, errors.New("discord_webhook_url is required to enable Discord alerts")
+	}
+	if input.WeeklySummary != nil {
+		prefs.WeeklySummary = *input.WeeklySummary
+	}
+	if input.FailureThreshold != nil {
+		if *input.FailureThreshold < 1 || *input.FailureThreshold > 20 {
+			return nil, errors.New("failure_threshold must be between 1 and 20")
+		}
+		prefs.FailureThreshold = *input.FailureThreshold
+	}
+	if input.DigestMinutes != nil {
+		if *input.DigestMinutes < 0 || *input.DigestMinutes > 7*24*60 {
+			return nil, errors.New("digest_minutes must be between 0 and 10080")
+		}
+		prefs.DigestMinutes = *input.DigestMinutes
+	}
+	if input.RecoveryNotices != nil {
+		prefs.RecoveryNotices = *input.RecoveryNotices
+	}
+	if input.FlapCooldownMins != nil {
+		if *input.FlapCooldownMins < 0 || *input.FlapCooldownMins > 7*24*60 {
+			return nil, errors.New("flap_cooldown_minutes must be between 0 and 10080")
+		}
+		prefs.FlapCooldownMins = *input.FlapCooldownMins
+	}
+
+	if err := s.alertRepo.SavePreferences(ctx, prefs); err != nil {
+		return nil, errors.New("failed to save notification preferences")
+	}
+	return prefs, nil
+}
+
+// queue stores a pending alert for a link
+func (s *AlertService) queue(ctx context.Context, link *models.Link, kind, message string) {
+	alert := &models.Alert{
+		UserID:  link.UserID,
+		LinkID:  link.ID,
+		Kind:    kind,
+		Message: message,
+	}
+	if err := s.alertRepo.Create(ctx, alert); err != nil {
+		slog.ErrorContext(ctx, "Failed to queue alert", "kind", kind, "link_id", link.ID, "error", err)
+	}
+}
+
+// sendDigest builds a user's digest from their pending alerts and broadcasts it, marking the
+// alerts delivered on success
+func (s *AlertService) sendDigest(ctx context.Context, userID uuid.UUID, prefs *models.NotificationPreference, now time.Time) error {
+	alerts, err := s.alertRepo.GetPending(ctx, userID)
+	if err != nil || len(alerts) == 0 {
+		return err
+	}
+	digest, err := s.newDigest(ctx, userID, prefs)
+	if err != nil {
+		return err
+	}
+	ids := make([]uuid.UUID, 0, len(alerts))
+	for _, alert := range alerts {
+		ids = append(ids, alert.ID)
+		item := linkItem(&alert.Link)
+		item.Kind = alert.Kind
+		item.Message = alert.Message
+		item.At = alert.CreatedAt
+		digest.Items = append(digest.Items, item)
+	}
+
+	if err := s.broadcast(ctx, digest, prefs, false); err != nil {
+		return err
+	}
+	// The digest went out, so record it even if shutdown has begun or it would be sent again
+	return s.alertRepo.MarkDelivered(context.WithoutCancel(ctx), ids, now)
+}
+
+// SendWeeklySummaries posts a weekly summary to the chat integrations of every user who is due one
+func (s *AlertService) SendWeeklySummaries(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.alertRepo.GetWeeklySummaryDue(ctx, now.Add(-weeklySummaryInterval))
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		prefs := &due[i]
+		if err := s.sendWeeklySummary(ctx, prefs, now); err != nil {
+			slog.ErrorContext(ctx, "Failed to deliver weekly summary", "user_id", prefs.UserID, "error", err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// SendTestNotification sends a test message over one of the user's channels, regardless of
+// whether the channel is currently enabled, so it can be verified before switching it on
+func (s *AlertService) SendTestNotification(ctx context.Context, userID uuid.UUID, channel string) error {
+	var notifier notify.Notifier
+	for _, n := range s.notifiers {
+		if n.Name() == channel {
+			notifier = n
+		}
+	}
+	if notifier == nil {
+		return ErrUnknownChannel
+	}
+
+	prefs, err := s.alertRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return errors.New("failed to load notification preferences")
+	}
+	if !channelConfigured(prefs, channel) {
+		return ErrChannelNotConfigured
+	}
+	digest, err := s.newDigest(ctx, userID, prefs)
+	if err != nil {
+		return errors.New("failed to load user")
+	}
+	digest.Test = true
+
+	if err := notifier.Send(ctx, digest); err != nil {
+		return fmt.Errorf("test message failed: %w", err)
+	}
+	return nil
+}
+
+// sendWeeklySummary builds a user's weekly stats and posts them to their chat channels
+func (s *AlertService) sendWeeklySummary(ctx context.Context, prefs *models.NotificationPreference, now time.Time) error {
+	since := now.Add(-weeklySummaryInterval)
+	counts, err := s.linkRepo.GetHealthCounts(ctx, prefs.UserID)
+	if err != nil {
+		return err
+	}
+	clicks, err := s.linkRepo.CountClicksSince(ctx, prefs.UserID, since)
+	if err != nil {
+		return err
+	}
+	downAlerts, err := s.alertRepo.CountSince(ctx, prefs.UserID, models.AlertKindDown, since)
+	if err != nil {
+		return err
+	}
+	unhealthy, err := s.linkRepo.GetUnhealthy(ctx, prefs.UserID, weeklySummaryUnhealthyLimit)
+	if err != nil {
+		return err
+	}
+
+	digest, err := s.newDigest(ctx, prefs.UserID, prefs)
+	if err != nil {
+		return err
+	}
+	digest.Weekly = &notify.WeeklyStats{
+		PeriodStart:    since,
+		PeriodEnd:      now,
+		TotalLinks:     counts.Total,
+		UnhealthyLinks: counts.Unhealthy,
+		Clicks:         clicks,
+		DownAlerts:     downAlerts,
+	}
+	for i := range unhealthy {
+		item := linkItem(&unhealthy[i])
+		item.Kind = notify.KindDown
+		digest.Weekly.Unhealthy = append(digest.Weekly.Unhealthy, item)
+	}
+
+	if err := s.broadcast(ctx, digest, prefs, true); err != nil {
+		return err
+	}
+	return s.alertRepo.MarkWeeklySummarySent(context.WithoutCancel(ctx), prefs.UserID, now)
+}
+
+// newDigest creates an empty digest addressed to a user's configured channels
+func (s *AlertService) newDigest(ctx context.Context, userID uuid.UUID, prefs *models.NotificationPreference) (*notify.Digest, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := &notify.Digest{UserID: userID, Email: user.Email}
+	if user.Name != nil {
+		digest.Name = *user.Name
+	}
+	if prefs.WebhookURL != nil {
+		digest.WebhookURL = *prefs.WebhookURL
+	}
+	if prefs.SlackWebhookURL != nil {
+		digest.SlackWebhookURL = *prefs.SlackWebhookURL
+	}
+	if prefs.DiscordWebhookURL != nil {
+		digest.DiscordWebhookURL = *prefs.DiscordWebhookURL
+	}
+	return digest, nil
+}
+
+// broadcast sends a digest on every enabled channel (chat channels only, if chatOnly). It
+// succeeds once any channel delivers, or when none is enabled, so a single broken channel
+// doesn't hold back the others.
+func (s *AlertService) broadcast(ctx context.Context, digest *notify.Digest, prefs *models.NotificationPreference, chatOnly bool) error {
+	attempted, delivered := 0, 0
+	var lastErr error
+	for _, notifier := range s.notifiers {
+		if !channelEnabled(prefs, notifier.Name()) || (chatOnly && !isChatChannel(notifier.Name())) {
+			continue
+		}
+		attempted++
+		if err := notifier.Send(ctx, digest); err != nil {
+			slog.ErrorContext(ctx, "Alert channel failed", "channel", notifier.Name(), "user_id", digest.UserID, "error", err)
+			lastErr = err
+			continue
+		}
+		delivered++
+	}
+	if attempted > 0 && delivered == 0 {
+		return lastErr
+	}
+	return nil
+}
+
+// linkItem describes a link for a digest, titled by its title or, failing that, its URL
+func linkItem(link *models.Link) notify.Item {
+	item := notify.Item{
+		LinkID:    link.ID,
+		LinkTitle: link.OriginalURL,
+		LinkURL:   link.OriginalURL,
+	}
+	if link.Title != nil {
+		item.LinkTitle = *link.Title
+	}
+	if link.LastWorkingAt != nil {
+		item.At = *link.LastWorkingAt
+	}
+	return item
+}
+
+// linkHealthEvent builds the webhook payload for a link going down or recovering
+func linkHealthEvent(link *models.Link, message string, at time.Time) LinkHealthEventData {
+	return LinkHealthEventData{
+		LinkID:      link.ID,
+		Title:       link.Title,
+		OriginalURL: link.OriginalURL,
+		Message:     message,
+		At:          at,
+	}
+}
+
+// channelEnabled reports whether a user has turned on a delivery channel
+func channelEnabled(prefs *models.NotificationPreference, channel string) bool {
+	switch channel {
+	case "email":
+		return prefs.EmailEnabled
+	case "webhook":
+		return prefs.WebhookEnabled && prefs.WebhookURL != nil
+	case "slack":
+		return prefs.SlackEnabled && prefs.SlackWebhookURL != nil
+	case "discord":
+		return prefs.DiscordEnabled && prefs.DiscordWebhookURL != nil
+	}
+	return false
+}
+
+// channelConfigured reports whether a user has given a delivery channel an address
+func channelConfigured(prefs *models.NotificationPreference, channel string) bool {
+	switch channel {
+	case "email":
+		return true
+	case "webhook":
+		return prefs.WebhookURL != nil
+	case "slack":
+		return prefs.SlackWebhookURL != nil
+	case "discord":
+		return prefs.DiscordWebhookURL != nil
+	}
+	return false
+}
+
+// isChatChannel reports whether a channel is a chat integration that receives weekly summaries
+func isChatChannel(channel string) bool {
+	return channel == "slack" || channel == "discord"
+}
+
+// validateChatWebhookURL checks an incoming-webhook URL points at the service's own host and
+// path. An empty value clears the URL.
+func validateChatWebhookURL(field, raw string, hosts []string, pathPrefix string) (*string, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || !containsString(hosts, strings.ToLower(u.Hostname())) || !strings.HasPrefix(u.Path, pathPrefix) {
+		return nil, fmt.Errorf("%s must be an https://%s%s... incoming webhook URL", field, hosts[0], pathPrefix)
+	}
+	return &raw, nil
+}
@@ -1,9 +1,10 @@
 package services
 
 import (
+	"context"
 	"errors"
-	"time"
 
+	"github.com/1shoukr/linkvault/internal/clock"
 	"github.com/1shoukr/linkvault/internal/models"
 	"github.com/1shoukr/linkvault/internal/repository"
 	"github.com/1shoukr/linkvault/pkg/utils"
@@ -12,20 +13,24 @@ import (
 
 // AuthService handles business logic for authentication operations
 type AuthService struct {
-	authRepo *repository.AuthRepository
+	authRepo repository.AuthStore
+	tokens   *utils.TokenIssuer
+	clock    clock.Clock
 }
 
 // NewAuthService creates a new auth service
-func NewAuthService(authRepo *repository.AuthRepository) *AuthService {
+func NewAuthService(authRepo repository.AuthStore, tokens *utils.TokenIssuer, clk clock.Clock) *AuthService {
 	return &AuthService{
 		authRepo: authRepo,
+		tokens:   tokens,
+		clock:    clk,
 	}
 }
 
 // Register registers a new user
-func (s *AuthService) Register(email, password, name string) (*models.User, string, error) {
+func (s *AuthService) Register(ctx context.Context, email, password, name string) (*models.User, string, error) {
 	// Check if user already exists
-	existingUser, _ := s.authRepo.GetUserByEmail(email)
+	existingUser, _ := s.authRepo.GetUserByEmail(ctx, email)
 	if existingUser != nil {
 		return nil, "", errors.New("user with this email already exists")
 	}
@@ -41,31 +46,31 @@ func (s *AuthService) Register(email, password, name string) (*models.User, stri
 		Email:         email,
 		PasswordHash:  &hashedPassword,
 		Name:          &name,
-		Plan:          "free",
+		Plan:          models.PlanFree,
 		EmailVerified: false,
 	}
 
-	if err := s.authRepo.CreateUser(user); err != nil {
+	if err := s.authRepo.CreateUser(ctx, user); err != nil {
 		return nil, "", errors.New("failed to create user")
 	}
 
 	// Generate JWT token
-	token, err := utils.GenerateToken(user.ID, user.Email)
+	token, err := s.tokens.GenerateToken(user.ID, user.Email)
 	if err != nil {
 		return nil, "", errors.New("failed to generate token")
 	}
 
 	// Update last login
-	now := time.Now()
+	now := s.clock.Now()
 	user.LastLoginAt = &now
-	s.authRepo.UpdateUser(user)
+	s.authRepo.UpdateUser(ctx, user)
 
 	return user, token, nil
 }
 
 // Login logs in a user
-func (s *AuthService) Login(email string, password string) (*models.User, string, error) {
-	user, err := s.authRepo.GetUserByEmail(email)
+func (s *AuthService) Login(ctx context.Context, email string, password string) (*models.User, string, error) {
+	user, err := s.authRepo.GetUserByEmail(ctx, email)
 	if err != nil {
 		return nil, "", errors.New("invalid email or password")
 	}
@@ -81,20 +86,29 @@ func (s *AuthService) Login(email string, password string) (*models.User, string
 	}
 
 	// Generate JWT token
-	token, err := utils.GenerateToken(user.ID, user.Email)
+	token, err := s.tokens.GenerateToken(user.ID, user.Email)
 	if err != nil {
 		return nil, "", errors.New("failed to generate token")
 	}
 
 	// Update last login
-	now := time.Now()
+	now := s.clock.Now()
 	user.LastLoginAt = &now
-	s.authRepo.UpdateUser(user)
+	s.authRepo.UpdateUser(ctx, user)
 
 	return user, token, nil
 }
 
 // GetUserByID retrieves a user by ID
-func (s *AuthService) GetUserByID(id uuid.UUID) (*models.User, error) {
-	return s.authRepo.GetUserByID(id)
+func (s *AuthService) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	return s.authRepo.GetUserByID(ctx, id)
+}
+
+// Authenticate validates a session token and returns the user it was issued to
+func (s *AuthService) Authenticate(ctx context.Context, token string) (*models.User, error) {
+	claims, err := s.tokens.ValidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return s.authRepo.GetUserByID(ctx, claims.UserID)
 }
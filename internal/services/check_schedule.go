@@ -0,0 +1,112 @@
+package services
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+)
+
+// CheckSchedule tunes how often links are health-checked. Each link's next check is derived
+// from its owner's plan, its recent click velocity and its health.
+type CheckSchedule struct {
+	FreeInterval time.Duration // healthy link on the free plan
+	ProInterval  time.Duration // healthy link on the Pro plan
+	MinInterval  time.Duration // floor for hot or failing links
+	MaxInterval  time.Duration // ceiling for cold or long-dead links
+	FailureRetry time.Duration // first recheck after a failure; doubles with each further failure
+	DeadAfter    time.Duration // links failing this long back off, doubling per DeadAfter elapsed
+}
+
+const (
+	// hotClicksPerHour and warmClicksPerHour are the click velocities at which healthy
+	// links are checked 4x and 2x as often
+	hotClicksPerHour  = 10.0
+	warmClicksPerHour = 1.0
+	// coldLinkAge is how old an idle link must be before its checks are spaced out
+	coldLinkAge = 7 * 24 * time.Hour
+	// scheduleJitter spreads checks by up to ±10% so links created together don't stay in lockstep
+	scheduleJitter = 0.1
+)
+
+// NextCheckAt computes when a link that was just checked at now should next be checked.
+// The link must carry the result of that check and its User; velocity is its click rate
+// leading up to the check, from ClickVelocity.
+func (s CheckSchedule) NextCheckAt(link *models.Link, velocity float64, now time.Time) time.Time {
+	return now.Add(jitter(s.interval(link, velocity, now)))
+}
+
+// interval picks the delay before a link's next check
+func (s CheckSchedule) interval(link *models.Link, velocity float64, now time.Time) time.Duration {
+	base := s.FreeInterval
+	if link.User.IsPro() {
+		base = s.ProInterval
+	}
+
+	var interval time.Duration
+	if link.IsHealthy {
+		interval = s.healthyInterval(link, base, velocity, now)
+	} else {
+		interval = s.failingInterval(link, base, now)
+	}
+	return min(max(interval, s.MinInterval), s.MaxInterval)
+}
+
+// healthyInterval speeds up checks of links getting clicks and slows down idle old ones
+func (s CheckSchedule) healthyInterval(link *models.Link, base time.Duration, velocity float64, now time.Time) time.Duration {
+	switch {
+	case velocity >= hotClicksPerHour:
+		return base / 4
+	case velocity >= warmClicksPerHour:
+		return base / 2
+	case velocity == 0 && now.Sub(link.CreatedAt) > coldLinkAge:
+		return base * 2
+	}
+	return base
+}
+
+// failingInterval rechecks soon after a failure to confirm it, settles at the plan cadence
+// while the link stays down, and backs off once it has been dead for a long time
+func (s CheckSchedule) failingInterval(link *models.Link, base time.Duration, now time.Time) time.Duration {
+	downSince := link.CreatedAt
+	if link.LastWorkingAt != nil {
+		downSince = *link.LastWorkingAt
+	}
+	if s.DeadAfter > 0 {
+		if periods := int(now.Sub(downSince) / s.DeadAfter); periods > 0 {
+			return doubled(base, periods, s.MaxInterval)
+		}
+	}
+
+	return doubled(s.FailureRetry, link.ConsecutiveFailures-1, base)
+}
+
+// doubled returns d doubled n times, saturating at limit rather than overflowing
+func doubled(d time.Duration, n int, limit time.Duration) time.Duration {
+	for ; n > 0 && d > 0; n-- {
+		if d > limit/2 {
+			return limit
+		}
+		d *= 2
+	}
+	return min(d, limit)
+}
+
+// ClickVelocity returns a link's clicks per hour since its previous check. It must be called
+// before the link's check fields are updated.
+func ClickVelocity(link *models.Link, now time.Time) float64 {
+	if link.LastCheckedAt == nil {
+		return 0
+	}
+	elapsed := now.Sub(*link.LastCheckedAt).Hours()
+	clicks := link.ClickCount - link.CheckClickCount
+	if elapsed <= 0 || clicks <= 0 {
+		return 0
+	}
+	return float64(clicks) / elapsed
+}
+
+// jitter randomizes d by up to ±scheduleJitter
+func jitter(d time.Duration) time.Duration {
+	return time.Duration(float64(d) * (1 + scheduleJitter*(2*rand.Float64()-1)))
+}
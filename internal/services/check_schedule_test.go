@@ -0,0 +1,46 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+)
+
+func TestFailingIntervalSaturates(t *testing.T) {
+	s := CheckSchedule{
+		FreeInterval: 6 * time.Hour,
+		ProInterval:  time.Hour,
+		MinInterval:  5 * time.Minute,
+		MaxInterval:  7 * 24 * time.Hour,
+		FailureRetry: 5 * time.Minute,
+		DeadAfter:    7 * 24 * time.Hour,
+	}
+	now := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	base := s.FreeInterval
+
+	tests := []struct {
+		name      string
+		failures  int
+		downSince time.Time
+		want      time.Duration
+	}{
+		{name: "first failure", failures: 1, downSince: now.Add(-time.Hour), want: 5 * time.Minute},
+		{name: "third failure", failures: 3, downSince: now.Add(-time.Hour), want: 20 * time.Minute},
+		{name: "retries settle at the plan cadence", failures: 10, downSince: now.Add(-time.Hour), want: base},
+		{name: "retries never overflow", failures: 1 << 20, downSince: now.Add(-time.Hour), want: base},
+		{name: "dead links back off", failures: 100, downSince: now.Add(-8 * 24 * time.Hour), want: 2 * base},
+		{name: "long-dead links stop at the ceiling", failures: 100, downSince: now.Add(-200 * 7 * 24 * time.Hour), want: s.MaxInterval},
+		{name: "decades dead never overflows", failures: 100, downSince: time.Time{}, want: s.MaxInterval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			downSince := tt.downSince
+			link := &models.Link{CreatedAt: downSince, LastWorkingAt: &downSince, ConsecutiveFailures: tt.failures}
+			if got := s.failingInterval(link, base, now); got != tt.want {
+				t.Errorf("failingInterval = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/checker"
+	"github.com/1shoukr/linkvault/internal/metrics"
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/1shoukr/linkvault/internal/netguard"
+	"github.com/1shoukr/linkvault/internal/repository"
+	"github.com/google/uuid"
+)
+
+// ErrContentRuleNotFound is returned when a content rule doesn't exist or isn't owned by the caller
+var ErrContentRuleNotFound = errors.New("content rule not found")
+
+// checkClaimLease is how long a claimed link is hidden from other workers. A check normally
+// reschedules the link well within it; a link whose worker died is picked up again once it lapses.
+const checkClaimLease = 15 * time.Minute
+
+// ContentRuleInput describes a user-defined content rule
+type ContentRuleInput struct {
+	LinkID  *uuid.UUID `json:"link_id"`
+	Name    string     `json:"name" binding:"required"`
+	Pattern string     `json:"pattern" binding:"required"`
+	IsRegex bool       `json:"is_regex"`
+	Message *string    `json:"message"`
+}
+
+// CheckService handles business logic for link health checks
+type CheckService struct {
+	linkRepo      repository.LinkStore
+	checkRepo     repository.CheckStore
+	checker       *checker.Checker
+	alertService  *AlertService
+	uptimeService *UptimeService
+	schedule      CheckSchedule
+
+	// Metrics, when set, counts check outcomes by destination host
+	Metrics *metrics.Metrics
+	// AllowPrivateNetworks runs on-demand checks of destinations on loopback or private addresses
+	AllowPrivateNetworks bool
+}
+
+// NewCheckService creates a new check service
+func NewCheckService(linkRepo repository.LinkStore, checkRepo repository.CheckStore, c *checker.Checker, alertService *AlertService, uptimeService *UptimeService, schedule CheckSchedule) *CheckService {
+	return &CheckService{
+		linkRepo:      linkRepo,
+		checkRepo:     checkRepo,
+		checker:       c,
+		alertService:  alertService,
+		uptimeService: uptimeService,
+		schedule:      schedule,
+	}
+}
+
+// CheckLink checks a link's destination, records the result and updates the link's health
+func (s *CheckService) CheckLink(ctx context.Context, link *models.Link) (*models.LinkCheckHistory, error) {
+	result := s.checker.Check(ctx, link.OriginalURL, s.contentRulesFor(ctx, link)...)
+	if ctx.Err() != nil {
+		// Interrupted, e.g. by shutdown; the result says nothing about the link
+		return nil, ctx.Err()
+	}
+
+	s.Metrics.Check(checkOutcome(result), linkHost(link))
+
+	history := newCheckHistory(link.ID, result)
+	if err := s.checkRepo.Create(ctx, history); err != nil {
+		return nil, err
+	}
+	if result.RateLimited {
+		// The host throttled us, so the link's health is unknown; leave it as is and come
+		// back once the host allows
+		nextCheckAt := history.CheckedAt.Add(jitter(max(result.RetryAfter, s.schedule.MinInterval)))
+		if err := s.linkRepo.Reschedule(ctx, link.ID, nextCheckAt); err != nil {
+			return nil, err
+		}
+		link.NextCheckAt = &nextCheckAt
+		return history, nil
+	}
+
+	velocity := ClickVelocity(link, history.CheckedAt)
+	responseTime := int(result.ResponseTime.Milliseconds())
+	link.IsHealthy = result.Healthy
+	link.LastStatusCode = &result.StatusCode
+	link.LastResponseTime = &responseTime
+	link.LastCheckedAt = &history.CheckedAt
+	link.FinalURL = history.FinalURL
+	if result.Healthy {
+		link.LastWorkingAt = &history.CheckedAt
+	}
+	s.alertService.EvaluateCheck(ctx, link, result.Healthy, result.Error, history.CheckedAt)
+	s.uptimeService.TrackIncident(ctx, link, result.Healthy, result.Error, history.CheckedAt)
+	nextCheckAt := s.schedule.NextCheckAt(link, velocity, history.CheckedAt)
+	link.NextCheckAt = &nextCheckAt
+	link.CheckClickCount = link.ClickCount
+	if err := s.linkRepo.UpdateHealth(ctx, link); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// CheckUserLink runs an on-demand check of a link owned by a user
+func (s *CheckService) CheckUserLink(ctx context.Context, userID, id uuid.UUID) (*models.LinkCheckHistory, error) {
+	link, err := s.getUserLink(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !s.AllowPrivateNetworks {
+		if err := netguard.CheckURL(ctx, link.OriginalURL); errors.Is(err, netguard.ErrPrivateAddress) {
+			return nil, fmt.Errorf("cannot check %s: %w", link.OriginalURL, err)
+		}
+	}
+	history, err := s.CheckLink(ctx, link)
+	if err != nil {
+		return nil, errors.New("failed to record check")
+	}
+	return history, nil
+}
+
+// GetCheckHistory retrieves recent checks for a link owned by a user
+func (s *CheckService) GetCheckHistory(ctx context.Context, userID, id uuid.UUID, limit int) ([]models.LinkCheckHistory, error) {
+	if _, err := s.getUserLink(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	return s.checkRepo.GetByLinkID(ctx, id, limit)
+}
+
+// CheckDueLinks checks up to batchSize links whose scheduled check is due, using up to
+// concurrency parallel requests, and returns how many were checked
+func (s *CheckService) CheckDueLinks(ctx context.Context, batchSize, concurrency int) (int, error) {
+	links, err := s.linkRepo.ClaimDueForCheck(ctx, time.Now(), checkClaimLease, batchSize)
+	if err != nil {
+		return 0, err
+	}
+	links = interleaveByHost(links)
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		checked  int
+		firstErr error
+	)
+	sem := make(chan struct{}, concurrency)
+	for i := range links {
+		if ctx.Err() != nil {
+			break
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(link *models.Link) {
+			defer func() { <-sem; wg.Done() }()
+			_, err := s.CheckLink(ctx, link)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil && ctx.Err() == nil {
+					firstErr = err
+				}
+				return
+			}
+			checked++
+		}(&links[i])
+	}
+	wg.Wait()
+
+	return checked, firstErr
+}
+
+// GetContentRules retrieves the content rules defined by a user
+func (s *CheckService) GetContentRules(ctx context.Context, userID uuid.UUID) ([]models.ContentRule, error) {
+	return s.checkRepo.GetRulesByUserID(ctx, userID)
+}
+
+// CreateContentRule validates and stores a user-defined content rule
+func (s *CheckService) CreateContentRule(ctx context.Context, userID uuid.UUID, input ContentRuleInput) (*models.ContentRule, error) {
+	if input.LinkID != nil {
+		if _, err := s.getUserLink(ctx, userID, *input.LinkID); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := checker.NewTextRule(input.Name, input.Pattern, input.IsRegex, ""); err != nil {
+		return nil, err
+	}
+
+	rule := &models.ContentRule{
+		UserID:  userID,
+		LinkID:  input.LinkID,
+		Name:    strings.TrimSpace(input.Name),
+		Pattern: input.Pattern,
+		IsRegex: input.IsRegex,
+		Message: input.Message,
+	}
+	if err := s.checkRepo.CreateRule(ctx, rule); err != nil {
+		return nil, errors.New("failed to create content rule")
+	}
+	return rule, nil
+}
+
+// DeleteContentRule deletes a content rule owned by a user
+func (s *CheckService) DeleteContentRule(ctx context.Context, userID, id uuid.UUID) error {
+	deleted, err := s.checkRepo.DeleteRule(ctx, userID, id)
+	if err != nil {
+		return errors.New("failed to delete content rule")
+	}
+	if !deleted {
+		return ErrContentRuleNotFound
+	}
+	return nil
+}
+
+// contentRulesFor compiles the user-defined content rules that apply to a link
+func (s *CheckService) contentRulesFor(ctx context.Context, link *models.Link) []checker.ContentRule {
+	stored, err := s.checkRepo.GetRulesForLink(ctx, link.UserID, link.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load content rules", "link_id", link.ID, "error", err)
+		return nil
+	}
+
+	rules := make([]checker.ContentRule, 0, len(stored))
+	for _, r := range stored {
+		var message string
+		if r.Message != nil {
+			message = *r.Message
+		}
+		rule, err := checker.NewTextRule(r.Name, r.Pattern, r.IsRegex, message)
+		if err != nil {
+			slog.WarnContext(ctx, "Skipping invalid content rule", "rule_id", r.ID, "link_id", link.ID, "error", err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func (s *CheckService) getUserLink(ctx context.Context, userID, id uuid.UUID) (*models.Link, error) {
+	link, err := s.linkRepo.GetByID(ctx, id)
+	if err != nil || link.UserID != userID {
+		return nil, ErrLinkNotFound
+	}
+	return link, nil
+}
+
+// newCheckHistory converts a checker result into a history row
+func newCheckHistory(linkID uuid.UUID, result *checker.Result) *models.LinkCheckHistory {
+	responseTime := int(result.ResponseTime.Milliseconds())
+	history := &models.LinkCheckHistory{
+		LinkID:       linkID,
+		CheckedAt:    time.Now(),
+		StatusCode:   result.StatusCode,
+		ResponseTime: &responseTime,
+		IsHealthy:    result.Healthy,
+		HopCount:     len(result.Hops),
+		SoftBroken:   result.SoftBroken,
+		RateLimited:  result.RateLimited,
+	}
+	if result.Error != "" {
+		history.ErrorMessage = &result.Error
+	}
+	if result.FinalURL != "" {
+		history.FinalURL = &result.FinalURL
+	}
+	for _, hop := range result.Hops {
+		history.RedirectChain = append(history.RedirectChain, models.RedirectHop(hop))
+	}
+	return history
+}
+
+// linkHost returns the lowercased host of a link's destination, or "" if it doesn't parse
+func linkHost(link *models.Link) string {
+	u, err := url.Parse(link.OriginalURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// checkOutcome classifies a check result for metrics
+func checkOutcome(result *checker.Result) string {
+	switch {
+	case result.RateLimited:
+		return metrics.CheckRateLimited
+	case result.Healthy:
+		return metrics.CheckHealthy
+	case result.StatusCode == 0:
+		return metrics.CheckUnreachable
+	default:
+		return metrics.CheckUnhealthy
+	}
+}
+
+// interleaveByHost reorders links round-robin across destination hosts, so a batch dominated
+// by one merchant doesn't tie up every worker slot queueing for that host's limits
+func interleaveByHost(links []models.Link) []models.Link {
+	var hosts []string
+	groups := make(map[string][]models.Link)
+	for _, link := range links {
+		host := linkHost(&link)
+		if _, ok := groups[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		groups[host] = append(groups[host], link)
+	}
+
+	ordered := make([]models.Link, 0, len(links))
+	for len(ordered) < len(links) {
+		for _, host := range hosts {
+			if group := groups[host]; len(group) > 0 {
+				ordered = append(ordered, group[0])
+				groups[host] = group[1:]
+			}
+		}
+	}
+	return ordered
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/1shoukr/linkvault/internal/models"
+)
+
+const (
+	// clickQueueSize bounds how many clicks wait for a writer before Record writes inline
+	clickQueueSize = 1024
+	// clickWriters is the number of goroutines writing queued clicks
+	clickWriters = 4
+)
+
+// queuedClick is a click waiting to be written
+type queuedClick struct {
+	link  *models.Link
+	click *models.Click
+}
+
+// ClickRecorder writes clicks off the redirect's request path. Clicks are queued and stored by
+// background writers; when stopped, Run flushes whatever is still queued so none are lost.
+type ClickRecorder struct {
+	linkService *LinkService
+	queue       chan queuedClick
+}
+
+// NewClickRecorder creates a click recorder
+func NewClickRecorder(linkService *LinkService) *ClickRecorder {
+	return &ClickRecorder{
+		linkService: linkService,
+		queue:       make(chan queuedClick, clickQueueSize),
+	}
+}
+
+// Record queues a click for writing. If the queue is full the click is written inline, slowing
+// the redirect rather than dropping it.
+func (r *ClickRecorder) Record(ctx context.Context, link *models.Link, click *models.Click) {
+	select {
+	case r.queue <- queuedClick{link: link, click: click}:
+	default:
+		// The visitor has their redirect either way, so a request that times out or disconnects
+		// mid-write must not lose the click
+		r.write(context.WithoutCancel(ctx), queuedClick{link: link, click: click})
+	}
+}
+
+// QueueDepth returns how many clicks are waiting for a writer
+func (r *ClickRecorder) QueueDepth() int {
+	return len(r.queue)
+}
+
+// Run writes queued clicks until ctx is cancelled, then flushes the queue and returns
+func (r *ClickRecorder) Run(ctx context.Context) {
+	// Writes, the final flush included, must outlive the cancellation that stops the writers
+	writeCtx := context.WithoutCancel(ctx)
+	var wg sync.WaitGroup
+	for i := 0; i < clickWriters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case queued := <-r.queue:
+					r.write(writeCtx, queued)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	flushed := 0
+	for {
+		select {
+		case queued := <-r.queue:
+			r.write(writeCtx, queued)
+			flushed++
+		default:
+			if flushed > 0 {
+				slog.InfoContext(ctx, "Click recorder flushed queued clicks", "flushed", flushed)
+			}
+			return
+		}
+	}
+}
+
+func (r *ClickRecorder) write(ctx context.Context, queued queuedClick) {
+	if err := r.linkService.RecordClick(ctx, queued.link, queued.click); err != nil {
+		slog.ErrorContext(ctx, "Failed to record click", "link_id", queued.click.LinkID, "error", err)
+	}
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/1shoukr/linkvault/internal/repository"
+	"github.com/1shoukr/linkvault/internal/repository/memory"
+)
+
+// cancellableLinks fails writes made with a cancelled context, as the gorm stores do
+type cancellableLinks struct {
+	repository.LinkStore
+}
+
+func (s cancellableLinks) RecordClick(ctx context.Context, click *models.Click, counted bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.LinkStore.RecordClick(ctx, click, counted)
+}
+
+func TestClickRecorderInlineWriteOutlivesRequest(t *testing.T) {
+	db := memory.NewDB()
+	users, links := memory.NewUserRepository(db), memory.NewLinkRepository(db)
+	user := &models.User{Email: "clicks@example.com"}
+	if err := users.Create(t.Context(), user); err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	link := &models.Link{UserID: user.ID, OriginalURL: "https://example.com"}
+	if err := links.Create(t.Context(), link); err != nil {
+		t.Fatalf("creating link: %v", err)
+	}
+
+	webhooks := NewWebhookService(memory.NewWebhookRepository(db), time.Second, nil)
+	linkService := NewLinkService(cancellableLinks{links}, users, webhooks, nil)
+	// An unbuffered queue with no writers is always full, so every click is written inline
+	recorder := &ClickRecorder{linkService: linkService, queue: make(chan queuedClick)}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	recorder.Record(ctx, link, &models.Click{LinkID: link.ID})
+
+	got, err := links.GetByID(t.Context(), link.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.ClickCount != 1 {
+		t.Fatalf("click_count = %d after recording with a cancelled request, want 1", got.ClickCount)
+	}
+}
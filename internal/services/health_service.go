@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/health"
+)
+
+// readinessTimeout bounds the dependency checks of one readiness probe
+const readinessTimeout = 2 * time.Second
+
+// Overall readiness statuses
+const (
+	ReadinessReady       = "ready"
+	ReadinessDegraded    = "degraded"    // serving, but a background worker is stale or the replica is down
+	ReadinessUnavailable = "unavailable" // the database is unreachable or the schema is behind
+)
+
+// Dependency check statuses
+const (
+	CheckOK      = "ok"
+	CheckFailed  = "failed"
+	CheckPending = "pending"
+	CheckSkipped = "skipped" // no database configured, e.g. when running on in-memory stores
+)
+
+// Pinger checks connectivity to the database; *sql.DB satisfies it
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// MigrationState reports how many schema migrations are still to be applied
+type MigrationState interface {
+	Pending(ctx context.Context) (int, error)
+}
+
+// DependencyCheck is the outcome of probing one dependency
+type DependencyCheck struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// MigrationCheck reports whether the schema is up to date
+type MigrationCheck struct {
+	Status  string `json:"status"`
+	Pending int    `json:"pending"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Readiness is the result of a readiness probe
+type Readiness struct {
+	Status     string                `json:"status"`
+	Database   DependencyCheck       `json:"database"`
+	Replica    *DependencyCheck      `json:"replica,omitempty"`
+	Migrations MigrationCheck        `json:"migrations"`
+	Workers    []health.WorkerStatus `json:"workers"`
+	CheckedAt  time.Time             `json:"checked_at"`
+}
+
+// Ready reports whether the instance should receive traffic
+func (r *Readiness) Ready() bool {
+	return r.Status != ReadinessUnavailable
+}
+
+// HealthService probes the dependencies the API needs to serve traffic
+type HealthService struct {
+	db         Pinger
+	migrations MigrationState
+	monitor    *health.Monitor
+
+	// Replica, when set, is pinged too. Only statistics and reports read from it, so losing it
+	// degrades the instance rather than taking it out of rotation.
+	Replica Pinger
+}
+
+// NewHealthService creates a new health service. db and migrations may be nil when the App runs
+// without a database, in which case both checks are reported as skipped.
+func NewHealthService(db Pinger, migrations MigrationState, monitor *health.Monitor) *HealthService {
+	return &HealthService{
+		db:         db,
+		migrations: migrations,
+		monitor:    monitor,
+	}
+}
+
+// Readiness pings the database and any replica, checks for pending migrations and collects
+// worker heartbeats
+func (s *HealthService) Readiness(ctx context.Context) *Readiness {
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	now := time.Now()
+	readiness := &Readiness{
+		Status:     ReadinessReady,
+		Database:   DependencyCheck{Status: CheckOK},
+		Migrations: MigrationCheck{Status: CheckOK},
+		Workers:    s.monitor.Workers(now),
+		CheckedAt:  now.UTC(),
+	}
+
+	s.checkDatabase(ctx, readiness)
+	if s.Replica != nil {
+		replica := ping(ctx, s.Replica)
+		readiness.Replica = &replica
+	}
+
+	switch {
+	case !passed(readiness.Database.Status) || !passed(readiness.Migrations.Status):
+		readiness.Status = ReadinessUnavailable
+	case readiness.Replica != nil && readiness.Replica.Status != CheckOK:
+		readiness.Status = ReadinessDegraded
+	case hasStaleWorker(readiness.Workers):
+		readiness.Status = ReadinessDegraded
+	}
+	return readiness
+}
+
+// checkDatabase pings the database and, once it answers, counts pending migrations
+func (s *HealthService) checkDatabase(ctx context.Context, readiness *Readiness) {
+	if s.db == nil {
+		readiness.Database.Status = CheckSkipped
+		readiness.Migrations.Status = CheckSkipped
+		return
+	}
+
+	readiness.Database = ping(ctx, s.db)
+	if readiness.Database.Status != CheckOK {
+		readiness.Migrations.Status = CheckFailed
+		readiness.Migrations.Error = "database unavailable"
+		return
+	}
+
+	if s.migrations == nil {
+		readiness.Migrations.Status = CheckSkipped
+		return
+	}
+	pending, err := s.migrations.Pending(ctx)
+	switch {
+	case err != nil:
+		readiness.Migrations.Status = CheckFailed
+		readiness.Migrations.Error = err.Error()
+	case pending > 0:
+		readiness.Migrations.Status = CheckPending
+		readiness.Migrations.Pending = pending
+		readiness.Migrations.Error = fmt.Sprintf("%d migration(s) not applied", pending)
+	}
+}
+
+// ping times a ping of db
+func ping(ctx context.Context, db Pinger) DependencyCheck {
+	start := time.Now()
+	err := db.PingContext(ctx)
+	check := DependencyCheck{Status: CheckOK, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		check.Status = CheckFailed
+		check.Error = err.Error()
+	}
+	return check
+}
+
+func passed(status string) bool {
+	return status == CheckOK || status == CheckSkipped
+}
+
+func hasStaleWorker(workers []health.WorkerStatus) bool {
+	for _, worker := range workers {
+		if worker.Status == health.WorkerStale {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,918 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/1shoukr/linkvault/internal/netguard"
+	"github.com/1shoukr/linkvault/internal/repository"
+	"github.com/1shoukr/linkvault/pkg/merchant"
+	"github.com/1shoukr/linkvault/pkg/utils"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrLinkNotFound is returned when a link doesn't exist or isn't owned by the caller
+	ErrLinkNotFound = errors.New("link not found")
+	// ErrLinkExpired is returned when a link has passed its expiry time or click cap
+	ErrLinkExpired = errors.New("link has expired")
+	// ErrInvalidLinkPassword is returned when a visitor enters the wrong link password
+	ErrInvalidLinkPassword = errors.New("incorrect password")
+	// ErrTooManyAttempts is returned when password attempts for a link are being throttled
+	ErrTooManyAttempts = errors.New("too many attempts, please try again later")
+)
+
+// channelNamePattern restricts channel names to URL-safe slugs
+var channelNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,49}$`)
+
+const (
+	// maxAccessTokenTTL caps how long an owner-issued access token stays valid
+	maxAccessTokenTTL = 30 * 24 * time.Hour
+	// passwordAttemptWindow is the throttling window for link password attempts
+	passwordAttemptWindow = 15 * time.Minute
+)
+
+// LinkInput holds the user-editable fields of a link. Nil fields are left unchanged on update.
+type LinkInput struct {
+	OriginalURL *string   `json:"original_url"`
+	Title       *string   `json:"title"`
+	Description *string   `json:"description"`
+	Category    *string   `json:"category"`
+	Platform    *string   `json:"platform"`
+	Tags        *[]string `json:"tags"`
+
+	// Guardrails; null removes a limit, as does an empty fallback URL
+	StartsAt    Nullable[time.Time] `json:"starts_at"`
+	ExpiresAt   Nullable[time.Time] `json:"expires_at"`
+	MaxClicks   Nullable[int]       `json:"max_clicks"`
+	FallbackURL Nullable[string]    `json:"fallback_url"`
+
+	// Access control; an empty password removes protection
+	Password  *string `json:"password"`
+	IsPrivate *bool   `json:"is_private"`
+
+	// UTM tags; replaces the link's whole template when set
+	UTM *models.UTMTemplate `json:"utm"`
+}
+
+// Nullable is an optional input field that tells "absent" (leave unchanged) apart from an
+// explicit JSON null (clear)
+type Nullable[T any] struct {
+	Set   bool // the field was present, possibly as null
+	Value *T   // nil when the field was null
+}
+
+// UnmarshalJSON records that the field was present; encoding/json calls it for null too
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	n.Value = nil
+	if string(data) == "null" {
+		return nil
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	n.Value = &value
+	return nil
+}
+
+// ChannelInput describes a channel-specific variant of a link
+type ChannelInput struct {
+	Name string             `json:"name" binding:"required"`
+	UTM  models.UTMTemplate `json:"utm"`
+}
+
+// GeoRuleInput describes a single country-to-destination routing rule
+type GeoRuleInput struct {
+	Country        string `json:"country" binding:"required"`
+	DestinationURL string `json:"destination_url" binding:"required"`
+}
+
+// VariantInput describes one weighted destination of an A/B split
+type VariantInput struct {
+	Label          string `json:"label" binding:"required"`
+	DestinationURL string `json:"destination_url" binding:"required"`
+	Weight         int    `json:"weight" binding:"required,min=1"`
+}
+
+// DeviceRuleInput describes a deep-link rule for one device platform
+type DeviceRuleInput struct {
+	Platform    string  `json:"platform" binding:"required"`
+	DeepLinkURL string  `json:"deep_link_url" binding:"required"`
+	FallbackURL *string `json:"fallback_url"`
+}
+
+// VariantStats summarizes how a single A/B variant is performing
+type VariantStats struct {
+	VariantID      uuid.UUID `json:"variant_id"`
+	Label          string    `json:"label"`
+	Weight         int       `json:"weight"`
+	Clicks         int64     `json:"clicks"`
+	UniqueVisitors int64     `json:"unique_visitors"`
+	ClickShare     float64   `json:"click_share"`  // percentage of all variant clicks
+	TargetShare    float64   `json:"target_share"` // percentage implied by the configured weights
+}
+
+// Visitor carries the request attributes redirect rules are evaluated against
+type Visitor struct {
+	Country         string // ISO country code, "" if unknown
+	Platform        string // utils.PlatformIOS, utils.PlatformAndroid or utils.PlatformDesktop
+	StickyVariantID string // variant previously assigned to this visitor, if any
+	Channel         string // channel variant requested via /r/:id/:channel, if any
+}
+
+// MerchantInspection reports what the merchant parser found in a URL
+type MerchantInspection struct {
+	Detected *merchant.Result `json:"detected"`
+	Warnings []string         `json:"warnings"`
+}
+
+// LifecycleResult counts the links moved by a lifecycle pass
+type LifecycleResult struct {
+	Activated int64
+	Expired   int64
+	Archived  int64
+}
+
+// RedirectTarget is the resolved destination for a single visitor
+type RedirectTarget struct {
+	URL         string              // web destination
+	DeepLinkURL string              // app deep link to try before URL, if a device rule matched
+	Variant     *models.LinkVariant // nil unless an A/B variant was served
+}
+
+// LinkService handles business logic for links
+type LinkService struct {
+	linkRepo       repository.LinkStore
+	userRepo       repository.UserStore
+	webhookService *WebhookService
+	tokens         *utils.TokenIssuer
+
+	// Password brute-force throttling: failed attempts per visitor IP on a link
+	visitorAttempts *utils.AttemptLimiter
+
+	// AllowPrivateDestinations accepts destination URLs that resolve to loopback or private addresses
+	AllowPrivateDestinations bool
+}
+
+// NewLinkService creates a new link service
+func NewLinkService(linkRepo repository.LinkStore, userRepo repository.UserStore, webhookService *WebhookService, tokens *utils.TokenIssuer) *LinkService {
+	return &LinkService{
+		linkRepo:        linkRepo,
+		userRepo:        userRepo,
+		webhookService:  webhookService,
+		tokens:          tokens,
+		visitorAttempts: utils.NewAttemptLimiter(5, passwordAttemptWindow),
+	}
+}
+
+// GetLinks retrieves all links owned by a user
+func (s *LinkService) GetLinks(ctx context.Context, userID uuid.UUID) ([]models.Link, error) {
+	return s.linkRepo.GetByUserID(ctx, userID)
+}
+
+// GetLink retrieves a link owned by a user
+func (s *LinkService) GetLink(ctx context.Context, userID, id uuid.UUID) (*models.Link, error) {
+	link, err := s.linkRepo.GetByID(ctx, id)
+	if err != nil || link.UserID != userID {
+		return nil, ErrLinkNotFound
+	}
+	return link, nil
+}
+
+// CreateLink creates a new link for a user
+func (s *LinkService) CreateLink(ctx context.Context, userID uuid.UUID, input LinkInput) (*models.Link, error) {
+	if input.OriginalURL == nil {
+		return nil, errors.New("original_url is required")
+	}
+
+	link := &models.Link{
+		UserID:    userID,
+		IsHealthy: true,
+	}
+	if err := applyLinkInput(link, input); err != nil {
+		return nil, err
+	}
+	if err := s.checkLinkDestinations(ctx, link, input); err != nil {
+		return nil, err
+	}
+	link.Status = lifecycleStatus(link, time.Now())
+
+	if err := s.linkRepo.Create(ctx, link); err != nil {
+		return nil, errors.New("failed to create link")
+	}
+	s.webhookService.Publish(ctx, userID, models.WebhookEventLinkCreated, link)
+	return link, nil
+}
+
+// UpdateLink applies a partial update to a link owned by a user
+func (s *LinkService) UpdateLink(ctx context.Context, userID, id uuid.UUID, input LinkInput) (*models.Link, error) {
+	link, err := s.GetLink(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	previousURL := link.OriginalURL
+	if err := applyLinkInput(link, input); err != nil {
+		return nil, err
+	}
+	if err := s.checkLinkDestinations(ctx, link, input); err != nil {
+		return nil, err
+	}
+	if link.OriginalURL != previousURL {
+		// A new destination hasn't been checked yet; put it at the front of the queue
+		link.NextCheckAt = nil
+	}
+	if link.ArchivedAt == nil {
+		link.Status = lifecycleStatus(link, time.Now())
+	}
+
+	if err := s.linkRepo.Update(ctx, link); err != nil {
+		return nil, errors.New("failed to update link")
+	}
+	return link, nil
+}
+
+// DeleteLink deletes a link owned by a user
+func (s *LinkService) DeleteLink(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.GetLink(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.linkRepo.Delete(ctx, id)
+}
+
+// InspectURL runs merchant detection on a URL and checks it against the user's affiliate tags
+func (s *LinkService) InspectURL(ctx context.Context, userID uuid.UUID, rawURL string) (*MerchantInspection, error) {
+	if err := validateDestinationURL(rawURL); err != nil {
+		return nil, err
+	}
+	result, _ := merchant.Detect(rawURL)
+	return &MerchantInspection{
+		Detected: result,
+		Warnings: s.affiliateWarnings(ctx, userID, result),
+	}, nil
+}
+
+// AffiliateWarnings reports missing or unexpected affiliate tags on a link's destination
+func (s *LinkService) AffiliateWarnings(ctx context.Context, link *models.Link) []string {
+	result, _ := merchant.Detect(link.OriginalURL)
+	return s.affiliateWarnings(ctx, link.UserID, result)
+}
+
+// affiliateWarnings compares a detection result with the tags the user configured for its network
+func (s *LinkService) affiliateWarnings(ctx context.Context, userID uuid.UUID, result *merchant.Result) []string {
+	warnings := []string{}
+	if result == nil {
+		return warnings
+	}
+	if result.ShortLink {
+		return append(warnings, fmt.Sprintf("This is a %s short link; its affiliate tag can't be verified until it is resolved", result.Network))
+	}
+	if result.AffiliateID == "" {
+		return append(warnings, fmt.Sprintf("No %s affiliate tag found in the URL; clicks may not earn commission", result.Network))
+	}
+
+	tags, err := s.userRepo.GetAffiliateTags(ctx, userID)
+	if err != nil {
+		return warnings
+	}
+	var expected []string
+	for _, tag := range tags {
+		if tag.Network != result.Network {
+			continue
+		}
+		if strings.EqualFold(tag.Tag, result.AffiliateID) {
+			return warnings
+		}
+		expected = append(expected, tag.Tag)
+	}
+	if len(expected) > 0 {
+		warnings = append(warnings, fmt.Sprintf("Affiliate tag %q does not match your %s tags (%s)",
+			result.AffiliateID, result.Network, strings.Join(expected, ", ")))
+	}
+	return warnings
+}
+
+// SetGeoRules replaces the country routing rules of a link owned by a user
+func (s *LinkService) SetGeoRules(ctx context.Context, userID, id uuid.UUID, inputs []GeoRuleInput) (*models.Link, error) {
+	if _, err := s.GetLink(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	rules := make([]models.LinkGeoRule, 0, len(inputs))
+	seen := make(map[string]bool, len(inputs))
+	for _, input := range inputs {
+		country := utils.NormalizeCountry(input.Country)
+		if country == "" {
+			return nil, fmt.Errorf("invalid country code %q", input.Country)
+		}
+		if seen[country] {
+			return nil, fmt.Errorf("duplicate rule for country %s", country)
+		}
+		seen[country] = true
+
+		if err := validateDestinationURL(input.DestinationURL); err != nil {
+			return nil, err
+		}
+		if err := s.checkPublicDestination(ctx, input.DestinationURL); err != nil {
+			return nil, err
+		}
+		rules = append(rules, models.LinkGeoRule{
+			Country:        country,
+			DestinationURL: strings.TrimSpace(input.DestinationURL),
+		})
+	}
+
+	if err := s.linkRepo.ReplaceGeoRules(ctx, id, rules); err != nil {
+		return nil, errors.New("failed to save geo rules")
+	}
+	return s.GetLink(ctx, userID, id)
+}
+
+// SetVariants replaces the A/B split destinations of a link owned by a user
+func (s *LinkService) SetVariants(ctx context.Context, userID, id uuid.UUID, inputs []VariantInput) (*models.Link, error) {
+	if _, err := s.GetLink(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	if len(inputs) == 1 {
+		return nil, errors.New("an A/B split needs at least two variants")
+	}
+
+	variants := make([]models.LinkVariant, 0, len(inputs))
+	for _, input := range inputs {
+		if err := validateDestinationURL(input.DestinationURL); err != nil {
+			return nil, err
+		}
+		if err := s.checkPublicDestination(ctx, input.DestinationURL); err != nil {
+			return nil, err
+		}
+		variants = append(variants, models.LinkVariant{
+			Label:          strings.TrimSpace(input.Label),
+			DestinationURL: strings.TrimSpace(input.DestinationURL),
+			Weight:         input.Weight,
+		})
+	}
+
+	if err := s.linkRepo.ReplaceVariants(ctx, id, variants); err != nil {
+		return nil, errors.New("failed to save variants")
+	}
+	return s.GetLink(ctx, userID, id)
+}
+
+// SetDeviceRules replaces the device deep-link rules of a link owned by a user
+func (s *LinkService) SetDeviceRules(ctx context.Context, userID, id uuid.UUID, inputs []DeviceRuleInput) (*models.Link, error) {
+	if _, err := s.GetLink(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	rules := make([]models.LinkDeviceRule, 0, len(inputs))
+	seen := make(map[string]bool, len(inputs))
+	for _, input := range inputs {
+		platform := strings.ToLower(strings.TrimSpace(input.Platform))
+		if !utils.IsValidPlatform(platform) {
+			return nil, fmt.Errorf("invalid platform %q", input.Platform)
+		}
+		if seen[platform] {
+			return nil, fmt.Errorf("duplicate rule for platform %s", platform)
+		}
+		seen[platform] = true
+
+		if err := validateDeepLinkURL(input.DeepLinkURL); err != nil {
+			return nil, err
+		}
+		if isWebURL(input.DeepLinkURL) {
+			if err := s.checkPublicDestination(ctx, input.DeepLinkURL); err != nil {
+				return nil, err
+			}
+		}
+		rule := models.LinkDeviceRule{
+			Platform:    platform,
+			DeepLinkURL: strings.TrimSpace(input.DeepLinkURL),
+		}
+		if input.FallbackURL != nil && strings.TrimSpace(*input.FallbackURL) != "" {
+			if err := validateDestinationURL(*input.FallbackURL); err != nil {
+				return nil, err
+			}
+			if err := s.checkPublicDestination(ctx, *input.FallbackURL); err != nil {
+				return nil, err
+			}
+			fallback := strings.TrimSpace(*input.FallbackURL)
+			rule.FallbackURL = &fallback
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := s.linkRepo.ReplaceDeviceRules(ctx, id, rules); err != nil {
+		return nil, errors.New("failed to save device rules")
+	}
+	return s.GetLink(ctx, userID, id)
+}
+
+// SetChannels replaces the channel variants of a link owned by a user
+func (s *LinkService) SetChannels(ctx context.Context, userID, id uuid.UUID, inputs []ChannelInput) (*models.Link, error) {
+	if _, err := s.GetLink(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	channels := make([]models.LinkChannel, 0, len(inputs))
+	seen := make(map[string]bool, len(inputs))
+	for _, input := range inputs {
+		name := strings.ToLower(strings.TrimSpace(input.Name))
+		if !channelNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid channel name %q: use lowercase letters, digits, '-' or '_'", input.Name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate channel %s", name)
+		}
+		seen[name] = true
+		channels = append(channels, models.LinkChannel{Name: name, UTM: input.UTM})
+	}
+
+	if err := s.linkRepo.ReplaceChannels(ctx, id, channels); err != nil {
+		return nil, errors.New("failed to save channels")
+	}
+	return s.GetLink(ctx, userID, id)
+}
+
+// BuildTaggedURL previews the UTM-tagged destination of a link owned by a user for a channel
+func (s *LinkService) BuildTaggedURL(ctx context.Context, userID, id uuid.UUID, channel string) (string, error) {
+	link, err := s.GetLink(ctx, userID, id)
+	if err != nil {
+		return "", err
+	}
+	return applyUTM(link, link.OriginalURL, Visitor{Channel: channel}), nil
+}
+
+// GetVariantStats compares click-through across the A/B variants of a link owned by a user
+func (s *LinkService) GetVariantStats(ctx context.Context, userID, id uuid.UUID) ([]VariantStats, error) {
+	link, err := s.GetLink(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	clickStats, err := s.linkRepo.GetVariantClickStats(ctx, id)
+	if err != nil {
+		return nil, errors.New("failed to load variant stats")
+	}
+	byVariant := make(map[uuid.UUID]repository.VariantClickStats, len(clickStats))
+	var totalClicks int64
+	for _, cs := range clickStats {
+		byVariant[cs.VariantID] = cs
+		totalClicks += cs.Clicks
+	}
+	var totalWeight int
+	for _, v := range link.Variants {
+		totalWeight += v.Weight
+	}
+
+	stats := make([]VariantStats, 0, len(link.Variants))
+	for _, v := range link.Variants {
+		cs := byVariant[v.ID]
+		vs := VariantStats{
+			VariantID:      v.ID,
+			Label:          v.Label,
+			Weight:         v.Weight,
+			Clicks:         cs.Clicks,
+			UniqueVisitors: cs.UniqueVisitors,
+		}
+		if totalClicks > 0 {
+			vs.ClickShare = float64(cs.Clicks) / float64(totalClicks) * 100
+		}
+		if totalWeight > 0 {
+			vs.TargetShare = float64(v.Weight) / float64(totalWeight) * 100
+		}
+		stats = append(stats, vs)
+	}
+	return stats, nil
+}
+
+// GetPublicLink retrieves a link that is currently live for visitors. Expired links are
+// returned alongside ErrLinkExpired so callers can serve their fallback URL.
+func (s *LinkService) GetPublicLink(ctx context.Context, id uuid.UUID) (*models.Link, error) {
+	link, err := s.linkRepo.GetByID(ctx, id)
+	if err != nil || link.ArchivedAt != nil {
+		return nil, ErrLinkNotFound
+	}
+	now := time.Now()
+	if link.IsExpired(now) {
+		return link, ErrLinkExpired
+	}
+	if link.Status == models.LinkStatusScheduled || !link.IsStarted(now) {
+		return nil, ErrLinkNotFound
+	}
+	return link, nil
+}
+
+// ResolveRedirect looks up an active link and picks the destination for a visitor.
+// The web destination comes from a matching geo rule, else an A/B variant (reusing the
+// visitor's sticky variant when it still exists), else the original URL. A device rule
+// for the visitor's platform then layers an app deep link on top, optionally overriding
+// the web fallback.
+func (s *LinkService) ResolveRedirect(ctx context.Context, id uuid.UUID, visitor Visitor) (*models.Link, *RedirectTarget, error) {
+	link, err := s.GetPublicLink(ctx, id)
+	if err != nil {
+		return link, nil, err
+	}
+
+	target := &RedirectTarget{URL: link.OriginalURL}
+	if rule := findGeoRule(link, visitor.Country); rule != nil {
+		target.URL = rule.DestinationURL
+	} else if variant := PickVariant(link.Variants, visitor.StickyVariantID); variant != nil {
+		target.URL = variant.DestinationURL
+		target.Variant = variant
+	}
+
+	for _, rule := range link.DeviceRules {
+		if rule.Platform != visitor.Platform {
+			continue
+		}
+		target.DeepLinkURL = rule.DeepLinkURL
+		if rule.FallbackURL != nil {
+			target.URL = *rule.FallbackURL
+		}
+		break
+	}
+
+	target.URL = applyUTM(link, target.URL, visitor)
+	if strings.HasPrefix(target.DeepLinkURL, "https://") {
+		target.DeepLinkURL = applyUTM(link, target.DeepLinkURL, visitor)
+	}
+	return link, target, nil
+}
+
+// ClaimClick counts a click against a capped link before it is served, returning
+// ErrLinkExpired once the cap is reached. The check and the increment are a single conditional
+// update, so concurrent redirects can't overshoot max_clicks. Clicks on uncapped links are
+// counted when they are recorded.
+func (s *LinkService) ClaimClick(ctx context.Context, link *models.Link) error {
+	if link.MaxClicks == nil {
+		return nil
+	}
+	claimed, err := s.linkRepo.ClaimClick(ctx, link.ID)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return ErrLinkExpired
+	}
+	return nil
+}
+
+// VerifyLinkPassword checks a visitor's password for a protected link, throttling repeated failures
+func (s *LinkService) VerifyLinkPassword(link *models.Link, password, clientIP string) error {
+	if link.PasswordHash == nil {
+		return ErrInvalidLinkPassword
+	}
+
+	// Only failures count, and only against the visitor making them, so nobody can lock
+	// a link's real visitors out by guessing at it
+	visitorKey := link.ID.String() + "|" + clientIP
+	if s.visitorAttempts.Exceeded(visitorKey) {
+		return ErrTooManyAttempts
+	}
+	if !utils.CheckPasswordHash(password, *link.PasswordHash) {
+		s.visitorAttempts.Allow(visitorKey)
+		return ErrInvalidLinkPassword
+	}
+
+	s.visitorAttempts.Reset(visitorKey)
+	return nil
+}
+
+// IssueAccessToken creates a signed, time-limited token granting access to a protected link owned by a user
+func (s *LinkService) IssueAccessToken(ctx context.Context, userID, id uuid.UUID, ttl time.Duration) (string, time.Time, error) {
+	if _, err := s.GetLink(ctx, userID, id); err != nil {
+		return "", time.Time{}, err
+	}
+	if ttl <= 0 || ttl > maxAccessTokenTTL {
+		return "", time.Time{}, fmt.Errorf("token lifetime must be between 1 second and %s", maxAccessTokenTTL)
+	}
+
+	expiresAt := time.Now().Add(ttl)
+	token, err := s.tokens.GenerateLinkAccessToken(id, ttl)
+	if err != nil {
+		return "", time.Time{}, errors.New("failed to generate access token")
+	}
+	return token, expiresAt, nil
+}
+
+// GrantAccess issues the token a visitor receives after unlocking a protected link
+func (s *LinkService) GrantAccess(linkID uuid.UUID, ttl time.Duration) (string, error) {
+	return s.tokens.GenerateLinkAccessToken(linkID, ttl)
+}
+
+// HasAccess reports whether token is a valid, unexpired access token for the link
+func (s *LinkService) HasAccess(token string, linkID uuid.UUID) bool {
+	return token != "" && s.tokens.ValidateLinkAccessToken(token, linkID) == nil
+}
+
+// TransitionLifecycles activates scheduled links that have started, expires links past their
+// limits, and archives links that have been expired for longer than archiveAfter
+func (s *LinkService) TransitionLifecycles(ctx context.Context, now time.Time, archiveAfter time.Duration) (LifecycleResult, error) {
+	var result LifecycleResult
+	var err error
+	// Expire first so a link whose whole window has already passed never flips to active
+	if result.Expired, err = s.linkRepo.ExpireDue(ctx, now); err != nil {
+		return result, fmt.Errorf("failed to expire links: %w", err)
+	}
+	if result.Activated, err = s.linkRepo.ActivateScheduled(ctx, now); err != nil {
+		return result, fmt.Errorf("failed to activate scheduled links: %w", err)
+	}
+	if archiveAfter > 0 {
+		if result.Archived, err = s.linkRepo.ArchiveExpired(ctx, now.Add(-archiveAfter), now); err != nil {
+			return result, fmt.Errorf("failed to archive expired links: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// RecordClick stores a click against a link and notifies the owner's webhooks
+func (s *LinkService) RecordClick(ctx context.Context, link *models.Link, click *models.Click) error {
+	// A capped link's click was already counted by ClaimClick
+	if err := s.linkRepo.RecordClick(ctx, click, link.MaxClicks != nil); err != nil {
+		return err
+	}
+	s.webhookService.Publish(ctx, link.UserID, models.WebhookEventClickRecorded, ClickEventData{
+		ClickID:   click.ID,
+		LinkID:    click.LinkID,
+		ClickedAt: click.ClickedAt,
+		Country:   click.Country,
+		Referrer:  click.Referrer,
+		VariantID: click.VariantID,
+	})
+	return nil
+}
+
+// findGeoRule returns the geo rule matching country, or nil
+func findGeoRule(link *models.Link, country string) *models.LinkGeoRule {
+	if country == "" {
+		return nil
+	}
+	for i := range link.GeoRules {
+		if link.GeoRules[i].Country == country {
+			return &link.GeoRules[i]
+		}
+	}
+	return nil
+}
+
+// applyUTM merges the effective UTM template (user defaults < link < channel) into destination
+// without overriding query parameters the destination already has
+func applyUTM(link *models.Link, destination string, visitor Visitor) string {
+	utm := link.User.DefaultUTM.Overlay(link.UTM)
+	if channel := findChannel(link, visitor.Channel); channel != nil {
+		utm = utm.Overlay(channel.UTM)
+	}
+
+	templates := utm.Params()
+	if len(templates) == 0 {
+		return destination
+	}
+
+	vars := map[string]string{
+		"link_id": link.ID.String(),
+		"channel": strings.ToLower(visitor.Channel),
+		"country": strings.ToLower(visitor.Country),
+		"device":  visitor.Platform,
+	}
+	if link.Platform != nil {
+		vars["platform"] = *link.Platform
+	}
+	if link.Category != nil {
+		vars["category"] = *link.Category
+	}
+	if link.Title != nil {
+		vars["title"] = *link.Title
+	}
+
+	params := make(map[string]string, len(templates))
+	for key, template := range templates {
+		params[key] = utils.ExpandTemplate(template, vars)
+	}
+
+	tagged, err := utils.MergeQueryParams(destination, params)
+	if err != nil {
+		return destination
+	}
+	return tagged
+}
+
+// findChannel returns the channel variant with the given name, or nil
+func findChannel(link *models.Link, name string) *models.LinkChannel {
+	if name == "" {
+		return nil
+	}
+	name = strings.ToLower(name)
+	for i := range link.Channels {
+		if link.Channels[i].Name == name {
+			return &link.Channels[i]
+		}
+	}
+	return nil
+}
+
+// PickVariant returns the sticky variant if it still exists, otherwise a weighted random choice.
+// It returns nil when there are no variants.
+func PickVariant(variants []models.LinkVariant, stickyVariantID string) *models.LinkVariant {
+	if len(variants) == 0 {
+		return nil
+	}
+	if stickyID, err := uuid.Parse(stickyVariantID); err == nil {
+		for i := range variants {
+			if variants[i].ID == stickyID {
+				return &variants[i]
+			}
+		}
+	}
+
+	var totalWeight int
+	for _, v := range variants {
+		totalWeight += v.Weight
+	}
+	if totalWeight <= 0 {
+		return &variants[0]
+	}
+	n := rand.IntN(totalWeight)
+	for i := range variants {
+		n -= variants[i].Weight
+		if n < 0 {
+			return &variants[i]
+		}
+	}
+	return &variants[len(variants)-1]
+}
+
+// applyLinkInput copies the set fields of input onto link
+func applyLinkInput(link *models.Link, input LinkInput) error {
+	if input.OriginalURL != nil {
+		if err := validateDestinationURL(*input.OriginalURL); err != nil {
+			return err
+		}
+		link.OriginalURL = strings.TrimSpace(*input.OriginalURL)
+		applyMerchantDetection(link, input.Platform == nil)
+	}
+	if input.Title != nil {
+		link.Title = input.Title
+	}
+	if input.Description != nil {
+		link.Description = input.Description
+	}
+	if input.Category != nil {
+		link.Category = input.Category
+	}
+	if input.Platform != nil {
+		link.Platform = input.Platform
+	}
+	if input.Tags != nil {
+		link.Tags = *input.Tags
+	}
+	if input.StartsAt.Set {
+		link.StartsAt = input.StartsAt.Value
+	}
+	if input.ExpiresAt.Set {
+		link.ExpiresAt = input.ExpiresAt.Value
+	}
+	if link.StartsAt != nil && link.ExpiresAt != nil && !link.ExpiresAt.After(*link.StartsAt) {
+		return errors.New("expires_at must be after starts_at")
+	}
+	if input.MaxClicks.Set {
+		if input.MaxClicks.Value != nil && *input.MaxClicks.Value < 1 {
+			return errors.New("max_clicks must be at least 1")
+		}
+		link.MaxClicks = input.MaxClicks.Value
+	}
+	if input.FallbackURL.Set {
+		link.FallbackURL = nil
+		if input.FallbackURL.Value != nil && strings.TrimSpace(*input.FallbackURL.Value) != "" {
+			if err := validateDestinationURL(*input.FallbackURL.Value); err != nil {
+				return err
+			}
+			fallback := strings.TrimSpace(*input.FallbackURL.Value)
+			link.FallbackURL = &fallback
+		}
+	}
+	if input.Password != nil {
+		if *input.Password == "" {
+			link.PasswordHash = nil
+		} else {
+			if len(*input.Password) < 4 {
+				return errors.New("password must be at least 4 characters")
+			}
+			hashedPassword, err := utils.HashPassword(*input.Password)
+			if err != nil {
+				return errors.New("failed to hash password")
+			}
+			link.PasswordHash = &hashedPassword
+		}
+		link.HasPassword = link.PasswordHash != nil
+	}
+	if input.IsPrivate != nil {
+		link.IsPrivate = *input.IsPrivate
+	}
+	if input.UTM != nil {
+		link.UTM = *input.UTM
+	}
+	return nil
+}
+
+// applyMerchantDetection records the detected affiliate network and ID for a link's destination,
+// also setting Platform when setPlatform is true
+func applyMerchantDetection(link *models.Link, setPlatform bool) {
+	result, ok := merchant.Detect(link.OriginalURL)
+	if !ok {
+		link.AffiliateNetwork = nil
+		link.AffiliateID = nil
+		return
+	}
+
+	network := result.Network
+	link.AffiliateNetwork = &network
+	link.AffiliateID = nil
+	if result.AffiliateID != "" {
+		affiliateID := result.AffiliateID
+		link.AffiliateID = &affiliateID
+	}
+	if setPlatform {
+		link.Platform = &network
+	}
+}
+
+// lifecycleStatus derives the status a link should have at the given time
+func lifecycleStatus(link *models.Link, now time.Time) string {
+	switch {
+	case link.IsPastLimits(now):
+		return models.LinkStatusExpired
+	case !link.IsStarted(now):
+		return models.LinkStatusScheduled
+	default:
+		return models.LinkStatusActive
+	}
+}
+
+// checkLinkDestinations refuses private addresses among the URLs input set on link
+func (s *LinkService) checkLinkDestinations(ctx context.Context, link *models.Link, input LinkInput) error {
+	if input.OriginalURL != nil {
+		if err := s.checkPublicDestination(ctx, link.OriginalURL); err != nil {
+			return err
+		}
+	}
+	if input.FallbackURL.Set && link.FallbackURL != nil {
+		return s.checkPublicDestination(ctx, *link.FallbackURL)
+	}
+	return nil
+}
+
+// checkPublicDestination refuses a destination on loopback, private or other non-public
+// addresses, which the health checker would otherwise fetch from inside the server's network.
+// Hosts that don't resolve yet are accepted; the checker's transport refuses them if they later
+// resolve to a private address.
+func (s *LinkService) checkPublicDestination(ctx context.Context, raw string) error {
+	if s.AllowPrivateDestinations {
+		return nil
+	}
+	if err := netguard.CheckURL(ctx, strings.TrimSpace(raw)); errors.Is(err, netguard.ErrPrivateAddress) {
+		return fmt.Errorf("invalid destination URL %q: %w", raw, err)
+	}
+	return nil
+}
+
+// isWebURL reports whether raw is an http(s) URL rather than an app scheme
+func isWebURL(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	return err == nil && (strings.EqualFold(u.Scheme, "http") || strings.EqualFold(u.Scheme, "https"))
+}
+
+// validateDestinationURL ensures a destination is an absolute http(s) URL
+func validateDestinationURL(raw string) error {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid destination URL %q", raw)
+	}
+	return nil
+}
+
+// validateDeepLinkURL accepts https universal links and custom app schemes,
+// rejecting schemes that would execute in the browser
+func validateDeepLinkURL(raw string) error {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Scheme == "" || u.Scheme == "http" {
+		return fmt.Errorf("invalid deep link URL %q", raw)
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "javascript", "data", "vbscript", "file", "blob":
+		return fmt.Errorf("invalid deep link URL %q", raw)
+	case "https":
+		if u.Host == "" {
+			return fmt.Errorf("invalid deep link URL %q", raw)
+		}
+	}
+	return nil
+}
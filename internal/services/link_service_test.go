@@ -0,0 +1,189 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/1shoukr/linkvault/internal/netguard"
+	"github.com/1shoukr/linkvault/internal/repository/memory"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestApplyLinkInputGuardrails(t *testing.T) {
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	maxClicks := 10
+	fallback := "https://example.com/sold-out"
+
+	tests := []struct {
+		name    string
+		body    string
+		want    func(*models.Link) bool
+		wantErr bool
+	}{
+		{
+			name: "absent fields are unchanged",
+			body: `{"title":"x"}`,
+			want: func(l *models.Link) bool {
+				return l.ExpiresAt != nil && l.MaxClicks != nil && l.FallbackURL != nil
+			},
+		},
+		{
+			name: "null clears limits",
+			body: `{"expires_at":null,"max_clicks":null,"fallback_url":null}`,
+			want: func(l *models.Link) bool {
+				return l.ExpiresAt == nil && l.MaxClicks == nil && l.FallbackURL == nil
+			},
+		},
+		{
+			name: "empty fallback URL clears it",
+			body: `{"fallback_url":""}`,
+			want: func(l *models.Link) bool { return l.FallbackURL == nil },
+		},
+		{
+			name: "values replace limits",
+			body: `{"max_clicks":3,"fallback_url":" https://example.com/other "}`,
+			want: func(l *models.Link) bool {
+				return *l.MaxClicks == 3 && *l.FallbackURL == "https://example.com/other"
+			},
+		},
+		{name: "zero cap is rejected", body: `{"max_clicks":0}`, wantErr: true},
+		{name: "invalid fallback is rejected", body: `{"fallback_url":"ftp://example.com"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link := &models.Link{
+				OriginalURL: "https://example.com/product",
+				ExpiresAt:   &expires,
+				MaxClicks:   &maxClicks,
+				FallbackURL: &fallback,
+			}
+			var input LinkInput
+			if err := json.Unmarshal([]byte(tt.body), &input); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+
+			err := applyLinkInput(link, input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("applyLinkInput succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyLinkInput: %v", err)
+			}
+			if !tt.want(link) {
+				t.Errorf("unexpected link after %s: expires_at=%v max_clicks=%v fallback_url=%v",
+					tt.body, link.ExpiresAt, link.MaxClicks, link.FallbackURL)
+			}
+		})
+	}
+}
+
+func TestLinkDestinationsMustBePublic(t *testing.T) {
+	db := memory.NewDB()
+	users, links := memory.NewUserRepository(db), memory.NewLinkRepository(db)
+	user := &models.User{Email: "destinations@example.com"}
+	if err := users.Create(t.Context(), user); err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	s := NewLinkService(links, users, NewWebhookService(memory.NewWebhookRepository(db), time.Second, nil), nil)
+
+	private := "http://127.0.0.1:8080/admin"
+	if _, err := s.CreateLink(t.Context(), user.ID, LinkInput{OriginalURL: &private}); !errors.Is(err, netguard.ErrPrivateAddress) {
+		t.Fatalf("CreateLink to loopback = %v, want ErrPrivateAddress", err)
+	}
+	public := "https://93.184.216.34/product"
+	link, err := s.CreateLink(t.Context(), user.ID, LinkInput{OriginalURL: &public})
+	if err != nil {
+		t.Fatalf("CreateLink: %v", err)
+	}
+
+	metadata := "http://169.254.169.254/latest/meta-data/"
+	saves := map[string]func() error{
+		"geo rule": func() error {
+			_, err := s.SetGeoRules(t.Context(), user.ID, link.ID, []GeoRuleInput{{Country: "DE", DestinationURL: "http://10.0.0.5/"}})
+			return err
+		},
+		"variant": func() error {
+			_, err := s.SetVariants(t.Context(), user.ID, link.ID, []VariantInput{
+				{Label: "a", DestinationURL: public, Weight: 1},
+				{Label: "b", DestinationURL: metadata, Weight: 1},
+			})
+			return err
+		},
+		"device fallback": func() error {
+			fallback := "http://[::1]/"
+			_, err := s.SetDeviceRules(t.Context(), user.ID, link.ID, []DeviceRuleInput{{Platform: "ios", DeepLinkURL: "app://product", FallbackURL: &fallback}})
+			return err
+		},
+		"universal link": func() error {
+			_, err := s.SetDeviceRules(t.Context(), user.ID, link.ID, []DeviceRuleInput{{Platform: "ios", DeepLinkURL: "https://192.168.1.1/app"}})
+			return err
+		},
+		"expiry fallback": func() error {
+			_, err := s.UpdateLink(t.Context(), user.ID, link.ID, LinkInput{FallbackURL: Nullable[string]{Set: true, Value: &metadata}})
+			return err
+		},
+	}
+	for name, save := range saves {
+		if err := save(); !errors.Is(err, netguard.ErrPrivateAddress) {
+			t.Errorf("saving a private %s = %v, want ErrPrivateAddress", name, err)
+		}
+	}
+
+	// An existing private destination is refused by on-demand checks too
+	stored, err := links.GetByID(t.Context(), link.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	stored.OriginalURL = private
+	if err := links.Update(t.Context(), stored); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	checks := NewCheckService(links, nil, nil, nil, nil, CheckSchedule{})
+	if _, err := checks.CheckUserLink(t.Context(), user.ID, link.ID); !errors.Is(err, netguard.ErrPrivateAddress) {
+		t.Fatalf("CheckUserLink on a loopback destination = %v, want ErrPrivateAddress", err)
+	}
+
+	s.AllowPrivateDestinations = true
+	if _, err := s.CreateLink(t.Context(), user.ID, LinkInput{OriginalURL: &private}); err != nil {
+		t.Fatalf("CreateLink to loopback with AllowPrivateDestinations: %v", err)
+	}
+}
+
+func TestLinkPasswordThrottlesOnlyFailingVisitors(t *testing.T) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte("open sesame"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("hashing the password: %v", err)
+	}
+	hash := string(hashed)
+	link := &models.Link{ID: uuid.New(), PasswordHash: &hash}
+	s := NewLinkService(nil, nil, nil, nil)
+
+	// Twice the old per-link cap in failures, spread over clients that each use up their own
+	for i := range 20 {
+		ip := fmt.Sprintf("203.0.113.%d", i)
+		for range 5 {
+			if err := s.VerifyLinkPassword(link, "guess", ip); !errors.Is(err, ErrInvalidLinkPassword) {
+				t.Fatalf("wrong password from %s = %v, want ErrInvalidLinkPassword", ip, err)
+			}
+		}
+	}
+	if err := s.VerifyLinkPassword(link, "open sesame", "203.0.113.0"); !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("sixth attempt from a failing client = %v, want ErrTooManyAttempts", err)
+	}
+
+	// Successes don't count, for a visitor who keeps coming back
+	for i := range 10 {
+		if err := s.VerifyLinkPassword(link, "open sesame", "198.51.100.7"); err != nil {
+			t.Fatalf("correct password, attempt %d from a fresh client = %v, want nil", i+1, err)
+		}
+	}
+}
@@ -0,0 +1,387 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/1shoukr/linkvault/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultUptimeWindow is the report window when the caller doesn't give one
+	defaultUptimeWindow = 30 * 24 * time.Hour
+	// maxUptimeWindow caps how far back a single report may reach
+	maxUptimeWindow = 366 * 24 * time.Hour
+	// maxReportIncidents caps the incident list of a report; totals still count every incident
+	maxReportIncidents = 500
+	// maxRollupDaysPerPass bounds how many days one rollup pass processes while catching up
+	maxRollupDaysPerPass = 31
+	// oneDay is the rollup granularity; rollup days are UTC
+	oneDay = 24 * time.Hour
+)
+
+// UptimeStats summarizes health checks and incidents over a window
+type UptimeStats struct {
+	Checks          int64    `json:"checks"`
+	HealthyChecks   int64    `json:"healthy_checks"`
+	UptimePercent   *float64 `json:"uptime_percent"` // share of healthy checks; nil without checks
+	ResponseTimeP50 *int     `json:"response_time_p50_ms"`
+	ResponseTimeP95 *int     `json:"response_time_p95_ms"`
+	IncidentCount   int      `json:"incident_count"`
+	DowntimeSeconds int64    `json:"downtime_seconds"` // incident time inside the window
+	MTTRSeconds     *int64   `json:"mttr_seconds"`     // mean duration of incidents resolved in the window
+}
+
+// IncidentReport is an incident with its duration
+type IncidentReport struct {
+	models.LinkIncident
+	DurationSeconds int64 `json:"duration_seconds"`
+	Ongoing         bool  `json:"ongoing"`
+}
+
+// LinkUptime is one link's row in a user-wide report
+type LinkUptime struct {
+	LinkID      uuid.UUID `json:"link_id"`
+	Title       *string   `json:"title"`
+	OriginalURL string    `json:"original_url"`
+	UptimeStats
+}
+
+// UptimeReport summarizes uptime for a link or for all of a user's links
+type UptimeReport struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	UptimeStats
+	Incidents []IncidentReport `json:"incidents"`
+	Links     []LinkUptime     `json:"links,omitempty"` // per-link breakdown, worst first; user reports only
+}
+
+// checkTally accumulates check counts and response times
+type checkTally struct {
+	checks  int64
+	healthy int64
+	times   models.ResponseHistogram
+}
+
+// UptimeService tracks incidents and computes uptime/SLA reports from check history
+type UptimeService struct {
+	uptimeRepo repository.UptimeStore
+	linkRepo   repository.LinkStore
+}
+
+// NewUptimeService creates a new uptime service
+func NewUptimeService(uptimeRepo repository.UptimeStore, linkRepo repository.LinkStore) *UptimeService {
+	return &UptimeService{
+		uptimeRepo: uptimeRepo,
+		linkRepo:   linkRepo,
+	}
+}
+
+// TrackIncident opens an incident when a link fails a check and closes it on the next healthy one
+func (s *UptimeService) TrackIncident(ctx context.Context, link *models.Link, healthy bool, cause string, at time.Time) {
+	var err error
+	if healthy {
+		err = s.uptimeRepo.CloseIncidents(ctx, link.ID, at)
+	} else {
+		incident := &models.LinkIncident{LinkID: link.ID, UserID: link.UserID, StartedAt: at}
+		if cause != "" {
+			incident.Cause = &cause
+		}
+		err = s.uptimeRepo.OpenIncident(ctx, incident)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to track incident", "link_id", link.ID, "error", err)
+	}
+}
+
+// GetLinkReport computes the uptime report of a user's link over [from, to)
+func (s *UptimeService) GetLinkReport(ctx context.Context, userID, linkID uuid.UUID, from, to *time.Time) (*UptimeReport, error) {
+	link, err := s.linkRepo.GetByID(ctx, linkID)
+	if err != nil || link.UserID != userID {
+		return nil, ErrLinkNotFound
+	}
+
+	start, end, err := uptimeWindow(from, to, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	scope := repository.UptimeScope{UserID: userID, LinkID: &linkID}
+	tallies, incidents, err := s.collect(ctx, scope, start, end)
+	if err != nil {
+		return nil, errors.New("failed to compute uptime")
+	}
+
+	report := &UptimeReport{From: start, To: end}
+	report.UptimeStats = buildUptimeStats(tallies[linkID], incidents, start, end)
+	report.Incidents = incidentReports(incidents, end)
+	return report, nil
+}
+
+// GetUserReport computes the uptime report across all of a user's links over [from, to),
+// with a per-link breakdown
+func (s *UptimeService) GetUserReport(ctx context.Context, userID uuid.UUID, from, to *time.Time) (*UptimeReport, error) {
+	start, end, err := uptimeWindow(from, to, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	links, err := s.linkRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve links")
+	}
+	tallies, incidents, err := s.collect(ctx, repository.UptimeScope{UserID: userID}, start, end)
+	if err != nil {
+		return nil, errors.New("failed to compute uptime")
+	}
+
+	total := &checkTally{times: models.NewResponseHistogram()}
+	for _, tally := range tallies {
+		total.checks += tally.checks
+		total.healthy += tally.healthy
+		total.times.Merge(tally.times)
+	}
+	incidentsByLink := make(map[uuid.UUID][]models.LinkIncident)
+	for _, incident := range incidents {
+		incidentsByLink[incident.LinkID] = append(incidentsByLink[incident.LinkID], incident)
+	}
+
+	report := &UptimeReport{From: start, To: end}
+	report.UptimeStats = buildUptimeStats(total, incidents, start, end)
+	report.Incidents = incidentReports(incidents, end)
+	for _, link := range links {
+		tally, checked := tallies[link.ID]
+		if link.ArchivedAt != nil && !checked {
+			continue
+		}
+		report.Links = append(report.Links, LinkUptime{
+			LinkID:      link.ID,
+			Title:       link.Title,
+			OriginalURL: link.OriginalURL,
+			UptimeStats: buildUptimeStats(tally, incidentsByLink[link.ID], start, end),
+		})
+	}
+	sort.SliceStable(report.Links, func(i, j int) bool {
+		a, b := report.Links[i].UptimePercent, report.Links[j].UptimePercent
+		return a != nil && (b == nil || *a < *b)
+	})
+	return report, nil
+}
+
+// RollupDays aggregates completed UTC days of check history into daily rollups. The most
+// recent rolled-up day is redone to pick up checks that finished after its last pass.
+func (s *UptimeService) RollupDays(ctx context.Context, now time.Time) (int, error) {
+	start, err := s.uptimeRepo.GetLatestRollupDay(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if start == nil {
+		if start, err = s.uptimeRepo.GetEarliestCheckTime(ctx); err != nil || start == nil {
+			return 0, err
+		}
+	}
+
+	today := startOfDay(now)
+	rolled := 0
+	for d := startOfDay(*start); d.Before(today) && rolled < maxRollupDaysPerPass; d = d.Add(oneDay) {
+		if err := s.rollupDay(ctx, d); err != nil {
+			return rolled, err
+		}
+		rolled++
+	}
+	return rolled, nil
+}
+
+// rollupDay builds and stores the rollups of every link checked on the given day
+func (s *UptimeService) rollupDay(ctx context.Context, d time.Time) error {
+	samples, err := s.uptimeRepo.GetAllSamples(ctx, d, d.Add(oneDay))
+	if err != nil {
+		return err
+	}
+
+	rollups := make(map[uuid.UUID]*models.LinkCheckRollup)
+	for _, sample := range samples {
+		rollup, ok := rollups[sample.LinkID]
+		if !ok {
+			rollup = &models.LinkCheckRollup{
+				LinkID:        sample.LinkID,
+				UserID:        sample.UserID,
+				Day:           d,
+				ResponseTimes: models.NewResponseHistogram(),
+			}
+			rollups[sample.LinkID] = rollup
+		}
+		rollup.Checks++
+		if sample.IsHealthy {
+			rollup.HealthyChecks++
+		}
+		if sample.ResponseTime != nil {
+			rollup.ResponseTimes.Add(*sample.ResponseTime)
+		}
+	}
+
+	batch := make([]models.LinkCheckRollup, 0, len(rollups))
+	for _, rollup := range rollups {
+		batch = append(batch, *rollup)
+	}
+	return s.uptimeRepo.UpsertRollups(ctx, batch)
+}
+
+// collect tallies checks per link over [from, to), reading whole days from rollups and the
+// partial days at either edge (and anything not yet rolled up) from raw history
+func (s *UptimeService) collect(ctx context.Context, scope repository.UptimeScope, from, to time.Time) (map[uuid.UUID]*checkTally, []models.LinkIncident, error) {
+	tallies := make(map[uuid.UUID]*checkTally)
+	tallyFor := func(linkID uuid.UUID) *checkTally {
+		tally, ok := tallies[linkID]
+		if !ok {
+			tally = &checkTally{times: models.NewResponseHistogram()}
+			tallies[linkID] = tally
+		}
+		return tally
+	}
+	addSamples := func(start, end time.Time) error {
+		if !start.Before(end) {
+			return nil
+		}
+		samples, err := s.uptimeRepo.GetSamples(ctx, scope, start, end)
+		if err != nil {
+			return err
+		}
+		for _, sample := range samples {
+			tally := tallyFor(sample.LinkID)
+			tally.checks++
+			if sample.IsHealthy {
+				tally.healthy++
+			}
+			if sample.ResponseTime != nil {
+				tally.times.Add(*sample.ResponseTime)
+			}
+		}
+		return nil
+	}
+
+	fullFrom, fullTo := startOfDay(from), startOfDay(to)
+	if fullFrom.Before(from) {
+		fullFrom = fullFrom.Add(oneDay)
+	}
+	latest, err := s.uptimeRepo.GetLatestRollupDay(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if latest == nil {
+		fullTo = fullFrom
+	} else if boundary := startOfDay(*latest).Add(oneDay); boundary.Before(fullTo) {
+		fullTo = boundary
+	}
+
+	if fullFrom.Before(fullTo) {
+		rollups, err := s.uptimeRepo.GetRollups(ctx, scope, fullFrom, fullTo)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, rollup := range rollups {
+			tally := tallyFor(rollup.LinkID)
+			tally.checks += rollup.Checks
+			tally.healthy += rollup.HealthyChecks
+			tally.times.Merge(rollup.ResponseTimes)
+		}
+		if err := addSamples(from, fullFrom); err != nil {
+			return nil, nil, err
+		}
+		if err := addSamples(fullTo, to); err != nil {
+			return nil, nil, err
+		}
+	} else if err := addSamples(from, to); err != nil {
+		return nil, nil, err
+	}
+
+	incidents, err := s.uptimeRepo.GetIncidents(ctx, scope, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tallies, incidents, nil
+}
+
+// buildUptimeStats combines a check tally (nil if nothing was checked) with the incidents
+// overlapping [from, to)
+func buildUptimeStats(tally *checkTally, incidents []models.LinkIncident, from, to time.Time) UptimeStats {
+	var stats UptimeStats
+	if tally != nil && tally.checks > 0 {
+		stats.Checks = tally.checks
+		stats.HealthyChecks = tally.healthy
+		uptime := float64(tally.healthy) / float64(tally.checks) * 100
+		stats.UptimePercent = &uptime
+		stats.ResponseTimeP50 = tally.times.Percentile(50)
+		stats.ResponseTimeP95 = tally.times.Percentile(95)
+	}
+
+	var resolved int64
+	var recovery time.Duration
+	for i := range incidents {
+		incident := &incidents[i]
+		stats.IncidentCount++
+
+		start, end := incident.StartedAt, to
+		if incident.EndedAt != nil && incident.EndedAt.Before(end) {
+			end = *incident.EndedAt
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(start) {
+			stats.DowntimeSeconds += int64(end.Sub(start).Seconds())
+		}
+
+		if incident.EndedAt != nil && !incident.EndedAt.Before(from) && incident.EndedAt.Before(to) {
+			resolved++
+			recovery += incident.Duration(to)
+		}
+	}
+	if resolved > 0 {
+		mttr := int64(recovery.Seconds()) / resolved
+		stats.MTTRSeconds = &mttr
+	}
+	return stats
+}
+
+// incidentReports converts incidents to report rows, newest first and capped
+func incidentReports(incidents []models.LinkIncident, now time.Time) []IncidentReport {
+	reports := make([]IncidentReport, 0, min(len(incidents), maxReportIncidents))
+	for i := len(incidents) - 1; i >= 0 && len(reports) < maxReportIncidents; i-- {
+		incident := incidents[i]
+		reports = append(reports, IncidentReport{
+			LinkIncident:    incident,
+			DurationSeconds: int64(incident.Duration(now).Seconds()),
+			Ongoing:         incident.EndedAt == nil,
+		})
+	}
+	return reports
+}
+
+// uptimeWindow resolves an optional [from, to) window, defaulting to the last 30 days
+func uptimeWindow(from, to *time.Time, now time.Time) (time.Time, time.Time, error) {
+	end := now
+	if to != nil && to.Before(now) {
+		end = *to
+	}
+	start := end.Add(-defaultUptimeWindow)
+	if from != nil {
+		start = *from
+	}
+
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, errors.New("from must be before to")
+	}
+	if end.Sub(start) > maxUptimeWindow {
+		return time.Time{}, time.Time{}, errors.New("window cannot exceed 366 days")
+	}
+	return start.UTC(), end.UTC(), nil
+}
+
+// startOfDay truncates t to midnight UTC
+func startOfDay(t time.Time) time.Time {
+	return t.UTC().Truncate(oneDay)
+}
@@ -1,32 +1,42 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/1shoukr/linkvault/internal/models"
 	"github.com/1shoukr/linkvault/internal/repository"
+	"github.com/1shoukr/linkvault/pkg/merchant"
 	"github.com/google/uuid"
 )
 
+// AffiliateTagInput describes an affiliate tag a user expects on links for a network
+type AffiliateTagInput struct {
+	Network string `json:"network" binding:"required"`
+	Tag     string `json:"tag" binding:"required"`
+}
+
 // UserService handles business logic for users
 type UserService struct {
-	userRepo *repository.UserRepository
+	userRepo repository.UserStore
 }
 
 // NewUserService creates a new user service
-func NewUserService(userRepo *repository.UserRepository) *UserService {
+func NewUserService(userRepo repository.UserStore) *UserService {
 	return &UserService{
 		userRepo: userRepo,
 	}
 }
 
 // GetUserByID retrieves a user by their ID
-func (s *UserService) GetUserByID(id uuid.UUID) (*models.User, error) {
+func (s *UserService) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	if id == uuid.Nil {
 		return nil, errors.New("invalid user ID")
 	}
 
-	user, err := s.userRepo.GetByID(id)
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -35,12 +45,12 @@ func (s *UserService) GetUserByID(id uuid.UUID) (*models.User, error) {
 }
 
 // GetUserByEmail retrieves a user by their email
-func (s *UserService) GetUserByEmail(email string) (*models.User, error) {
+func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	if email == "" {
 		return nil, errors.New("email is required")
 	}
 
-	user, err := s.userRepo.GetByEmail(email)
+	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		return nil, err
 	}
@@ -48,9 +58,54 @@ func (s *UserService) GetUserByEmail(email string) (*models.User, error) {
 	return user, nil
 }
 
+// UpdateUTMDefaults replaces a user's default UTM template
+func (s *UserService) UpdateUTMDefaults(ctx context.Context, id uuid.UUID, utm models.UTMTemplate) (*models.User, error) {
+	user, err := s.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	user.DefaultUTM = utm
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, errors.New("failed to update UTM defaults")
+	}
+
+	return user, nil
+}
+
+// GetAffiliateTags retrieves the affiliate tags configured by a user
+func (s *UserService) GetAffiliateTags(ctx context.Context, id uuid.UUID) ([]models.AffiliateTag, error) {
+	return s.userRepo.GetAffiliateTags(ctx, id)
+}
+
+// SetAffiliateTags replaces the affiliate tags configured by a user
+func (s *UserService) SetAffiliateTags(ctx context.Context, id uuid.UUID, inputs []AffiliateTagInput) ([]models.AffiliateTag, error) {
+	tags := make([]models.AffiliateTag, 0, len(inputs))
+	seen := make(map[string]bool, len(inputs))
+	for _, input := range inputs {
+		network := strings.ToLower(strings.TrimSpace(input.Network))
+		if !merchant.IsKnownNetwork(network) {
+			return nil, fmt.Errorf("unknown network %q, expected one of: %s", input.Network, strings.Join(merchant.Networks, ", "))
+		}
+		tag := strings.TrimSpace(input.Tag)
+		key := network + "|" + strings.ToLower(tag)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		tags = append(tags, models.AffiliateTag{Network: network, Tag: tag})
+	}
+
+	if err := s.userRepo.ReplaceAffiliateTags(ctx, id, tags); err != nil {
+		return nil, errors.New("failed to save affiliate tags")
+	}
+
+	return s.userRepo.GetAffiliateTags(ctx, id)
+}
+
 // GetAllUsers retrieves all users
-func (s *UserService) GetAllUsers() ([]models.User, error) {
-	users, err := s.userRepo.GetAll()
+func (s *UserService) GetAllUsers(ctx context.Context) ([]models.User, error) {
+	users, err := s.userRepo.GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
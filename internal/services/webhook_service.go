@@ -0,0 +1,400 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/metrics"
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/1shoukr/linkvault/internal/netguard"
+	"github.com/1shoukr/linkvault/internal/repository"
+	"github.com/1shoukr/linkvault/pkg/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	// webhookMaxAttempts is how many times a delivery is tried before it is marked failed
+	webhookMaxAttempts = 8
+	// webhookBaseBackoff is the delay before the first retry; it doubles after each failure
+	webhookBaseBackoff = 30 * time.Second
+	// webhookMaxBackoff caps the delay between retries
+	webhookMaxBackoff = 6 * time.Hour
+	// webhookDeliveryLogLimit is how many recent deliveries the delivery log returns
+	webhookDeliveryLogLimit = 50
+)
+
+var (
+	// ErrWebhookNotFound is returned when a webhook endpoint doesn't exist or isn't owned by the caller
+	ErrWebhookNotFound = errors.New("webhook not found")
+	// ErrWebhookDeliveryNotFound is returned when a delivery doesn't belong to the endpoint
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+// WebhookEndpointInput holds the fields accepted when creating or updating a webhook endpoint.
+// Nil fields are left unchanged on update.
+type WebhookEndpointInput struct {
+	URL         *string  `json:"url"`
+	Description *string  `json:"description"`
+	Events      []string `json:"events"`
+	IsActive    *bool    `json:"is_active"`
+}
+
+// WebhookEvent is the JSON envelope posted to webhook endpoints
+type WebhookEvent struct {
+	ID        uuid.UUID   `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// ClickEventData is the payload of click.recorded events. Visitor IPs and user agents are withheld.
+type ClickEventData struct {
+	ClickID   uuid.UUID  `json:"click_id"`
+	LinkID    uuid.UUID  `json:"link_id"`
+	ClickedAt time.Time  `json:"clicked_at"`
+	Country   *string    `json:"country"`
+	Referrer  *string    `json:"referrer"`
+	VariantID *uuid.UUID `json:"variant_id"`
+}
+
+// LinkHealthEventData is the payload of link.down and link.recovered events
+type LinkHealthEventData struct {
+	LinkID      uuid.UUID `json:"link_id"`
+	Title       *string   `json:"title"`
+	OriginalURL string    `json:"original_url"`
+	Message     string    `json:"message"`
+	At          time.Time `json:"at"`
+}
+
+// WebhookService manages user webhook endpoints and delivers queued events to them
+type WebhookService struct {
+	webhookRepo repository.WebhookStore
+	client      *http.Client
+
+	// Metrics, when set, counts delivery attempts by outcome
+	Metrics *metrics.Metrics
+	// AllowPrivateWebhooks accepts endpoint URLs that resolve to loopback or private addresses
+	AllowPrivateWebhooks bool
+}
+
+// NewWebhookService creates a new webhook service. transport may be nil to use
+// http.DefaultTransport.
+func NewWebhookService(webhookRepo repository.WebhookStore, timeout time.Duration, transport http.RoundTripper) *WebhookService {
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			// Endpoints must answer directly; a redirect would re-post the payload somewhere unsigned-for
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// GetEndpoints retrieves all webhook endpoints for a user
+func (s *WebhookService) GetEndpoints(ctx context.Context, userID uuid.UUID) ([]models.WebhookEndpoint, error) {
+	return s.webhookRepo.GetEndpointsByUserID(ctx, userID)
+}
+
+// CreateEndpoint registers a webhook endpoint with a freshly generated signing secret
+func (s *WebhookService) CreateEndpoint(ctx context.Context, userID uuid.UUID, input WebhookEndpointInput) (*models.WebhookEndpoint, error) {
+	if input.URL == nil {
+		return nil, errors.New("url is required")
+	}
+	if len(input.Events) == 0 {
+		return nil, errors.New("at least one event is required")
+	}
+
+	secret, err := utils.GenerateWebhookSecret()
+	if err != nil {
+		return nil, errors.New("failed to generate webhook secret")
+	}
+	endpoint := &models.WebhookEndpoint{
+		UserID:   userID,
+		Secret:   secret,
+		IsActive: true,
+	}
+	if err := s.applyWebhookInput(ctx, endpoint, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.webhookRepo.CreateEndpoint(ctx, endpoint); err != nil {
+		return nil, errors.New("failed to create webhook")
+	}
+	return endpoint, nil
+}
+
+// UpdateEndpoint applies a partial update to a user's webhook endpoint
+func (s *WebhookService) UpdateEndpoint(ctx context.Context, userID, id uuid.UUID, input WebhookEndpointInput) (*models.WebhookEndpoint, error) {
+	endpoint, err := s.getEndpoint(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if input.Events != nil && len(input.Events) == 0 {
+		return nil, errors.New("at least one event is required")
+	}
+	if err := s.applyWebhookInput(ctx, endpoint, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.webhookRepo.UpdateEndpoint(ctx, endpoint); err != nil {
+		return nil, errors.New("failed to update webhook")
+	}
+	return endpoint, nil
+}
+
+// DeleteEndpoint deletes a user's webhook endpoint along with its delivery log
+func (s *WebhookService) DeleteEndpoint(ctx context.Context, userID, id uuid.UUID) error {
+	deleted, err := s.webhookRepo.DeleteEndpoint(ctx, userID, id)
+	if err != nil {
+		return errors.New("failed to delete webhook")
+	}
+	if !deleted {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// GetDeliveries retrieves the recent delivery log of a user's webhook endpoint
+func (s *WebhookService) GetDeliveries(ctx context.Context, userID, endpointID uuid.UUID) ([]models.WebhookDelivery, error) {
+	if _, err := s.getEndpoint(ctx, userID, endpointID); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.GetDeliveries(ctx, endpointID, webhookDeliveryLogLimit)
+}
+
+// Redeliver queues a new delivery of a previous delivery's payload, leaving the original in the log
+func (s *WebhookService) Redeliver(ctx context.Context, userID, endpointID, deliveryID uuid.UUID) (*models.WebhookDelivery, error) {
+	if _, err := s.getEndpoint(ctx, userID, endpointID); err != nil {
+		return nil, err
+	}
+	original, err := s.webhookRepo.GetDelivery(ctx, endpointID, deliveryID)
+	if err != nil {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+
+	delivery := models.WebhookDelivery{
+		EndpointID:    endpointID,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: time.Now(),
+	}
+	deliveries := []models.WebhookDelivery{delivery}
+	if err := s.webhookRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		return nil, errors.New("failed to queue redelivery")
+	}
+	return &deliveries[0], nil
+}
+
+// Publish queues an event for every active endpoint of the user subscribed to it. Failures are
+// logged rather than returned so callers on the request path aren't affected.
+func (s *WebhookService) Publish(ctx context.Context, userID uuid.UUID, event string, data interface{}) {
+	endpoints, err := s.webhookRepo.GetActiveEndpoints(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load webhooks", "user_id", userID, "error", err)
+		return
+	}
+
+	var deliveries []models.WebhookDelivery
+	var payload []byte
+	for _, endpoint := range endpoints {
+		if !endpoint.Subscribes(event) {
+			continue
+		}
+		if payload == nil {
+			payload, err = json.Marshal(WebhookEvent{
+				ID:        uuid.New(),
+				Type:      event,
+				CreatedAt: time.Now().UTC(),
+				Data:      data,
+			})
+			if err != nil {
+				slog.ErrorContext(ctx, "Failed to encode webhook payload", "event", event, "error", err)
+				return
+			}
+		}
+		deliveries = append(deliveries, models.WebhookDelivery{
+			EndpointID:    endpoint.ID,
+			Event:         event,
+			Payload:       payload,
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: time.Now(),
+		})
+	}
+
+	if err := s.webhookRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		slog.ErrorContext(ctx, "Failed to queue webhooks", "event", event, "user_id", userID, "error", err)
+	}
+}
+
+// DeliverDue attempts up to limit pending deliveries that are due, returning how many were attempted
+func (s *WebhookService) DeliverDue(ctx context.Context, limit int) (int, error) {
+	// Attempts run one after another, so the claim must outlast a batch of timed-out posts
+	lease := time.Duration(limit)*s.client.Timeout + time.Minute
+	deliveries, err := s.webhookRepo.ClaimDueDeliveries(ctx, time.Now(), lease, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	attempted := 0
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			break
+		}
+		s.attempt(ctx, &deliveries[i])
+		if ctx.Err() == nil {
+			attempted++
+		}
+	}
+	return attempted, nil
+}
+
+// attempt posts a delivery to its endpoint and records the outcome, scheduling a retry with
+// exponential backoff on failure
+func (s *WebhookService) attempt(ctx context.Context, delivery *models.WebhookDelivery) {
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastStatusCode = nil
+	delivery.LastError = nil
+
+	statusCode, err := s.post(ctx, &delivery.Endpoint, delivery, now)
+	if err != nil && ctx.Err() != nil {
+		// Interrupted by shutdown; leave the delivery pending so it's retried, without using an
+		// attempt, once the claim lapses
+		return
+	}
+	if statusCode != 0 {
+		delivery.LastStatusCode = &statusCode
+	}
+
+	switch {
+	case err == nil:
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+		s.Metrics.WebhookDelivery(metrics.WebhookDelivered)
+	case delivery.Attempts >= webhookMaxAttempts || !delivery.Endpoint.IsActive:
+		message := err.Error()
+		delivery.LastError = &message
+		delivery.Status = models.WebhookDeliveryFailed
+		s.Metrics.WebhookDelivery(metrics.WebhookFailed)
+	default:
+		message := err.Error()
+		delivery.LastError = &message
+		delivery.NextAttemptAt = now.Add(webhookBackoff(delivery.Attempts))
+		s.Metrics.WebhookDelivery(metrics.WebhookRetrying)
+	}
+
+	// The endpoint has answered; record the outcome even if shutdown has begun
+	if err := s.webhookRepo.UpdateDelivery(context.WithoutCancel(ctx), delivery); err != nil {
+		slog.ErrorContext(ctx, "Failed to record webhook delivery", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// post sends a signed delivery, treating any non-2xx response as a failure
+func (s *WebhookService) post(ctx context.Context, endpoint *models.WebhookEndpoint, delivery *models.WebhookDelivery, now time.Time) (int, error) {
+	if !endpoint.IsActive {
+		return 0, errors.New("webhook endpoint is disabled")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "LinkVault-Webhooks/1.0")
+	req.Header.Set("X-LinkVault-Event", delivery.Event)
+	req.Header.Set("X-LinkVault-Delivery", delivery.ID.String())
+	req.Header.Set("X-LinkVault-Signature", utils.SignWebhookPayload(endpoint.Secret, now, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint returned HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// getEndpoint retrieves a webhook endpoint owned by a user
+func (s *WebhookService) getEndpoint(ctx context.Context, userID, id uuid.UUID) (*models.WebhookEndpoint, error) {
+	endpoint, err := s.webhookRepo.GetEndpoint(ctx, userID, id)
+	if err != nil {
+		return nil, ErrWebhookNotFound
+	}
+	return endpoint, nil
+}
+
+// webhookBackoff returns the delay before the retry following the given attempt
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := 1; i < attempts && backoff < webhookMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > webhookMaxBackoff {
+		return webhookMaxBackoff
+	}
+	return backoff
+}
+
+// applyWebhookInput validates and copies the non-nil fields of input onto endpoint
+func (s *WebhookService) applyWebhookInput(ctx context.Context, endpoint *models.WebhookEndpoint, input WebhookEndpointInput) error {
+	if input.URL != nil {
+		if err := validateDestinationURL(*input.URL); err != nil {
+			return fmt.Errorf("invalid webhook url %q", *input.URL)
+		}
+		if !s.AllowPrivateWebhooks {
+			if err := netguard.CheckURL(ctx, *input.URL); err != nil {
+				return fmt.Errorf("invalid webhook url %q: %w", *input.URL, err)
+			}
+		}
+		endpoint.URL = *input.URL
+	}
+	if input.Description != nil {
+		endpoint.Description = input.Description
+	}
+	if input.Events != nil {
+		events := make([]string, 0, len(input.Events))
+		for _, event := range input.Events {
+			if !isWebhookEvent(event) {
+				return fmt.Errorf("unknown webhook event %q", event)
+			}
+			if !containsString(events, event) {
+				events = append(events, event)
+			}
+		}
+		endpoint.Events = events
+	}
+	if input.IsActive != nil {
+		endpoint.IsActive = *input.IsActive
+	}
+	return nil
+}
+
+// isWebhookEvent checks if event is a supported webhook event type
+func isWebhookEvent(event string) bool {
+	return containsString(models.WebhookEvents, event)
+}
+
+// containsString checks if values contains s
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
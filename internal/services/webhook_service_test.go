@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/models"
+	"github.com/1shoukr/linkvault/internal/netguard"
+)
+
+func TestApplyWebhookInputRejectsPrivateURLs(t *testing.T) {
+	tests := []struct {
+		url          string
+		allowPrivate bool
+		wantPrivate  bool
+	}{
+		{url: "https://93.184.216.34/hooks/linkvault"},
+		{url: "http://127.0.0.1:9000/hook", wantPrivate: true},
+		{url: "http://169.254.169.254/latest/meta-data/", wantPrivate: true},
+		{url: "http://10.0.0.5/hook", wantPrivate: true},
+		{url: "http://[::1]/hook", wantPrivate: true},
+		{url: "http://127.0.0.1:9000/hook", allowPrivate: true},
+	}
+
+	for _, tt := range tests {
+		s := NewWebhookService(nil, time.Second, nil)
+		s.AllowPrivateWebhooks = tt.allowPrivate
+		url := tt.url
+
+		err := s.applyWebhookInput(context.Background(), &models.WebhookEndpoint{}, WebhookEndpointInput{URL: &url})
+		if got := errors.Is(err, netguard.ErrPrivateAddress); got != tt.wantPrivate {
+			t.Errorf("applyWebhookInput(%s, allowPrivate=%v) = %v, want private=%v", tt.url, tt.allowPrivate, err, tt.wantPrivate)
+		}
+		if !tt.wantPrivate && err != nil {
+			t.Errorf("applyWebhookInput(%s) = %v, want nil", tt.url, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package tracing_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/1shoukr/linkvault/internal/repository"
+	"github.com/1shoukr/linkvault/internal/tracing"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// dryRunDB opens a gorm DB that builds statements without connecting to Postgres
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	connConfig, err := pgx.ParseConfig("postgres://linkvault@127.0.0.1:1/linkvault")
+	if err != nil {
+		t.Fatalf("parsing DSN: %v", err)
+	}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: stdlib.OpenDB(*connConfig)}), &gorm.Config{
+		Logger:                 logger.Discard,
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+	})
+	if err != nil {
+		t.Fatalf("opening dry-run DB: %v", err)
+	}
+	return db
+}
+
+func TestGormSpansAreChildrenOfTheRequestSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	db := dryRunDB(t)
+	if err := db.Use(tracing.NewGormPlugin(tp)); err != nil {
+		t.Fatalf("registering the GORM plugin: %v", err)
+	}
+	users := repository.NewUserRepository(db)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(otelgin.Middleware("linkvault-test", otelgin.WithTracerProvider(tp), otelgin.WithPropagators(tracing.Propagator)))
+	router.GET("/users/:id", func(c *gin.Context) {
+		if _, err := users.GetByID(c.Request.Context(), uuid.New()); err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/"+uuid.NewString(), nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /users/:id = %d, want 200", rec.Code)
+	}
+
+	var request, query sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		switch span.SpanKind() {
+		case trace.SpanKindServer:
+			request = span
+		case trace.SpanKindClient:
+			query = span
+		}
+	}
+	if request == nil || query == nil {
+		t.Fatalf("recorded %d spans, want a server span and a GORM client span", len(recorder.Ended()))
+	}
+	if query.Name() != "db.query users" {
+		t.Fatalf("GORM span name = %q, want %q", query.Name(), "db.query users")
+	}
+	if query.Parent().SpanID() != request.SpanContext().SpanID() || query.SpanContext().TraceID() != request.SpanContext().TraceID() {
+		t.Fatalf("GORM span parent = %s in trace %s, want the request span %s in trace %s",
+			query.Parent().SpanID(), query.SpanContext().TraceID(), request.SpanContext().SpanID(), request.SpanContext().TraceID())
+	}
+}
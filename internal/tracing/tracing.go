@@ -0,0 +1,87 @@
+// Package tracing sets up optional OpenTelemetry tracing. With OTEL_TRACES_EXPORTER unset or
+// "none" every tracer is a no-op; with "otlp" spans are exported over OTLP/HTTP, configured by
+// the standard OTEL_EXPORTER_OTLP_* variables (endpoint, headers, timeout) and sampled per
+// OTEL_TRACES_SAMPLER.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/1shoukr/linkvault/internal/buildinfo"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// ScopeName identifies the spans created by this application's own instrumentation
+const ScopeName = "github.com/1shoukr/linkvault"
+
+// Exporters
+const (
+	ExporterNone = "none"
+	ExporterOTLP = "otlp"
+)
+
+// Propagator reads W3C trace context and baggage from incoming requests. Instrumentation is
+// given it explicitly, so traces propagate even where Setup hasn't installed it globally.
+var Propagator propagation.TextMapPropagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Setup builds the tracer provider selected by exporter and installs it, with Propagator, as the
+// global provider. The returned shutdown flushes buffered spans and must be called before the
+// process exits.
+func Setup(ctx context.Context, exporter, serviceName string) (trace.TracerProvider, func(context.Context) error, error) {
+	otel.SetTextMapPropagator(Propagator)
+
+	switch strings.ToLower(exporter) {
+	case "", ExporterNone:
+		return noop.NewTracerProvider(), func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+	default:
+		return nil, nil, fmt.Errorf("unsupported trace exporter %q (want %q or %q)", exporter, ExporterOTLP, ExporterNone)
+	}
+
+	spanExporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(
+			semconv.ServiceName(serviceName),
+			semconv.ServiceVersion(buildinfo.Version),
+		),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to describe trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider, provider.Shutdown, nil
+}
+
+// Transport wraps base (http.DefaultTransport when nil) so every outbound request gets a client
+// span. Trace headers are not sent: checks and webhooks go to third-party hosts, and a
+// traceparent header would both leak our trace IDs and mark the checker as a bot.
+func Transport(tp trace.TracerProvider, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base,
+		otelhttp.WithTracerProvider(tp),
+		otelhttp.WithPropagators(propagation.NewCompositeTextMapPropagator()),
+	)
+}
@@ -0,0 +1,64 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/health"
+	"github.com/1shoukr/linkvault/internal/logging"
+	"github.com/1shoukr/linkvault/internal/services"
+)
+
+// AlertDigestWorker periodically delivers batched link alerts and weekly chat summaries
+type AlertDigestWorker struct {
+	alertService *services.AlertService
+	interval     time.Duration
+
+	// Heartbeat, when set, is beaten after every pass so readiness can spot a stuck worker
+	Heartbeat *health.Heartbeat
+}
+
+// NewAlertDigestWorker creates a new alert digest worker
+func NewAlertDigestWorker(alertService *services.AlertService, interval time.Duration) *AlertDigestWorker {
+	return &AlertDigestWorker{
+		alertService: alertService,
+		interval:     interval,
+	}
+}
+
+// Run sends due digests every interval until ctx is cancelled
+func (w *AlertDigestWorker) Run(ctx context.Context) {
+	ctx = logging.With(ctx, slog.String("worker", "alert_digest"))
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.runOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *AlertDigestWorker) runOnce(ctx context.Context) {
+	defer w.Heartbeat.Beat()
+
+	sent, err := w.alertService.SendDigests(ctx, time.Now())
+	if err != nil {
+		slog.ErrorContext(ctx, "Alert digest pass failed", "error", err)
+	}
+	if sent > 0 {
+		slog.InfoContext(ctx, "Alert digests sent", "sent", sent)
+	}
+
+	summaries, err := w.alertService.SendWeeklySummaries(ctx, time.Now())
+	if err != nil {
+		slog.ErrorContext(ctx, "Weekly summary pass failed", "error", err)
+	}
+	if summaries > 0 {
+		slog.InfoContext(ctx, "Weekly summaries sent", "sent", summaries)
+	}
+}
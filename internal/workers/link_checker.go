@@ -0,0 +1,60 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/health"
+	"github.com/1shoukr/linkvault/internal/logging"
+	"github.com/1shoukr/linkvault/internal/services"
+)
+
+// LinkCheckWorker periodically health-checks links that are due
+type LinkCheckWorker struct {
+	checkService *services.CheckService
+	pollInterval time.Duration
+	batchSize    int
+	concurrency  int
+
+	// Heartbeat, when set, is beaten after every pass so readiness can spot a stuck worker
+	Heartbeat *health.Heartbeat
+}
+
+// NewLinkCheckWorker creates a new link check worker
+func NewLinkCheckWorker(checkService *services.CheckService, pollInterval time.Duration, batchSize, concurrency int) *LinkCheckWorker {
+	return &LinkCheckWorker{
+		checkService: checkService,
+		pollInterval: pollInterval,
+		batchSize:    batchSize,
+		concurrency:  concurrency,
+	}
+}
+
+// Run checks due links every poll interval until ctx is cancelled
+func (w *LinkCheckWorker) Run(ctx context.Context) {
+	ctx = logging.With(ctx, slog.String("worker", "link_checker"))
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		w.runOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *LinkCheckWorker) runOnce(ctx context.Context) {
+	defer w.Heartbeat.Beat()
+
+	checked, err := w.checkService.CheckDueLinks(ctx, w.batchSize, w.concurrency)
+	if err != nil {
+		slog.ErrorContext(ctx, "Link check pass failed", "error", err)
+	}
+	if checked > 0 {
+		slog.InfoContext(ctx, "Link check pass complete", "checked", checked)
+	}
+}
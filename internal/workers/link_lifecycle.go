@@ -0,0 +1,60 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/health"
+	"github.com/1shoukr/linkvault/internal/logging"
+	"github.com/1shoukr/linkvault/internal/services"
+)
+
+// LinkLifecycleWorker periodically moves links through scheduled → active → expired → archived
+type LinkLifecycleWorker struct {
+	linkService  *services.LinkService
+	interval     time.Duration
+	archiveAfter time.Duration
+
+	// Heartbeat, when set, is beaten after every pass so readiness can spot a stuck worker
+	Heartbeat *health.Heartbeat
+}
+
+// NewLinkLifecycleWorker creates a new link lifecycle worker
+func NewLinkLifecycleWorker(linkService *services.LinkService, interval, archiveAfter time.Duration) *LinkLifecycleWorker {
+	return &LinkLifecycleWorker{
+		linkService:  linkService,
+		interval:     interval,
+		archiveAfter: archiveAfter,
+	}
+}
+
+// Run transitions link statuses every interval until ctx is cancelled
+func (w *LinkLifecycleWorker) Run(ctx context.Context) {
+	ctx = logging.With(ctx, slog.String("worker", "link_lifecycle"))
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.runOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *LinkLifecycleWorker) runOnce(ctx context.Context) {
+	defer w.Heartbeat.Beat()
+
+	result, err := w.linkService.TransitionLifecycles(ctx, time.Now(), w.archiveAfter)
+	if err != nil {
+		slog.ErrorContext(ctx, "Link lifecycle pass failed", "error", err)
+		return
+	}
+	if result.Activated+result.Expired+result.Archived > 0 {
+		slog.InfoContext(ctx, "Link lifecycle pass complete",
+			"activated", result.Activated, "expired", result.Expired, "archived", result.Archived)
+	}
+}
@@ -0,0 +1,56 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/health"
+	"github.com/1shoukr/linkvault/internal/logging"
+	"github.com/1shoukr/linkvault/internal/services"
+)
+
+// UptimeRollupWorker periodically rolls completed days of check history into daily rollups
+type UptimeRollupWorker struct {
+	uptimeService *services.UptimeService
+	interval      time.Duration
+
+	// Heartbeat, when set, is beaten after every pass so readiness can spot a stuck worker
+	Heartbeat *health.Heartbeat
+}
+
+// NewUptimeRollupWorker creates a new uptime rollup worker
+func NewUptimeRollupWorker(uptimeService *services.UptimeService, interval time.Duration) *UptimeRollupWorker {
+	return &UptimeRollupWorker{
+		uptimeService: uptimeService,
+		interval:      interval,
+	}
+}
+
+// Run rolls up check history every interval until ctx is cancelled
+func (w *UptimeRollupWorker) Run(ctx context.Context) {
+	ctx = logging.With(ctx, slog.String("worker", "uptime_rollup"))
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.runOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *UptimeRollupWorker) runOnce(ctx context.Context) {
+	defer w.Heartbeat.Beat()
+
+	days, err := w.uptimeService.RollupDays(ctx, time.Now())
+	if err != nil {
+		slog.ErrorContext(ctx, "Uptime rollup pass failed", "error", err)
+	}
+	if days > 0 {
+		slog.InfoContext(ctx, "Uptime rollup pass complete", "days", days)
+	}
+}
@@ -0,0 +1,58 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/1shoukr/linkvault/internal/health"
+	"github.com/1shoukr/linkvault/internal/logging"
+	"github.com/1shoukr/linkvault/internal/services"
+)
+
+// WebhookDeliveryWorker drains the outbound webhook queue, including scheduled retries
+type WebhookDeliveryWorker struct {
+	webhookService *services.WebhookService
+	interval       time.Duration
+	batchSize      int
+
+	// Heartbeat, when set, is beaten after every pass so readiness can spot a stuck worker
+	Heartbeat *health.Heartbeat
+}
+
+// NewWebhookDeliveryWorker creates a new webhook delivery worker
+func NewWebhookDeliveryWorker(webhookService *services.WebhookService, interval time.Duration, batchSize int) *WebhookDeliveryWorker {
+	return &WebhookDeliveryWorker{
+		webhookService: webhookService,
+		interval:       interval,
+		batchSize:      batchSize,
+	}
+}
+
+// Run delivers due webhooks every interval until ctx is cancelled
+func (w *WebhookDeliveryWorker) Run(ctx context.Context) {
+	ctx = logging.With(ctx, slog.String("worker", "webhook_delivery"))
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.runOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *WebhookDeliveryWorker) runOnce(ctx context.Context) {
+	defer w.Heartbeat.Beat()
+
+	attempted, err := w.webhookService.DeliverDue(ctx, w.batchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "Webhook delivery pass failed", "error", err)
+	}
+	if attempted > 0 {
+		slog.InfoContext(ctx, "Webhook delivery pass complete", "attempted", attempted)
+	}
+}
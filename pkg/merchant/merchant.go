@@ -0,0 +1,86 @@
+// Package merchant recognizes affiliate network and storefront URL shapes and extracts
+// the merchant and affiliate identifiers they carry.
+package merchant
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Affiliate networks recognized by Detect
+const (
+	NetworkAmazon     = "amazon"
+	NetworkShareASale = "shareasale"
+	NetworkImpact     = "impact"
+	NetworkCJ         = "cj"
+	NetworkRakuten    = "rakuten"
+	NetworkLTK        = "ltk"
+	NetworkShopify    = "shopify"
+)
+
+// Networks lists every network Detect can recognize
+var Networks = []string{NetworkAmazon, NetworkShareASale, NetworkImpact, NetworkCJ, NetworkRakuten, NetworkLTK, NetworkShopify}
+
+// IsKnownNetwork reports whether network is one of the recognized networks
+func IsKnownNetwork(network string) bool {
+	for _, n := range Networks {
+		if n == network {
+			return true
+		}
+	}
+	return false
+}
+
+// Result describes what was detected in a URL
+type Result struct {
+	Network     string `json:"network"`      // one of the Network* constants
+	Merchant    string `json:"merchant"`     // merchant name or ID, when the URL carries one
+	AffiliateID string `json:"affiliate_id"` // publisher tag/ID, "" when missing
+	Destination string `json:"destination"`  // deep-linked product URL, when wrapped by the network
+	ShortLink   bool   `json:"short_link"`   // opaque short link whose tag is only visible once resolved
+}
+
+// detector inspects a parsed URL with a lower-cased host and reports a match
+type detector func(u *url.URL, host string) (*Result, bool)
+
+var detectors = []detector{
+	detectAmazon,
+	detectShareASale,
+	detectImpact,
+	detectCJ,
+	detectRakuten,
+	detectLTK,
+	detectShopify,
+}
+
+// Detect identifies the affiliate network of rawURL. It returns false for unrecognized URLs.
+func Detect(rawURL string) (*Result, bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return nil, false
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+
+	for _, detect := range detectors {
+		if result, ok := detect(u, host); ok {
+			return result, true
+		}
+	}
+	return nil, false
+}
+
+// hostMatches reports whether host is domain or a subdomain of it
+func hostMatches(host, domain string) bool {
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// pathSegments splits a URL path into its non-empty segments
+func pathSegments(u *url.URL) []string {
+	var segments []string
+	for _, s := range strings.Split(u.Path, "/") {
+		if s != "" {
+			segments = append(segments, s)
+		}
+	}
+	return segments
+}
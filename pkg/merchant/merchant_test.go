@@ -0,0 +1,123 @@
+package merchant_test
+
+import (
+	"testing"
+
+	"github.com/1shoukr/linkvault/pkg/merchant"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		want   merchant.Result
+		wantOK bool
+	}{
+		{
+			name:   "Amazon with a tag",
+			url:    "https://www.amazon.co.uk/dp/B08N5WRWNW?tag=creator-21&th=1",
+			want:   merchant.Result{Network: merchant.NetworkAmazon, Merchant: "amazon.co.uk", AffiliateID: "creator-21"},
+			wantOK: true,
+		},
+		{
+			name:   "Amazon short link",
+			url:    "https://amzn.to/3xYzAbC",
+			want:   merchant.Result{Network: merchant.NetworkAmazon, Merchant: "amazon", ShortLink: true},
+			wantOK: true,
+		},
+		{
+			name: "ShareASale deep link without a scheme",
+			url:  "https://shareasale.com/r.cfm?b=1234&u=998877&m=4567&urllink=www.example-store.com%2Fproducts%2Flamp",
+			want: merchant.Result{
+				Network:     merchant.NetworkShareASale,
+				Merchant:    "4567",
+				AffiliateID: "998877",
+				Destination: "https://www.example-store.com/products/lamp",
+			},
+			wantOK: true,
+		},
+		{
+			name: "Impact campaign path",
+			url:  "https://brand.sjv.io/c/1234567/890123/4567?u=https%3A%2F%2Fbrand.com%2Fshoes",
+			want: merchant.Result{
+				Network:     merchant.NetworkImpact,
+				Merchant:    "brand",
+				AffiliateID: "1234567",
+				Destination: "https://brand.com/shoes",
+			},
+			wantOK: true,
+		},
+		{
+			name:   "Impact campaign path on a shared domain",
+			url:    "https://7eer.net/c/1234567/890123/4567",
+			want:   merchant.Result{Network: merchant.NetworkImpact, Merchant: "4567", AffiliateID: "1234567"},
+			wantOK: true,
+		},
+		{
+			name: "CJ click link",
+			url:  "https://www.anrdoezrs.net/click-8765432-13579246?url=https%3A%2F%2Fshop.example.com%2Fitem",
+			want: merchant.Result{
+				Network:     merchant.NetworkCJ,
+				Merchant:    "13579246",
+				AffiliateID: "8765432",
+				Destination: "https://shop.example.com/item",
+			},
+			wantOK: true,
+		},
+		{
+			name: "CJ deep link",
+			url:  "https://www.jdoqocy.com/links/8765432/type/dlg/https://shop.example.com/item",
+			want: merchant.Result{
+				Network:     merchant.NetworkCJ,
+				AffiliateID: "8765432",
+				Destination: "https://shop.example.com/item",
+			},
+			wantOK: true,
+		},
+		{
+			name: "Rakuten deep link",
+			url:  "https://click.linksynergy.com/deeplink?id=AbCdEf123&mid=24449&murl=https%3A%2F%2Fwww.store.com%2Fp%2F42",
+			want: merchant.Result{
+				Network:     merchant.NetworkRakuten,
+				Merchant:    "24449",
+				AffiliateID: "AbCdEf123",
+				Destination: "https://www.store.com/p/42",
+			},
+			wantOK: true,
+		},
+		{
+			name:   "LTK token",
+			url:    "https://rstyle.me/+AbC123xYz",
+			want:   merchant.Result{Network: merchant.NetworkLTK, AffiliateID: "AbC123xYz", ShortLink: true},
+			wantOK: true,
+		},
+		{
+			name:   "Shopify referral",
+			url:    "https://cool-store.myshopify.com/products/mug?ref=creator10",
+			want:   merchant.Result{Network: merchant.NetworkShopify, Merchant: "cool-store", AffiliateID: "creator10"},
+			wantOK: true,
+		},
+		{
+			name:   "Shopify discount link",
+			url:    "https://cool-store.myshopify.com/discount/SAVE15?redirect=/products/mug",
+			want:   merchant.Result{Network: merchant.NetworkShopify, Merchant: "cool-store", AffiliateID: "SAVE15"},
+			wantOK: true,
+		},
+		{name: "lookalike host", url: "https://evilshareasale.com/r.cfm?u=998877&m=4567"},
+		{name: "lookalike Amazon host", url: "https://amazon.com.evil.example/dp/B08N5WRWNW?tag=creator-21"},
+		{name: "unknown store", url: "https://example.com/products/mug?ref=creator10"},
+		{name: "not a URL", url: "amazon.com/dp/B08N5WRWNW"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := merchant.Detect(tt.url)
+			if ok != tt.wantOK {
+				t.Fatalf("Detect(%q) ok = %v, want %v (result %+v)", tt.url, ok, tt.wantOK, got)
+			}
+			if ok && *got != tt.want {
+				t.Fatalf("Detect(%q) = %+v, want %+v", tt.url, *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package merchant
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// amazonHost matches Amazon retail storefronts such as amazon.com, amazon.co.uk or amazon.com.au
+var amazonHost = regexp.MustCompile(`^(smile\.)?amazon\.(com|ca|com\.mx|com\.br|co\.uk|de|fr|it|es|nl|se|pl|com\.be|com\.tr|ae|sa|eg|in|co\.jp|sg|com\.au)$`)
+
+func detectAmazon(u *url.URL, host string) (*Result, bool) {
+	switch {
+	case amazonHost.MatchString(host):
+		return &Result{
+			Network:     NetworkAmazon,
+			Merchant:    strings.TrimPrefix(host, "smile."),
+			AffiliateID: u.Query().Get("tag"),
+		}, true
+	case host == "amzn.to" || host == "amzn.eu" || host == "a.co":
+		// Short links hide the tag until resolved
+		return &Result{Network: NetworkAmazon, Merchant: "amazon", ShortLink: true}, true
+	}
+	return nil, false
+}
+
+// ShareASale: shareasale.com/r.cfm?b=<banner>&u=<affiliate>&m=<merchant>&urllink=<deep link>
+func detectShareASale(u *url.URL, host string) (*Result, bool) {
+	if !hostMatches(host, "shareasale.com") {
+		return nil, false
+	}
+	q := u.Query()
+	return &Result{
+		Network:     NetworkShareASale,
+		Merchant:    q.Get("m"),
+		AffiliateID: q.Get("u"),
+		Destination: withScheme(q.Get("urllink")),
+	}, true
+}
+
+// impactHosts are the tracking domains Impact assigns to brands (e.g. brand.sjv.io)
+var impactHosts = []string{"sjv.io", "pxf.io", "7eer.net", "evyy.net", "ojrq.net", "mkr3.net", "vanity.impact.com"}
+
+// Impact: <brand>.sjv.io/c/<affiliate>/<ad>/<campaign>?u=<deep link>
+func detectImpact(u *url.URL, host string) (*Result, bool) {
+	matched := false
+	for _, domain := range impactHosts {
+		if hostMatches(host, domain) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return nil, false
+	}
+
+	result := &Result{Network: NetworkImpact, Destination: u.Query().Get("u")}
+	if label, _, ok := strings.Cut(host, "."); ok && strings.Count(host, ".") > 1 {
+		result.Merchant = label
+	}
+	if segments := pathSegments(u); len(segments) >= 4 && segments[0] == "c" {
+		result.AffiliateID = segments[1]
+		if result.Merchant == "" {
+			result.Merchant = segments[3]
+		}
+	}
+	return result, true
+}
+
+// cjHosts are Commission Junction's click-tracking domains
+var cjHosts = []string{"anrdoezrs.net", "jdoqocy.com", "tkqlhce.com", "dpbolvw.net", "kqzyfj.com", "emjcd.com", "qksrv.net", "ftjcfx.com", "lduhtrp.net", "afcyhf.com", "awltovhc.com", "apmebf.com"}
+
+// cjClickPath matches /click-<publisher>-<ad> paths
+var cjClickPath = regexp.MustCompile(`^/click-(\d+)-(\d+)`)
+
+// CJ: www.anrdoezrs.net/click-<publisher>-<ad>?url=... or /links/<publisher>/type/dlg/<deep link>
+func detectCJ(u *url.URL, host string) (*Result, bool) {
+	matched := false
+	for _, domain := range cjHosts {
+		if hostMatches(host, domain) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return nil, false
+	}
+
+	result := &Result{Network: NetworkCJ, Destination: u.Query().Get("url")}
+	if m := cjClickPath.FindStringSubmatch(u.Path); m != nil {
+		result.AffiliateID = m[1]
+		result.Merchant = m[2]
+		return result, true
+	}
+	segments := pathSegments(u)
+	if len(segments) >= 2 && segments[0] == "links" {
+		result.AffiliateID = segments[1]
+		if idx := strings.Index(u.Path, "/dlg/"); idx >= 0 && result.Destination == "" {
+			result.Destination = u.Path[idx+len("/dlg/"):]
+		}
+	}
+	return result, true
+}
+
+// Rakuten: click.linksynergy.com/deeplink?id=<affiliate>&mid=<merchant>&murl=<deep link>
+func detectRakuten(u *url.URL, host string) (*Result, bool) {
+	if !hostMatches(host, "linksynergy.com") {
+		return nil, false
+	}
+	q := u.Query()
+	return &Result{
+		Network:     NetworkRakuten,
+		Merchant:    q.Get("mid"),
+		AffiliateID: q.Get("id"),
+		Destination: q.Get("murl"),
+	}, true
+}
+
+// LTK (LIKEtoKNOW.it / rewardStyle): liketk.it/<code>, rstyle.me/+<token>, shopltk.com/explore/<creator>
+func detectLTK(u *url.URL, host string) (*Result, bool) {
+	switch {
+	case host == "liketk.it" || host == "rstyle.me":
+		result := &Result{Network: NetworkLTK, ShortLink: true}
+		if segments := pathSegments(u); len(segments) > 0 {
+			result.AffiliateID = strings.TrimPrefix(segments[0], "+")
+		}
+		return result, true
+	case hostMatches(host, "shopltk.com") || hostMatches(host, "liketoknow.it"):
+		result := &Result{Network: NetworkLTK}
+		if segments := pathSegments(u); len(segments) >= 2 && segments[0] == "explore" {
+			result.AffiliateID = segments[1]
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+// shopifyRefParams are the referral parameters used by Shopify Collabs and common affiliate apps
+var shopifyRefParams = []string{"ref", "sca_ref", "aff", "affiliate"}
+
+// Shopify: <store>.myshopify.com/products/<handle>?ref=<code>, or a /discount/<code> share link.
+// Stores on custom domains can't be recognized from the URL alone.
+func detectShopify(u *url.URL, host string) (*Result, bool) {
+	if !hostMatches(host, "myshopify.com") && !hostMatches(host, "shop.app") {
+		return nil, false
+	}
+
+	result := &Result{Network: NetworkShopify}
+	if hostMatches(host, "myshopify.com") {
+		result.Merchant = strings.TrimSuffix(host, ".myshopify.com")
+	}
+	q := u.Query()
+	for _, param := range shopifyRefParams {
+		if value := q.Get(param); value != "" {
+			result.AffiliateID = value
+			break
+		}
+	}
+	if segments := pathSegments(u); result.AffiliateID == "" && len(segments) >= 2 && segments[0] == "discount" {
+		result.AffiliateID = segments[1]
+	}
+	return result, true
+}
+
+// withScheme adds https:// to scheme-less deep links as ShareASale stores them
+func withScheme(raw string) string {
+	if raw == "" || strings.Contains(raw, "://") {
+		return raw
+	}
+	return "https://" + raw
+}
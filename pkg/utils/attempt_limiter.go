@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// AttemptLimiter counts attempts per key in fixed windows and blocks keys that exceed the limit.
+// It is in-memory, so limits apply per server instance.
+type AttemptLimiter struct {
+	mu       sync.Mutex
+	limit    int
+	window   time.Duration
+	attempts map[string]*attemptWindow
+}
+
+type attemptWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+// NewAttemptLimiter creates a limiter allowing limit attempts per key within each window
+func NewAttemptLimiter(limit int, window time.Duration) *AttemptLimiter {
+	return &AttemptLimiter{
+		limit:    limit,
+		window:   window,
+		attempts: make(map[string]*attemptWindow),
+	}
+}
+
+// Allow records an attempt for key and reports whether it is within the limit
+func (l *AttemptLimiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.evictExpired(now)
+
+	w, ok := l.attempts[key]
+	if !ok {
+		w = &attemptWindow{resetAt: now.Add(l.window)}
+		l.attempts[key] = w
+	}
+	w.count++
+	return w.count <= l.limit
+}
+
+// Exceeded reports whether key has used up its attempts in the current window, without recording one
+func (l *AttemptLimiter) Exceeded(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	w, ok := l.attempts[key]
+	return ok && !time.Now().After(w.resetAt) && w.count >= l.limit
+}
+
+// Reset clears the attempts recorded for key
+func (l *AttemptLimiter) Reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.attempts, key)
+}
+
+func (l *AttemptLimiter) evictExpired(now time.Time) {
+	for key, w := range l.attempts {
+		if now.After(w.resetAt) {
+			delete(l.attempts, key)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"net/http"
+	"strings"
+)
+
+// countryHeaders lists the headers edge proxies use to forward the visitor's country, in priority order
+var countryHeaders = []string{
+	"CF-IPCountry",              // Cloudflare
+	"CloudFront-Viewer-Country", // AWS CloudFront
+	"X-Vercel-IP-Country",       // Vercel
+	"Fastly-Client-Country",     // Fastly
+	"X-Country-Code",            // Generic / custom proxies
+}
+
+// ResolveCountry returns the visitor's ISO 3166-1 alpha-2 country code, or "" if unknown.
+// The headers are only read when trustProxyHeaders is set: without a proxy overwriting them,
+// any client can claim any country.
+func ResolveCountry(r *http.Request, trustProxyHeaders bool) string {
+	if !trustProxyHeaders {
+		return ""
+	}
+	for _, header := range countryHeaders {
+		if country := NormalizeCountry(r.Header.Get(header)); country != "" {
+			return country
+		}
+	}
+	return ""
+}
+
+// NormalizeCountry upper-cases a country code and rejects anything that isn't two letters.
+// Cloudflare's "XX" (unknown) and "T1" (Tor) placeholders are treated as unknown.
+func NormalizeCountry(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 2 || code == "XX" || code == "T1" {
+		return ""
+	}
+	for _, ch := range code {
+		if ch < 'A' || ch > 'Z' {
+			return ""
+		}
+	}
+	return code
+}
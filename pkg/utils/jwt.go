@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"crypto/hkdf"
+	"crypto/sha256"
 	"errors"
 	"time"
 
@@ -8,6 +10,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// DefaultTokenTTL is how long issued tokens stay valid
+const DefaultTokenTTL = 24 * time.Hour
+
 // JWTClaims represents the JWT token claims
 type JWTClaims struct {
 	UserID uuid.UUID `json:"user_id"`
@@ -15,48 +20,61 @@ type JWTClaims struct {
 	jwt.RegisteredClaims
 }
 
-var jwtSecret []byte
+// linkAccessKeyInfo labels the key derived from the JWT secret for link access tokens
+const linkAccessKeyInfo = "linkvault link access token"
+
+// TokenIssuer signs and validates user session tokens, and link access tokens with a key
+// derived from the same secret so neither kind validates as the other
+type TokenIssuer struct {
+	secret     []byte
+	linkSecret []byte
+	ttl        time.Duration
+	now        func() time.Time
+}
 
-// InitJWT initializes the JWT secret
-func InitJWT(secret string) {
+// NewTokenIssuer creates an issuer signing with secret; now defaults to time.Now
+func NewTokenIssuer(secret string, ttl time.Duration, now func() time.Time) (*TokenIssuer, error) {
 	if secret == "" {
-		panic("JWT_SECRET is required")
+		return nil, errors.New("JWT_SECRET is required")
+	}
+	if ttl <= 0 {
+		ttl = DefaultTokenTTL
+	}
+	if now == nil {
+		now = time.Now
+	}
+	linkSecret, err := hkdf.Key(sha256.New, []byte(secret), nil, linkAccessKeyInfo, sha256.Size)
+	if err != nil {
+		return nil, err
 	}
-	jwtSecret = []byte(secret)
+	return &TokenIssuer{secret: []byte(secret), linkSecret: linkSecret, ttl: ttl, now: now}, nil
 }
 
 // GenerateToken generates a JWT token for a user
-func GenerateToken(userID uuid.UUID, email string) (string, error) {
-	if len(jwtSecret) == 0 {
-		return "", errors.New("JWT secret not initialized")
-	}
-
+func (i *TokenIssuer) GenerateToken(userID uuid.UUID, email string) (string, error) {
+	now := i.now()
 	claims := JWTClaims{
 		UserID: userID,
 		Email:  email,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)), // 24 hours
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(i.ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	return token.SignedString(i.secret)
 }
 
 // ValidateToken validates a JWT token and returns the claims
-func ValidateToken(tokenString string) (*JWTClaims, error) {
-	if len(jwtSecret) == 0 {
-		return nil, errors.New("JWT secret not initialized")
-	}
-
+func (i *TokenIssuer) ValidateToken(tokenString string) (*JWTClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
 		}
-		return jwtSecret, nil
-	})
+		return i.secret, nil
+	}, jwt.WithTimeFunc(i.now))
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// LinkAccessClaims represents the claims of a time-limited access token for a protected link
+type LinkAccessClaims struct {
+	LinkID uuid.UUID `json:"link_id"`
+	jwt.RegisteredClaims
+}
+
+// GenerateLinkAccessToken generates a signed token granting access to a protected link until ttl elapses
+func (i *TokenIssuer) GenerateLinkAccessToken(linkID uuid.UUID, ttl time.Duration) (string, error) {
+	now := i.now()
+	claims := LinkAccessClaims{
+		LinkID: linkID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "link-access",
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(i.linkSecret)
+}
+
+// ValidateLinkAccessToken checks that a token is valid, unexpired and issued for the given link
+func (i *TokenIssuer) ValidateLinkAccessToken(tokenString string, linkID uuid.UUID) error {
+	token, err := jwt.ParseWithClaims(tokenString, &LinkAccessClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return i.linkSecret, nil
+	}, jwt.WithTimeFunc(i.now))
+	if err != nil {
+		return err
+	}
+
+	claims, ok := token.Claims.(*LinkAccessClaims)
+	if !ok || !token.Valid || claims.Subject != "link-access" || claims.LinkID != linkID {
+		return errors.New("invalid link access token")
+	}
+	return nil
+}
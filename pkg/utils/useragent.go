@@ -0,0 +1,29 @@
+package utils
+
+import "strings"
+
+// Device platforms recognized for deep-link routing
+const (
+	PlatformIOS     = "ios"
+	PlatformAndroid = "android"
+	PlatformDesktop = "desktop"
+)
+
+// DevicePlatform classifies a User-Agent string as ios, android or desktop.
+// Unknown agents (bots, CLI tools) are treated as desktop so they get the web destination.
+func DevicePlatform(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	switch {
+	case strings.Contains(ua, "android"):
+		return PlatformAndroid
+	case strings.Contains(ua, "iphone"), strings.Contains(ua, "ipad"), strings.Contains(ua, "ipod"):
+		return PlatformIOS
+	default:
+		return PlatformDesktop
+	}
+}
+
+// IsValidPlatform reports whether p is one of the recognized device platforms
+func IsValidPlatform(p string) bool {
+	return p == PlatformIOS || p == PlatformAndroid || p == PlatformDesktop
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+)
+
+// MergeQueryParams appends params to rawURL's query string, leaving any parameter the URL
+// already carries untouched. The rest of rawURL is kept byte for byte rather than re-encoded,
+// since some merchants sign or compare their URLs verbatim.
+func MergeQueryParams(rawURL string, params map[string]string) (string, error) {
+	if len(params) == 0 {
+		return rawURL, nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	existing := u.Query()
+	added := url.Values{}
+	for key, value := range params {
+		if existing.Has(key) || value == "" {
+			continue
+		}
+		added.Set(key, value)
+	}
+	if len(added) == 0 {
+		return rawURL, nil
+	}
+
+	base, fragment, hasFragment := strings.Cut(rawURL, "#")
+	switch {
+	case u.RawQuery == "" && !strings.HasSuffix(base, "?"):
+		base += "?"
+	case u.RawQuery != "" && !strings.HasSuffix(base, "&"):
+		base += "&"
+	}
+	base += added.Encode()
+	if hasFragment {
+		base += "#" + fragment
+	}
+	return base, nil
+}
+
+// ExpandTemplate replaces {name} placeholders with values from vars; unknown placeholders become empty
+func ExpandTemplate(template string, vars map[string]string) string {
+	if !strings.Contains(template, "{") {
+		return template
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		b.WriteString(template[:start])
+		b.WriteString(vars[template[start+1:start+end]])
+		template = template[start+end+1:]
+	}
+	b.WriteString(template)
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,36 @@
+package utils
+
+import "testing"
+
+func TestMergeQueryParams(t *testing.T) {
+	utm := map[string]string{"utm_source": "newsletter", "utm_campaign": "spring sale"}
+
+	tests := []struct {
+		name   string
+		rawURL string
+		params map[string]string
+		want   string
+	}{
+		{"no query", "https://shop.example.com/p/42", utm, "https://shop.example.com/p/42?utm_campaign=spring+sale&utm_source=newsletter"},
+		{"existing query keeps its encoding", "https://shop.example.com/p/42?q=a%2Cb&sig=AbC%3D%3D", utm, "https://shop.example.com/p/42?q=a%2Cb&sig=AbC%3D%3D&utm_campaign=spring+sale&utm_source=newsletter"},
+		{"escaped path kept", "https://shop.example.com/p/caf%C3%A9%20mug", utm, "https://shop.example.com/p/caf%C3%A9%20mug?utm_campaign=spring+sale&utm_source=newsletter"},
+		{"unescaped path kept", "https://shop.example.com/p/café", utm, "https://shop.example.com/p/café?utm_campaign=spring+sale&utm_source=newsletter"},
+		{"fragment stays last", "https://shop.example.com/p/42?color=red#reviews", utm, "https://shop.example.com/p/42?color=red&utm_campaign=spring+sale&utm_source=newsletter#reviews"},
+		{"fragment encoding kept", "https://shop.example.com/p/42#tab%2Fspecs", utm, "https://shop.example.com/p/42?utm_campaign=spring+sale&utm_source=newsletter#tab%2Fspecs"},
+		{"trailing question mark", "https://shop.example.com/p/42?", utm, "https://shop.example.com/p/42?utm_campaign=spring+sale&utm_source=newsletter"},
+		{"existing parameter wins", "https://shop.example.com/p/42?utm_source=instagram", utm, "https://shop.example.com/p/42?utm_source=instagram&utm_campaign=spring+sale"},
+		{"nothing to add", "https://shop.example.com/p/42?utm_source=x&utm_campaign=y", utm, "https://shop.example.com/p/42?utm_source=x&utm_campaign=y"},
+		{"empty values skipped", "https://shop.example.com/p/42", map[string]string{"utm_medium": ""}, "https://shop.example.com/p/42"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergeQueryParams(tt.rawURL, tt.params)
+			if err != nil {
+				t.Fatalf("MergeQueryParams: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("MergeQueryParams(%q) = %q, want %q", tt.rawURL, got, tt.want)
+			}
+		})
+	}
+}
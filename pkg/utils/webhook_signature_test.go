@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWebhookSignatureRoundTrip(t *testing.T) {
+	secret, err := GenerateWebhookSecret()
+	if err != nil {
+		t.Fatalf("GenerateWebhookSecret: %v", err)
+	}
+	sentAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"event":"link.unhealthy","link_id":"3f1c"}`)
+	header := SignWebhookPayload(secret, sentAt, body)
+
+	tests := []struct {
+		name    string
+		secret  string
+		header  string
+		body    []byte
+		now     time.Time
+		wantErr bool
+	}{
+		{name: "valid", secret: secret, header: header, body: body, now: sentAt.Add(time.Minute)},
+		{name: "tampered body", secret: secret, header: header, body: []byte(`{"event":"link.unhealthy","link_id":"9a0b"}`), now: sentAt, wantErr: true},
+		{name: "wrong secret", secret: "whsec_other", header: header, body: body, now: sentAt, wantErr: true},
+		{name: "expired timestamp", secret: secret, header: header, body: body, now: sentAt.Add(6 * time.Minute), wantErr: true},
+		{name: "timestamp from the future", secret: secret, header: header, body: body, now: sentAt.Add(-6 * time.Minute), wantErr: true},
+		{name: "malformed header", secret: secret, header: "v1=deadbeef", body: body, now: sentAt, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyWebhookSignature(tt.secret, tt.header, tt.body, 5*time.Minute, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyWebhookSignature = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}